@@ -119,6 +119,7 @@ func CreateMergeArgParser() *argparser.ArgParser {
 		return fmt.Errorf("Error: Dolt does not support merging from multiple commits. You probably meant to checkout one and then merge from the other.")
 	}
 	ap.SupportsFlag(NoFFParam, "", "Create a merge commit even when the merge resolves as a fast-forward.")
+	ap.SupportsFlag(AllowEmptyFlag, "", "Create the merge commit of a {{.EmphasisLeft}}--no-ff{{.EmphasisRight}} merge even if the merge doesn't change any data, such as to record that a branch was merged. Only valid with {{.EmphasisLeft}}--no-ff{{.EmphasisRight}}.")
 	ap.SupportsFlag(SquashParam, "", "Merge changes to the working set without updating the commit history")
 	ap.SupportsString(MessageArg, "m", "msg", "Use the given {{.LessThan}}msg{{.GreaterThan}} as the commit message.")
 	ap.SupportsFlag(AbortParam, "", mergeAbortDetails)
@@ -126,6 +127,19 @@ func CreateMergeArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(NoCommitFlag, "", "Perform the merge and stop just before creating a merge commit. Note this will not prevent a fast-forward merge; use the --no-ff arg together with the --no-commit arg to prevent both fast-forwards and merge commits.")
 	ap.SupportsFlag(NoEditFlag, "", "Use an auto-generated commit message when creating a merge commit. The default for interactive CLI sessions is to open an editor.")
 	ap.SupportsString(AuthorParam, "", "author", "Specify an explicit author using the standard A U Thor {{.LessThan}}author@example.com{{.GreaterThan}} format.")
+	ap.SupportsString(DateParam, "", "date", "Specify the date used in the merge commit. If not specified the current system time is used. Fast-forward merges don't create a commit, so the date is ignored for them.")
+	ap.SupportsString(MergeBaseParam, "", "ref", "Use {{.LessThan}}ref{{.GreaterThan}} as the ancestor of the three-way merge instead of the common ancestor of the two commits. The ref must be an ancestor of both commits unless {{.EmphasisLeft}}--force-merge-base{{.EmphasisRight}} is given. Fast-forward merges are not performed when a merge base is given.")
+	ap.SupportsFlag(ForceMergeBase, "", "Allow {{.EmphasisLeft}}--merge-base{{.EmphasisRight}} to name a commit that is not an ancestor of both commits being merged. A warning is issued instead of an error.")
+	ap.SupportsString(OnlyParam, "", "tables", "Only merge changes to the given comma-separated {{.LessThan}}tables{{.GreaterThan}}, leaving all other tables as they are on the current branch. Fast-forward merges are not performed when tables are given, and the merge is recorded like a {{.EmphasisLeft}}--squash{{.EmphasisRight}} merge, without the merged commit as a parent, so that the other tables can still be merged from it later.")
+	ap.SupportsString(PruneViolations, "", "types", "Delete rows that only violate constraints of the given comma-separated {{.LessThan}}types{{.GreaterThan}} during a three-way merge instead of recording the violations. Valid types are {{.EmphasisLeft}}foreign key{{.EmphasisRight}}, {{.EmphasisLeft}}unique index{{.EmphasisRight}}, {{.EmphasisLeft}}check constraint{{.EmphasisRight}} and {{.EmphasisLeft}}not null{{.EmphasisRight}}.")
+	ap.SupportsString(TextMergeParam, "", "columns", "Merge the cells of the given comma-separated TEXT {{.LessThan}}columns{{.GreaterThan}}, each named {{.EmphasisLeft}}table.column{{.EmphasisRight}}, line by line when both sides of a three-way merge change the same cell. Edits to different lines of the cell are combined, while edits to the same or adjacent lines remain a conflict.")
+	ap.SupportsFlag(NoGCHintFlag, "", "Keep the merge base and the two commits being merged from being collected by {{.EmphasisLeft}}dolt gc{{.EmphasisRight}}, so the exact inputs of the merge can be inspected or merged again later. They're kept by internal refs named {{.EmphasisLeft}}refs/internal/merge/{{.LessThan}}ours{{.GreaterThan}}/{{.LessThan}}theirs{{.GreaterThan}}/base{{.EmphasisRight}}, {{.EmphasisLeft}}.../ours{{.EmphasisRight}} and {{.EmphasisLeft}}.../theirs{{.EmphasisRight}}, after the hashes of the two commits. Only applies to merges that create a merge commit, and only the inputs of the 64 merges with the most recent ours commits are kept.")
+	ap.SupportsString(ResolveParam, "", "ours|theirs", "Resolve the data conflicts of a three-way merge by taking the version of each conflicting row from our branch ({{.EmphasisLeft}}ours{{.EmphasisRight}}) or their branch ({{.EmphasisLeft}}theirs{{.EmphasisRight}}). Schema conflicts and constraint violations are not resolved, and the merge fails if there are any.")
+	ap.SupportsString(ConflictBranchParam, "", "branch", "If a three-way merge results in conflicts or constraint violations, save the conflicted merge to the working set of a new branch named {{.LessThan}}branch{{.GreaterThan}}, started at the current commit, and leave the current branch as it was before the merge. It's an error if the branch already exists.")
+	ap.SupportsFlag(NoPagerFlag, "", "Print the merge commit's info without starting a pager. The pager is never started when stdout is not a terminal.")
+	ap.SupportsFlag(QuietFlag, "q", "Don't print the merge commit's info.")
+	ap.SupportsFlag(IncludeSystemTables, "", "Include changes to system tables stored in the database, such as {{.EmphasisLeft}}dolt_docs{{.EmphasisRight}} and {{.EmphasisLeft}}dolt_query_catalog{{.EmphasisRight}}, in the merge summary. They're left out by default.")
+	ap.SupportsFlag(SummaryLineFlag, "", "Print the merge summary as a single line, such as {{.EmphasisLeft}}merged feature into main: 2 tables, +300 -50 *20, 0 conflicts{{.EmphasisRight}}, instead of a block per table. The merge commit's info isn't printed.")
 
 	return ap
 }
@@ -17,53 +17,66 @@ package cli
 // Constants for command line flags names. These tend to be used in multiple places, so defining
 // them low in the package dependency tree makes sense.
 const (
-	AbortParam       = "abort"
-	AllFlag          = "all"
-	AllowEmptyFlag   = "allow-empty"
-	AmendFlag        = "amend"
-	AuthorParam      = "author"
-	BranchParam      = "branch"
-	CachedFlag       = "cached"
-	CheckoutCoBranch = "b"
-	CommitFlag       = "commit"
-	CopyFlag         = "copy"
-	DateParam        = "date"
-	DecorateFlag     = "decorate"
-	DeleteFlag       = "delete"
-	DeleteForceFlag  = "D"
-	DryRunFlag       = "dry-run"
-	ForceFlag        = "force"
-	HardResetParam   = "hard"
-	HostFlag         = "host"
-	ListFlag         = "list"
-	MergesFlag       = "merges"
-	MessageArg       = "message"
-	MinParentsFlag   = "min-parents"
-	MoveFlag         = "move"
-	NoCommitFlag     = "no-commit"
-	NoEditFlag       = "no-edit"
-	NoFFParam        = "no-ff"
-	NoPrettyFlag     = "no-pretty"
-	NoTLSFlag        = "no-tls"
-	NotFlag          = "not"
-	NumberFlag       = "number"
-	OneLineFlag      = "oneline"
-	OursFlag         = "ours"
-	OutputOnlyFlag   = "output-only"
-	ParentsFlag      = "parents"
-	PasswordFlag     = "password"
-	PortFlag         = "port"
-	PruneFlag        = "prune"
-	RemoteParam      = "remote"
-	SetUpstreamFlag  = "set-upstream"
-	ShallowFlag      = "shallow"
-	ShowIgnoredFlag  = "ignored"
-	SkipEmptyFlag    = "skip-empty"
-	SoftResetParam   = "soft"
-	SquashParam      = "squash"
-	TablesFlag       = "tables"
-	TheirsFlag       = "theirs"
-	TrackFlag        = "track"
-	UpperCaseAllFlag = "ALL"
-	UserFlag         = "user"
+	AbortParam          = "abort"
+	AllFlag             = "all"
+	AllowEmptyFlag      = "allow-empty"
+	AmendFlag           = "amend"
+	AuthorParam         = "author"
+	BranchParam         = "branch"
+	CachedFlag          = "cached"
+	CheckoutCoBranch    = "b"
+	CommitFlag          = "commit"
+	ConflictBranchParam = "conflict-branch"
+	CopyFlag            = "copy"
+	DateParam           = "date"
+	DecorateFlag        = "decorate"
+	DeleteFlag          = "delete"
+	DeleteForceFlag     = "D"
+	DryRunFlag          = "dry-run"
+	ForceFlag           = "force"
+	ForceMergeBase      = "force-merge-base"
+	HardResetParam      = "hard"
+	HostFlag            = "host"
+	IncludeSystemTables = "include-system-tables"
+	ListFlag            = "list"
+	MergeBaseParam      = "merge-base"
+	MergesFlag          = "merges"
+	MessageArg          = "message"
+	MinParentsFlag      = "min-parents"
+	MoveFlag            = "move"
+	NoCommitFlag        = "no-commit"
+	NoEditFlag          = "no-edit"
+	NoFFParam           = "no-ff"
+	NoGCHintFlag        = "no-gc-hint"
+	NoPagerFlag         = "no-pager"
+	NoPrettyFlag        = "no-pretty"
+	NoTLSFlag           = "no-tls"
+	NotFlag             = "not"
+	NumberFlag          = "number"
+	OneLineFlag         = "oneline"
+	OnlyParam           = "only"
+	OursFlag            = "ours"
+	OutputOnlyFlag      = "output-only"
+	ParentsFlag         = "parents"
+	PasswordFlag        = "password"
+	PortFlag            = "port"
+	PruneFlag           = "prune"
+	PruneViolations     = "prune-violations"
+	QuietFlag           = "quiet"
+	RemoteParam         = "remote"
+	ResolveParam        = "resolve"
+	SetUpstreamFlag     = "set-upstream"
+	ShallowFlag         = "shallow"
+	ShowConflictsFlag   = "show-conflicts"
+	ShowIgnoredFlag     = "ignored"
+	SkipEmptyFlag       = "skip-empty"
+	SoftResetParam      = "soft"
+	SquashParam         = "squash"
+	SummaryLineFlag     = "summary-line"
+	TablesFlag          = "tables"
+	TextMergeParam      = "text-merge"
+	TheirsFlag          = "theirs"
+	TrackFlag           = "track"
+	UpperCaseAllFlag    = "ALL"
+	UserFlag            = "user"
 )
@@ -29,12 +29,14 @@ import (
 	goisatty "github.com/mattn/go-isatty"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
 	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/libraries/utils/editor"
 	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
@@ -128,6 +130,7 @@ func performCommit(ctx context.Context, commandStr string, args []string, cliCtx
 	msg, msgOk := apr.GetValue(cli.MessageArg)
 	if !msgOk {
 		amendStr := ""
+		suggestedMsg := ""
 		if apr.Contains(cli.AmendFlag) {
 			_, rowIter, err := queryist.Query(sqlCtx, "select message from dolt_log() limit 1")
 			if err != nil {
@@ -140,8 +143,10 @@ func performCommit(ctx context.Context, commandStr string, args []string, cliCtx
 				return 1, false
 			}
 			amendStr = row[0].(string)
+		} else {
+			suggestedMsg = getMergeMessage(sqlCtx, queryist)
 		}
-		msg, err = getCommitMessageFromEditor(sqlCtx, queryist, "", amendStr, false, cliCtx)
+		msg, err = getCommitMessageFromEditor(sqlCtx, queryist, suggestedMsg, amendStr, false, cliCtx)
 		if err != nil {
 			return handleCommitErr(sqlCtx, queryist, err, usage), false
 		}
@@ -328,6 +333,21 @@ func handleCommitErr(sqlCtx *sql.Context, queryist cli.Queryist, err error, usag
 	return HandleVErrAndExitCode(verr, usage)
 }
 
+// getMergeMessage returns the message recorded for the commit that completes the merge in progress in the current
+// database, or the empty string if there isn't one. The merge state can only be read from a local engine, so this
+// also returns the empty string when connected to a server; dolt_commit still uses the recorded message if none is
+// given.
+func getMergeMessage(sqlCtx *sql.Context, queryist cli.Queryist) string {
+	if _, ok := queryist.(*engine.SqlEngine); !ok {
+		return ""
+	}
+	ws, err := dsess.DSessFromSess(sqlCtx.Session).WorkingSet(sqlCtx, sqlCtx.GetCurrentDatabase())
+	if err != nil || !ws.MergeActive() {
+		return ""
+	}
+	return ws.MergeState().MergeMessage()
+}
+
 // getCommitMessageFromEditor opens editor to ask user for commit message if none defined from command line.
 // suggestedMsg will be returned if no-edit flag is defined or if this function was called from sql dolt_merge command.
 func getCommitMessageFromEditor(sqlCtx *sql.Context, queryist cli.Queryist, suggestedMsg, amendString string, noEdit bool, cliCtx cli.CliContext) (string, error) {
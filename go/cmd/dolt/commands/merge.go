@@ -52,6 +52,7 @@ The second syntax ({{.LessThan}}dolt merge --abort{{.GreaterThan}}) can only be
 	Synopsis: []string{
 		"[--squash] {{.LessThan}}branch{{.GreaterThan}}",
 		"--no-ff [-m message] {{.LessThan}}branch{{.GreaterThan}}",
+		"--merge-base {{.LessThan}}ref{{.GreaterThan}} [--force-merge-base] {{.LessThan}}branch{{.GreaterThan}}",
 		"--abort",
 	},
 }
@@ -69,12 +70,14 @@ func (cmd MergeCmd) Description() string {
 }
 
 func (cmd MergeCmd) Docs() *cli.CommandDocumentation {
-	ap := cli.CreateMergeArgParser()
+	ap := cmd.ArgParser()
 	return cli.NewCommandDocumentation(mergeDocs, ap)
 }
 
 func (cmd MergeCmd) ArgParser() *argparser.ArgParser {
-	return cli.CreateMergeArgParser()
+	ap := cli.CreateMergeArgParser()
+	ap.SupportsFlag(cli.ShowConflictsFlag, "", "If the merge results in conflicts, print the conflicting rows and schemas for each table after the merge summary.")
+	return ap
 }
 
 // EventType returns the type of the event to log
@@ -88,7 +91,7 @@ func (cmd MergeCmd) RequiresRepo() bool {
 
 // Exec executes the command
 func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
-	ap := cli.CreateMergeArgParser()
+	ap := cmd.ArgParser()
 	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, mergeDocs, ap))
 	apr := cli.ParseArgsOrDie(ap, args, help)
 
@@ -129,6 +132,16 @@ func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string,
 		cli.Println(err.Error())
 		return 1
 	}
+	if apr.Contains(cli.ForceMergeBase) {
+		for _, warning := range sqlCtx.Session.Warnings() {
+			cli.PrintErrln(color.YellowString("warning: " + warning.Message))
+		}
+	}
+	autoMergedRows, rowsRemovedForViolations, err := getMergeRowCounts(queryist, sqlCtx)
+	if err != nil {
+		cli.Println(err.Error())
+		return 1
+	}
 	// if merge is called with '--no-commit', we need to commit the sql transaction or the staged changes will be lost
 	_, _, err = queryist.Query(sqlCtx, "COMMIT")
 	if err != nil {
@@ -143,13 +156,30 @@ func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string,
 		cli.Println(err.Error())
 		return 0
 	}
-	if rows != nil && rows[0][1].(int64) == 1 {
+	var fastForward, conflicts int64
+	if len(rows) > 0 {
+		fastForward, err = getInt64ColAsInt64(rows[0][1])
+		if err == nil {
+			conflicts, err = getInt64ColAsInt64(rows[0][2])
+		}
+		if err != nil {
+			cli.Println("merge finished, but failed to check for fast-forward")
+			cli.Println(err.Error())
+			return 0
+		}
+	}
+	if fastForward == 1 {
 		cli.Println("Fast-forward")
 	}
 
+	if conflictBranch, ok := apr.GetValue(cli.ConflictBranchParam); ok && conflicts == 1 {
+		cli.Printf("Automatic merge failed; the merge was saved to branch '%s' and the current branch was left unchanged.\n"+
+			"Use 'dolt checkout %s' and 'dolt conflicts' to investigate and resolve conflicts.\n", conflictBranch, conflictBranch)
+		return 0
+	}
+
 	// calculate merge stats
 	if !apr.Contains(cli.AbortParam) {
-		//todo: refs with the `remotes/` prefix will fail to get a hash
 		mergeHash, mergeHashErr := getHashOf(queryist, sqlCtx, apr.Arg(0))
 		if mergeHashErr != nil {
 			cli.Println("merge finished, but failed to get hash of merge ref")
@@ -182,9 +212,9 @@ func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string,
 
 		if noConflicts {
 			if apr.Contains(cli.NoCommitFlag) {
-				mergeStats, err = calculateMergeStats(queryist, sqlCtx, mergeStats, "HEAD", "STAGED")
+				mergeStats, err = calculateMergeStats(queryist, sqlCtx, mergeStats, "HEAD", "STAGED", apr.Contains(cli.IncludeSystemTables))
 			} else {
-				mergeStats, err = calculateMergeStats(queryist, sqlCtx, mergeStats, "HEAD^1", "HEAD")
+				mergeStats, err = calculateMergeStats(queryist, sqlCtx, mergeStats, "HEAD^1", "HEAD", apr.Contains(cli.IncludeSystemTables))
 			}
 			if err != nil {
 				if err.Error() == "Already up to date." || err.Error() == "error: unable to get diff summary from HEAD^1 to HEAD: invalid ancestor spec" {
@@ -195,9 +225,19 @@ func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string,
 				cli.Println(err.Error())
 				return 1
 			}
+			for tblName, n := range autoMergedRows {
+				if stats, ok := mergeStats[tblName]; ok {
+					stats.AutoMergedRows = n
+				}
+			}
+		}
+		for tblName, n := range rowsRemovedForViolations {
+			if stats, ok := mergeStats[tblName]; ok {
+				stats.RowsRemovedForViolations = n
+			}
 		}
 
-		if !apr.Contains(cli.NoCommitFlag) && !apr.Contains(cli.NoFFParam) {
+		if !apr.Contains(cli.NoCommitFlag) && !apr.Contains(cli.NoFFParam) && !apr.Contains(cli.QuietFlag) && !apr.Contains(cli.SummaryLineFlag) {
 			commit, err := getCommitInfo(queryist, sqlCtx, "HEAD")
 			if err != nil {
 				cli.Println("merge finished, but failed to get commit info")
@@ -206,7 +246,12 @@ func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string,
 			}
 			if cli.ExecuteWithStdioRestored != nil {
 				cli.ExecuteWithStdioRestored(func() {
-					pager := outputpager.Start()
+					var pager *outputpager.Pager
+					if apr.Contains(cli.NoPagerFlag) {
+						pager = outputpager.StartUnpaged()
+					} else {
+						pager = outputpager.Start()
+					}
 					defer pager.Stop()
 
 					PrintCommitInfo(pager, 0, false, "auto", commit)
@@ -214,7 +259,26 @@ func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string,
 			}
 		}
 
-		hasConflicts, hasConstraintViolations := printSuccessStats(mergeStats)
+		var hasConflicts, hasConstraintViolations bool
+		if apr.Contains(cli.SummaryLineFlag) {
+			branchName, err := getActiveBranchName(sqlCtx, queryist)
+			if err != nil {
+				cli.Println("merge finished, but failed to get the current branch")
+				cli.Println(err.Error())
+				return 1
+			}
+			cli.Println(mergeSummaryLine(apr.Arg(0), branchName, mergeStats))
+			hasConflicts, hasConstraintViolations = mergeHasConflictsAndViolations(mergeStats)
+		} else {
+			hasConflicts, hasConstraintViolations = printSuccessStats(mergeStats)
+		}
+		if hasConflicts && apr.Contains(cli.ShowConflictsFlag) {
+			err = printMergeConflictDetails(queryist, sqlCtx, mergeStats)
+			if err != nil {
+				cli.Println("merge finished, but could not print conflicts")
+				cli.Println(err.Error())
+			}
+		}
 		return handleMergeErr(sqlCtx, queryist, nil, hasConflicts, hasConstraintViolations, usage)
 	}
 
@@ -296,6 +360,9 @@ func constructInterpolatedDoltMergeQuery(apr *argparser.ArgParseResults, cliCtx
 		params = append(params, apr.Arg(0))
 	} else if apr.Contains(cli.NoFFParam) {
 		writeToBuffer("--no-ff", false)
+		if apr.Contains(cli.AllowEmptyFlag) {
+			writeToBuffer("--allow-empty", false)
+		}
 	} else if apr.Contains(cli.AbortParam) {
 		writeToBuffer("--abort", false)
 	}
@@ -342,6 +409,66 @@ func constructInterpolatedDoltMergeQuery(apr *argparser.ArgParseResults, cliCtx
 		}
 		params = append(params, msg)
 	}
+	if apr.Contains(cli.MergeBaseParam) {
+		writeToBuffer("--merge-base", false)
+		writeToBuffer("?", true)
+		base, ok := apr.GetValue(cli.MergeBaseParam)
+		if !ok {
+			return "", errors.New("Could not retrieve merge base")
+		}
+		params = append(params, base)
+	}
+	if apr.Contains(cli.ForceMergeBase) {
+		writeToBuffer("--force-merge-base", false)
+	}
+	if apr.Contains(cli.PruneViolations) {
+		writeToBuffer("--prune-violations", false)
+		writeToBuffer("?", true)
+		cvTypes, ok := apr.GetValue(cli.PruneViolations)
+		if !ok {
+			return "", errors.New("Could not retrieve constraint violation types")
+		}
+		params = append(params, cvTypes)
+	}
+	if apr.Contains(cli.OnlyParam) {
+		writeToBuffer("--only", false)
+		writeToBuffer("?", true)
+		tables, ok := apr.GetValue(cli.OnlyParam)
+		if !ok {
+			return "", errors.New("Could not retrieve tables to merge")
+		}
+		params = append(params, tables)
+	}
+	if apr.Contains(cli.NoGCHintFlag) {
+		writeToBuffer("--no-gc-hint", false)
+	}
+	if apr.Contains(cli.TextMergeParam) {
+		writeToBuffer("--text-merge", false)
+		writeToBuffer("?", true)
+		columns, ok := apr.GetValue(cli.TextMergeParam)
+		if !ok {
+			return "", errors.New("Could not retrieve columns to merge line by line")
+		}
+		params = append(params, columns)
+	}
+	if apr.Contains(cli.ConflictBranchParam) {
+		writeToBuffer("--conflict-branch", false)
+		writeToBuffer("?", true)
+		branch, ok := apr.GetValue(cli.ConflictBranchParam)
+		if !ok {
+			return "", errors.New("Could not retrieve conflict branch")
+		}
+		params = append(params, branch)
+	}
+	if apr.Contains(cli.ResolveParam) {
+		writeToBuffer("--resolve", false)
+		writeToBuffer("?", true)
+		side, ok := apr.GetValue(cli.ResolveParam)
+		if !ok {
+			return "", errors.New("Could not retrieve conflict resolution")
+		}
+		params = append(params, side)
+	}
 
 	if !apr.Contains(cli.AbortParam) && !apr.Contains(cli.SquashParam) {
 		writeToBuffer("?", true)
@@ -359,9 +486,11 @@ func constructInterpolatedDoltMergeQuery(apr *argparser.ArgParseResults, cliCtx
 }
 
 // calculateMergeConflicts calculates the count of conflicts that occurred during the merge. Returns a map of table name to MergeStats,
-// a bool indicating whether there were any conflicts, and a bool indicating whether calculation was successful.
+// a bool indicating whether there were any conflicts, and a bool indicating whether calculation was successful. Only
+// per-table counts are queried, never the conflicting rows themselves, so this stays cheap for merges with very many
+// conflicts.
 func calculateMergeConflicts(queryist cli.Queryist, sqlCtx *sql.Context, mergeStats map[string]*merge.MergeStats) (map[string]*merge.MergeStats, bool, error) {
-	dataConflicts, err := GetRowsForSql(queryist, sqlCtx, "SELECT * FROM dolt_conflicts")
+	dataConflicts, err := GetRowsForSql(queryist, sqlCtx, "SELECT `table`, num_conflicts FROM dolt_conflicts")
 	if err != nil {
 		return nil, false, err
 	}
@@ -374,7 +503,7 @@ func calculateMergeConflicts(queryist cli.Queryist, sqlCtx *sql.Context, mergeSt
 		}
 	}
 
-	schemaConflicts, err := GetRowsForSql(queryist, sqlCtx, "SELECT * FROM dolt_schema_conflicts")
+	schemaConflicts, err := GetRowsForSql(queryist, sqlCtx, "SELECT table_name FROM dolt_schema_conflicts")
 	if err != nil {
 		return nil, false, err
 	}
@@ -387,7 +516,7 @@ func calculateMergeConflicts(queryist cli.Queryist, sqlCtx *sql.Context, mergeSt
 		}
 	}
 
-	constraintViolations, err := GetRowsForSql(queryist, sqlCtx, "SELECT * FROM dolt_constraint_violations")
+	constraintViolations, err := GetRowsForSql(queryist, sqlCtx, "SELECT `table`, num_violations FROM dolt_constraint_violations")
 	if err != nil {
 		return nil, false, err
 	}
@@ -403,9 +532,58 @@ func calculateMergeConflicts(queryist cli.Queryist, sqlCtx *sql.Context, mergeSt
 	return mergeStats, dataConflicts == nil && schemaConflicts == nil && constraintViolations == nil, nil
 }
 
+// printMergeConflictDetails prints the schema conflicts and conflicting rows for each table with conflicts in
+// |mergeStats|. Unlike calculateMergeConflicts, this reads every conflicting row, so it's only done when asked for with
+// --show-conflicts.
+func printMergeConflictDetails(queryist cli.Queryist, sqlCtx *sql.Context, mergeStats map[string]*merge.MergeStats) error {
+	var tblNames []string
+	for tblName, stats := range mergeStats {
+		if stats.HasDataConflicts() || stats.HasSchemaConflicts() {
+			tblNames = append(tblNames, tblName)
+		}
+	}
+	sort.Strings(tblNames)
+
+	for _, tblName := range tblNames {
+		stats := mergeStats[tblName]
+		if stats.HasSchemaConflicts() {
+			q, err := dbr.InterpolateForDialect("SELECT description FROM dolt_schema_conflicts WHERE table_name = ?", []interface{}{tblName}, dialect.MySQL)
+			if err != nil {
+				return err
+			}
+			rows, err := GetRowsForSql(queryist, sqlCtx, q)
+			if err != nil {
+				return err
+			}
+			for _, row := range rows {
+				cli.Printf("Schema conflict in %s: %v\n", tblName, row[0])
+			}
+		}
+
+		if stats.HasDataConflicts() {
+			q, err := dbr.InterpolateForDialect("SELECT * FROM ?", []interface{}{dbr.I(doltdb.DoltConfTablePrefix + tblName)}, dialect.MySQL)
+			if err != nil {
+				return err
+			}
+			sch, rowIter, err := queryist.Query(sqlCtx, q)
+			if err != nil {
+				return err
+			}
+			cli.Printf("Conflicts in %s:\n", tblName)
+			err = engine.PrettyPrintResults(sqlCtx, engine.FormatTabular, sch, rowIter)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 // calculateMergeStats calculates the table operations and row operations that occurred during the merge. Returns a map of
-// table name to MergeStats, and a bool indicating whether calculation was successful.
-func calculateMergeStats(queryist cli.Queryist, sqlCtx *sql.Context, mergeStats map[string]*merge.MergeStats, fromRef, toRef string) (map[string]*merge.MergeStats, error) {
+// table name to MergeStats, and a bool indicating whether calculation was successful. Changes to system tables, such as
+// dolt_docs, are only included if |includeSystemTables| is true.
+func calculateMergeStats(queryist cli.Queryist, sqlCtx *sql.Context, mergeStats map[string]*merge.MergeStats, fromRef, toRef string, includeSystemTables bool) (map[string]*merge.MergeStats, error) {
 	diffSummaries, err := getDiffSummariesBetweenRefs(queryist, sqlCtx, fromRef, toRef)
 	if err != nil {
 		return nil, err
@@ -420,6 +598,11 @@ func calculateMergeStats(queryist cli.Queryist, sqlCtx *sql.Context, mergeStats
 		if doltdb.IsFullTextTable(summary.TableName) {
 			continue
 		}
+		if !includeSystemTables && doltdb.HasDoltPrefix(summary.TableName) {
+			// the merge still changed something, even if it isn't reported
+			allUnmodified = false
+			continue
+		}
 		if summary.DiffType == "added" {
 			allUnmodified = false
 			mergeStats[summary.TableName] = &merge.MergeStats{
@@ -537,6 +720,76 @@ func printSuccessStats(tblToStats map[string]*merge.MergeStats) (conflicts bool,
 	return printConflictsAndViolations(tblToStats)
 }
 
+// mergeSummaryLine returns a single line describing the merge of |src| into |dst|, totaling the tables changed, the rows
+// added, deleted and modified, and the conflicts in |tblToStats|. Constraint violations are included if there are any.
+func mergeSummaryLine(src, dst string, tblToStats map[string]*merge.MergeStats) string {
+	var adds, deletes, mods, conflicts, violations, autoMerged, pruned int
+	for _, stats := range tblToStats {
+		adds += stats.Adds
+		deletes += stats.Deletes
+		mods += stats.Modifications
+		conflicts += stats.DataConflicts + stats.SchemaConflicts
+		violations += stats.ConstraintViolations
+		autoMerged += stats.AutoMergedRows
+		pruned += stats.RowsRemovedForViolations
+	}
+
+	line := fmt.Sprintf("merged %s into %s: %d tables, +%d -%d *%d, %d conflicts", src, dst, len(tblToStats), adds, deletes, mods, conflicts)
+	if violations > 0 {
+		line += fmt.Sprintf(", %d constraint violations", violations)
+	}
+	if autoMerged > 0 {
+		line += fmt.Sprintf(", %d rows auto-merged", autoMerged)
+	}
+	if pruned > 0 {
+		line += fmt.Sprintf(", %d rows removed for constraint violations", pruned)
+	}
+	return line
+}
+
+// getMergeRowCounts returns the number of rows of each table that were merged automatically and that were deleted by
+// --prune-violations in the last call to dolt_merge, from the dolt_merge_stats system table.
+func getMergeRowCounts(queryist cli.Queryist, sqlCtx *sql.Context) (autoMerged, removedForViolations map[string]int, err error) {
+	rows, err := GetRowsForSql(queryist, sqlCtx, "select table_name, auto_merged_rows, rows_removed_for_violations from dolt_merge_stats")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	autoMerged = make(map[string]int)
+	removedForViolations = make(map[string]int)
+	for _, row := range rows {
+		tblName, ok := row[0].(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("unexpected type for table_name in dolt_merge_stats: %T", row[0])
+		}
+		n, err := getInt64ColAsInt64(row[1])
+		if err != nil {
+			return nil, nil, err
+		}
+		autoMerged[tblName] = int(n)
+		n, err = getInt64ColAsInt64(row[2])
+		if err != nil {
+			return nil, nil, err
+		}
+		removedForViolations[tblName] = int(n)
+	}
+	return autoMerged, removedForViolations, nil
+}
+
+// mergeHasConflictsAndViolations returns whether any table in |tblToStats| has conflicts or constraint violations,
+// without printing anything.
+func mergeHasConflictsAndViolations(tblToStats map[string]*merge.MergeStats) (conflicts bool, constraintViolations bool) {
+	for _, stats := range tblToStats {
+		if stats.HasDataConflicts() || stats.HasSchemaConflicts() {
+			conflicts = true
+		}
+		if stats.HasConstraintViolations() {
+			constraintViolations = true
+		}
+	}
+	return conflicts, constraintViolations
+}
+
 func printAdditions(tblToStats map[string]*merge.MergeStats) {
 	for tblName, stats := range tblToStats {
 		if stats.Operation == merge.TableAdded {
@@ -583,6 +836,8 @@ func printModifications(tblToStats map[string]*merge.MergeStats) {
 	rowsAdded := 0
 	rowsDeleted := 0
 	rowsChanged := 0
+	rowsAutoMerged := 0
+	rowsPruned := 0
 	var tbls []string
 	for tblName, stats := range tblToStats {
 		if stats.Operation == merge.TableModified && stats.DataConflicts == 0 && stats.ConstraintViolations == 0 {
@@ -601,7 +856,9 @@ func printModifications(tblToStats map[string]*merge.MergeStats) {
 			rowsAdded += stats.Adds
 			rowsChanged += stats.Modifications + stats.DataConflicts
 			rowsDeleted += stats.Deletes
+			rowsAutoMerged += stats.AutoMergedRows
 		}
+		rowsPruned += stats.RowsRemovedForViolations
 	}
 
 	if len(tbls) == 0 {
@@ -625,6 +882,12 @@ func printModifications(tblToStats map[string]*merge.MergeStats) {
 
 	details := fmt.Sprintf("%d tables changed, %d rows added(+), %d rows modified(*), %d rows deleted(-)", len(tbls), rowsAdded, rowsChanged, rowsDeleted)
 	cli.Println(details)
+	if rowsAutoMerged > 0 {
+		cli.Println(fmt.Sprintf("%d of the modified rows were changed on both sides and merged automatically", rowsAutoMerged))
+	}
+	if rowsPruned > 0 {
+		cli.Println(fmt.Sprintf("%d rows were deleted because they violated constraints", rowsPruned))
+	}
 }
 
 func visualizeChangeTypes(stats *merge.MergeStats, maxMods int) string {
@@ -776,6 +1039,9 @@ func executeNoFFMergeAndCommit(ctx context.Context, sqlCtx *sql.Context, queryis
 		Name:       spec.Name,
 		Email:      spec.Email,
 	})
+	if err != nil {
+		return tblToStats, err
+	}
 
 	headRef, err := dEnv.RepoStateReader().CWBHeadRef()
 	if err != nil {
@@ -783,6 +1049,9 @@ func executeNoFFMergeAndCommit(ctx context.Context, sqlCtx *sql.Context, queryis
 	}
 
 	wsHash, err := ws.HashOf()
+	if err != nil {
+		return tblToStats, err
+	}
 	_, err = dEnv.DoltDB.CommitWithWorkingSet(
 		ctx,
 		headRef,
@@ -24,6 +24,7 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/clusterdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dprocedures"
 	"github.com/dolthub/dolt/go/libraries/utils/version"
 )
 
@@ -36,6 +37,7 @@ const (
 )
 
 var _ server.ServerEventListener = (*metricsListener)(nil)
+var _ dprocedures.MergeMetrics = (*metricsListener)(nil)
 
 type metricsListener struct {
 	labels prometheus.Labels
@@ -51,6 +53,12 @@ type metricsListener struct {
 	isReplicaGauges      *prometheus.GaugeVec
 	replicationLagGauges *prometheus.GaugeVec
 
+	// merge metrics, labeled by database
+	cntMerges            *prometheus.CounterVec
+	cntMergeFastForwards *prometheus.CounterVec
+	cntMergeConflicts    *prometheus.CounterVec
+	cntMergeViolations   *prometheus.CounterVec
+
 	// used in updating cluster metrics
 	clusterStatus  clusterdb.ClusterStatusProvider
 	mu             *sync.Mutex
@@ -102,6 +110,26 @@ func newMetricsListener(labels prometheus.Labels, versionStr string, clusterStat
 			Help:        "one if the server is currently in this role, zero otherwise",
 			ConstLabels: labels,
 		}, []string{dbLabel}),
+		cntMerges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "dss_merges",
+			Help:        "Count of merges performed by dolt_merge and dolt_pull",
+			ConstLabels: labels,
+		}, []string{dbLabel}),
+		cntMergeFastForwards: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "dss_merge_fast_forwards",
+			Help:        "Count of merges performed by dolt_merge and dolt_pull that were fast-forwards",
+			ConstLabels: labels,
+		}, []string{dbLabel}),
+		cntMergeConflicts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "dss_merge_conflicts",
+			Help:        "Count of data and schema conflicts found by merges",
+			ConstLabels: labels,
+		}, []string{dbLabel}),
+		cntMergeViolations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "dss_merge_constraint_violations",
+			Help:        "Count of constraint violations found by merges",
+			ConstLabels: labels,
+		}, []string{dbLabel}),
 		clusterStatus:  clusterStatus,
 		mu:             &sync.Mutex{},
 		clusterSeenDbs: make(map[string]struct{}),
@@ -126,6 +154,10 @@ func newMetricsListener(labels prometheus.Labels, versionStr string, clusterStat
 	prometheus.MustRegister(ml.histQueryDur)
 	prometheus.MustRegister(ml.replicationLagGauges)
 	prometheus.MustRegister(ml.isReplicaGauges)
+	prometheus.MustRegister(ml.cntMerges)
+	prometheus.MustRegister(ml.cntMergeFastForwards)
+	prometheus.MustRegister(ml.cntMergeConflicts)
+	prometheus.MustRegister(ml.cntMergeViolations)
 
 	go func() {
 		for ml.updateReplMetrics() {
@@ -200,6 +232,16 @@ func (ml *metricsListener) QueryCompleted(success bool, duration time.Duration)
 	ml.histQueryDur.Observe(duration.Seconds())
 }
 
+// MergeCompleted implements dprocedures.MergeMetrics.
+func (ml *metricsListener) MergeCompleted(dbName string, fastForward bool, conflicts, constraintViolations int) {
+	ml.cntMerges.WithLabelValues(dbName).Inc()
+	if fastForward {
+		ml.cntMergeFastForwards.WithLabelValues(dbName).Inc()
+	}
+	ml.cntMergeConflicts.WithLabelValues(dbName).Add(float64(conflicts))
+	ml.cntMergeViolations.WithLabelValues(dbName).Add(float64(constraintViolations))
+}
+
 func (ml *metricsListener) Close() {
 	prometheus.Unregister(ml.gaugeVersion)
 	prometheus.Unregister(ml.cntConnections)
@@ -207,6 +249,10 @@ func (ml *metricsListener) Close() {
 	prometheus.Unregister(ml.gaugeConcurrentConn)
 	prometheus.Unregister(ml.gaugeConcurrentQueries)
 	prometheus.Unregister(ml.histQueryDur)
+	prometheus.Unregister(ml.cntMerges)
+	prometheus.Unregister(ml.cntMergeFastForwards)
+	prometheus.Unregister(ml.cntMergeConflicts)
+	prometheus.Unregister(ml.cntMergeViolations)
 
 	ml.closeReplicationMetrics()
 }
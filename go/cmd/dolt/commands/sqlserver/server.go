@@ -44,6 +44,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/binlogreplication"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
 	_ "github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dprocedures"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqlserver"
 )
@@ -256,6 +257,10 @@ func Serve(
 		go func() {
 			_ = metSrv.ListenAndServe()
 		}()
+
+		// merge metrics are only collected when they can be scraped
+		dprocedures.SetMergeMetrics(listener)
+		defer dprocedures.SetMergeMetrics(nil)
 	}
 
 	var remoteSrv *remotesrv.Server
@@ -353,6 +353,12 @@ func getInt64ColAsInt64(col interface{}) (int64, error) {
 	switch v := col.(type) {
 	case int:
 		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
 	case uint64:
 		return int64(v), nil
 	case int64:
@@ -364,7 +370,7 @@ func getInt64ColAsInt64(col interface{}) (int64, error) {
 		}
 		return iv, nil
 	default:
-		return 0, fmt.Errorf("unexpected type %T, was expecting int64, uint64 or string", v)
+		return 0, fmt.Errorf("unexpected type %T, was expecting an integer or string", v)
 	}
 }
 
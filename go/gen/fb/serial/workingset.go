@@ -382,7 +382,15 @@ func (rcv *MergeState) MutateIsCherryPick(n bool) bool {
 	return rcv._tab.MutateBoolSlot(12, n)
 }
 
-const MergeStateNumFields = 5
+func (rcv *MergeState) MergeMessage() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(14))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+const MergeStateNumFields = 6
 
 func MergeStateStart(builder *flatbuffers.Builder) {
 	builder.StartObject(MergeStateNumFields)
@@ -411,6 +419,9 @@ func MergeStateStartUnmergableTablesVector(builder *flatbuffers.Builder, numElem
 func MergeStateAddIsCherryPick(builder *flatbuffers.Builder, isCherryPick bool) {
 	builder.PrependBoolSlot(4, isCherryPick, false)
 }
+func MergeStateAddMergeMessage(builder *flatbuffers.Builder, mergeMessage flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(5, flatbuffers.UOffsetT(mergeMessage), 0)
+}
 func MergeStateEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
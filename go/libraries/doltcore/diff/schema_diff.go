@@ -16,6 +16,7 @@ package diff
 
 import (
 	"reflect"
+	"strings"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
@@ -204,3 +205,41 @@ func DiffForeignKeys(fromFks, toFKs []doltdb.ForeignKey) (diffs []ForeignKeyDiff
 	}
 	return diffs
 }
+
+type CheckDifference struct {
+	DiffType SchemaChangeType
+	From     schema.Check
+	To       schema.Check
+}
+
+// DiffChecks matches two sets of check constraints by name, which is not case-sensitive.
+// It returns matched and unmatched checks as a slice of CheckDifferences.
+func DiffChecks(fromChecks, toChecks []schema.Check) (diffs []CheckDifference) {
+	matched := make(map[string]bool)
+	for _, from := range fromChecks {
+		d := CheckDifference{DiffType: SchDiffRemoved, From: from}
+		for _, to := range toChecks {
+			if strings.EqualFold(from.Name(), to.Name()) {
+				matched[strings.ToLower(to.Name())] = true
+				d.To = to
+				d.DiffType = SchDiffModified
+				if from.Expression() == to.Expression() && from.Enforced() == to.Enforced() {
+					d.DiffType = SchDiffNone
+				}
+				break
+			}
+		}
+		diffs = append(diffs, d)
+	}
+
+	for _, to := range toChecks {
+		if matched[strings.ToLower(to.Name())] {
+			continue
+		}
+		diffs = append(diffs, CheckDifference{
+			DiffType: SchDiffAdded,
+			To:       to,
+		})
+	}
+	return diffs
+}
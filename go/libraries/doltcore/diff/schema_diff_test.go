@@ -66,3 +66,35 @@ func TestDiffSchemas(t *testing.T) {
 		t.Error(diffs, "!=", expected)
 	}
 }
+
+func TestDiffChecks(t *testing.T) {
+	oldChecks := schema.NewCheckCollection()
+	unchanged, err := oldChecks.AddCheck("unchanged", "(a > 0)", true)
+	require.NoError(t, err)
+	dropped, err := oldChecks.AddCheck("dropped", "(b > 0)", true)
+	require.NoError(t, err)
+	modified, err := oldChecks.AddCheck("modified", "(c > 0)", true)
+	require.NoError(t, err)
+	unenforced, err := oldChecks.AddCheck("unenforced", "(d > 0)", true)
+	require.NoError(t, err)
+
+	newChecks := schema.NewCheckCollection()
+	newUnchanged, err := newChecks.AddCheck("UNCHANGED", "(a > 0)", true)
+	require.NoError(t, err)
+	newModified, err := newChecks.AddCheck("modified", "(c > 1)", true)
+	require.NoError(t, err)
+	newUnenforced, err := newChecks.AddCheck("unenforced", "(d > 0)", false)
+	require.NoError(t, err)
+	added, err := newChecks.AddCheck("added", "(e > 0)", true)
+	require.NoError(t, err)
+
+	diffs := DiffChecks(oldChecks.AllChecks(), newChecks.AllChecks())
+	expected := []CheckDifference{
+		{SchDiffNone, unchanged, newUnchanged},
+		{SchDiffRemoved, dropped, nil},
+		{SchDiffModified, modified, newModified},
+		{SchDiffModified, unenforced, newUnenforced},
+		{SchDiffAdded, nil, added},
+	}
+	require.Equal(t, expected, diffs)
+}
@@ -49,7 +49,10 @@ func parseInstructions(aSpec string) ([]int, error) {
 
 		switch currInst {
 		case '^':
-			instructions = append(instructions, num-1)
+			// ^0 is the commit itself
+			if num > 0 {
+				instructions = append(instructions, num-1)
+			}
 		case '~':
 			for j := 0; j < num; j++ {
 				instructions = append(instructions, 0)
@@ -115,7 +118,7 @@ func SplitAncestorSpec(s string) (string, *AncestorSpec, error) {
 	}
 
 	commitSpec := cleanStr[:idx]
-	as, err := NewAncestorSpec(s[idx:])
+	as, err := NewAncestorSpec(cleanStr[idx:])
 
 	if err != nil {
 		return "", emptyASpec, err
@@ -34,6 +34,9 @@ func TestParseInstructions(t *testing.T) {
 		{"~3", []int{0, 0, 0}, false},
 		{"^^", []int{0, 0}, false},
 		{"^2~3^5", []int{1, 0, 0, 0, 4}, false},
+		{"^0", []int{}, false},
+		{"~0", []int{}, false},
+		{"~5", []int{0, 0, 0, 0, 0}, false},
 		{"invalid", nil, true},
 	}
 
@@ -61,6 +64,7 @@ func TestSplitAncestorSpec(t *testing.T) {
 		{"MASTER^1", "MASTER", "^1", false},
 		{"head~3^^", "head", "~3^^", false},
 		{"HEAD~3^^", "HEAD", "~3^^", false},
+		{"  HEAD~5 ", "HEAD", "~5", false},
 		{"branch^invalid", "", "", true},
 	}
 
@@ -568,6 +568,33 @@ func (ddb *DoltDB) ReadRootValue(ctx context.Context, h hash.Hash) (*RootValue,
 	return decodeRootNomsValue(ddb.vrw, ddb.ns, val)
 }
 
+// ReadWorkingSetRoot reads the working root of the working set whose hash is |h|, as returned by WorkingSet.HashOf.
+// Returns ErrHashNotFound if there's no value with that hash, and ErrFoundHashNotAWorkingSet if the value isn't a
+// working set.
+func (ddb *DoltDB) ReadWorkingSetRoot(ctx context.Context, h hash.Hash) (*RootValue, error) {
+	val, err := ddb.vrw.ReadValue(ctx, h)
+	if err != nil {
+		return nil, err
+	}
+	if val == nil {
+		return nil, ErrHashNotFound
+	}
+
+	ok, err := datas.IsWorkingSet(val)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrFoundHashNotAWorkingSet
+	}
+
+	rootVal, err := datas.LoadRootNomsValueFromRootIshAddr(ctx, ddb.vrw, h)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRootNomsValue(ddb.vrw, ddb.ns, rootVal)
+}
+
 // ReadCommit reads the Commit whose hash is |h|, if one exists.
 func (ddb *DoltDB) ReadCommit(ctx context.Context, h hash.Hash) (*Commit, error) {
 	c, err := datas.LoadCommitAddr(ctx, ddb.vrw, h)
@@ -633,6 +660,25 @@ func (ddb *DoltDB) SetHeadToCommit(ctx context.Context, ref ref.DoltRef, cm *Com
 	return ddb.SetHead(ctx, ref, addr)
 }
 
+// SetHeadToCommitIfAt sets the given ref to point at the given commit if it still points at the commit with hash
+// |expected|. Returns ErrHeadMoved if the ref points anywhere else, so a concurrent update isn't overwritten.
+func (ddb *DoltDB) SetHeadToCommitIfAt(ctx context.Context, ref ref.DoltRef, expected hash.Hash, cm *Commit) error {
+	ds, err := ddb.db.GetDataset(ctx, ref.String())
+	if err != nil {
+		return err
+	}
+	if curr, ok := ds.MaybeHeadAddr(); !ok || curr != expected {
+		return ErrHeadMoved
+	}
+
+	// WriteCommit only updates the ref if it still points at the head |ds| was read with
+	_, err = ddb.db.WriteCommit(ctx, ds, cm.dCommit)
+	if err == datas.ErrMergeNeeded {
+		return ErrHeadMoved
+	}
+	return err
+}
+
 func (ddb *DoltDB) SetHead(ctx context.Context, ref ref.DoltRef, addr hash.Hash) error {
 	ds, err := ddb.db.GetDataset(ctx, ref.String())
 
@@ -1353,6 +1399,14 @@ func (ddb *DoltDB) NewWorkspaceAtCommit(ctx context.Context, workRef ref.DoltRef
 	return err
 }
 
+// DeleteInternalRef deletes the internal ref given. Returns ErrBranchNotFound if it doesn't exist.
+func (ddb *DoltDB) DeleteInternalRef(ctx context.Context, internalRef ref.DoltRef) error {
+	if internalRef.GetType() != ref.InternalRefType {
+		return fmt.Errorf("%s is not an internal ref", internalRef.String())
+	}
+	return ddb.deleteRef(ctx, internalRef, nil)
+}
+
 func (ddb *DoltDB) DeleteWorkspace(ctx context.Context, workRef ref.DoltRef) error {
 	err := ddb.deleteRef(ctx, workRef, nil)
 
@@ -1529,6 +1583,56 @@ func (ddb *DoltDB) TableFileStoreHasJournal(ctx context.Context) (bool, error) {
 	return false, nil
 }
 
+// StorageStats describes the storage used by the chunk store of a DoltDB.
+type StorageStats struct {
+	// ChunkCount is the number of chunks in the store's table files. A chunk written to more than one table file is
+	// counted once for each file.
+	ChunkCount uint64
+	// TotalBytes is the total size of the store's table files, in bytes.
+	TotalBytes uint64
+	// NewGenBytes is the total size of the table files of the store's new generation, in bytes, including any appendix
+	// files. The new generation holds the chunks written since the last garbage collection, which is the only part of
+	// the store that garbage collection can reclaim, but it isn't an estimate of garbage: most of its chunks are usually
+	// still referenced. It's zero for stores that don't keep a separate generation of new chunks.
+	NewGenBytes uint64
+}
+
+// StorageStats returns statistics about the storage used by this DoltDB, read from the metadata of its table files
+// rather than by scanning its chunks. Returns chunks.ErrUnsupportedOperation if the underlying ChunkStore doesn't
+// store its chunks in table files.
+func (ddb *DoltDB) StorageStats(ctx context.Context) (StorageStats, error) {
+	cs := datas.ChunkStoreFromDatabase(ddb.db)
+	tableFileStore, ok := cs.(chunks.TableFileStore)
+	if !ok {
+		return StorageStats{}, fmt.Errorf("%w: storage stats are only available for stores with table files", chunks.ErrUnsupportedOperation)
+	}
+
+	var stats StorageStats
+	_, tableFiles, _, err := tableFileStore.Sources(ctx)
+	if err != nil {
+		return StorageStats{}, err
+	}
+	for _, tableFile := range tableFiles {
+		stats.ChunkCount += uint64(tableFile.NumChunks())
+	}
+
+	stats.TotalBytes, err = tableFileStore.Size(ctx)
+	if err != nil {
+		return StorageStats{}, err
+	}
+
+	if gcs, ok := cs.(chunks.GenerationalCS); ok {
+		if newGen, ok := gcs.NewGen().(chunks.TableFileStore); ok {
+			stats.NewGenBytes, err = newGen.Size(ctx)
+			if err != nil {
+				return StorageStats{}, err
+			}
+		}
+	}
+
+	return stats, nil
+}
+
 func (ddb *DoltDB) SetCommitHooks(ctx context.Context, postHooks []CommitHook) *DoltDB {
 	ddb.db = ddb.db.SetCommitHooks(ctx, postHooks)
 	return ddb
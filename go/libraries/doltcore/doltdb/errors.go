@@ -30,6 +30,7 @@ var ErrInvalidBranchOrHash = errors.New("string is not a valid branch or hash")
 var ErrInvalidHash = errors.New("string is not a valid hash")
 
 var ErrFoundHashNotACommit = errors.New("the value retrieved for this hash is not a commit")
+var ErrFoundHashNotAWorkingSet = errors.New("the value retrieved for this hash is not a working set")
 var ErrHashNotFound = errors.New("could not find a value for this hash")
 var ErrBranchNotFound = errors.New("branch not found")
 var ErrTagNotFound = errors.New("tag not found")
@@ -45,6 +46,7 @@ var ErrNomsIO = errors.New("error reading from or writing to noms")
 var ErrUpToDate = errors.New("up to date")
 var ErrIsAhead = errors.New("cannot fast forward from a to b. a is ahead of b already")
 var ErrIsBehind = errors.New("cannot reverse from b to a. b is a is behind a already")
+var ErrHeadMoved = errors.New("the ref was moved by another update")
 
 var ErrUnresolvedConflictsOrViolations = errors.New("merge has unresolved conflicts or constraint violations")
 var ErrMergeActive = errors.New("merging is not possible because you have not committed an active merge")
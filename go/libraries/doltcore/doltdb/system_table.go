@@ -318,6 +318,19 @@ const (
 	TagsTableName = "dolt_tags"
 
 	IgnoreTableName = "dolt_ignore"
+
+	// StorageStatsTableName is the storage stats system table name
+	StorageStatsTableName = "dolt_storage_stats"
+
+	// ActiveRevisionsTableName is the active revisions system table name
+	ActiveRevisionsTableName = "dolt_active_revisions"
+
+	// MergeStatsTableName is the merge stats system table name
+	MergeStatsTableName = "dolt_merge_stats"
+
+	// ConflictsSummaryTableName is the conflicts summary system table name. It takes precedence over the conflicts
+	// table of a user table named summary, whose conflicts are still counted in the summary.
+	ConflictsSummaryTableName = "dolt_conflicts_summary"
 )
 
 const (
@@ -39,6 +39,9 @@ type MergeState struct {
 	// isCherryPick is set to true when the in-progress merge is a cherry-pick. This is needed so that
 	// commit knows to NOT create a commit with multiple parents when creating a commit for a cherry-pick.
 	isCherryPick bool
+	// mergeMessage is the message suggested for the commit that completes the merge, if one was recorded when the
+	// merge started.
+	mergeMessage string
 }
 
 // todo(andy): this might make more sense in pkg merge
@@ -85,6 +88,12 @@ func (m MergeState) IsCherryPick() bool {
 	return m.isCherryPick
 }
 
+// MergeMessage returns the message suggested for the commit that completes this merge, such as the default message
+// generated by dolt_merge or the message given with -m, or the empty string if none was recorded.
+func (m MergeState) MergeMessage() string {
+	return m.mergeMessage
+}
+
 func (m MergeState) PreMergeWorkingRoot() *RootValue {
 	return m.preMergeWorking
 }
@@ -202,6 +211,15 @@ func (ws WorkingSet) WithMergedTables(tables []string) *WorkingSet {
 	return &ws
 }
 
+// WithMergeMessage returns a copy of |ws| whose in-progress merge records |msg| as the message for the commit that
+// completes it. |ws| must have a merge in progress.
+func (ws WorkingSet) WithMergeMessage(msg string) *WorkingSet {
+	ms := *ws.mergeState
+	ms.mergeMessage = msg
+	ws.mergeState = &ms
+	return &ws
+}
+
 func (ws WorkingSet) StartMerge(commit *Commit, commitSpecStr string) *WorkingSet {
 	ws.mergeState = &MergeState{
 		commit:          commit,
@@ -349,12 +367,18 @@ func newWorkingSet(ctx context.Context, name string, vrw types.ValueReadWriter,
 			return nil, err
 		}
 
+		mergeMessage, err := dsws.MergeState.MergeMessage(ctx, vrw)
+		if err != nil {
+			return nil, err
+		}
+
 		mergeState = &MergeState{
 			commit:           commit,
 			commitSpecStr:    commitSpec,
 			preMergeWorking:  preMergeWorkingRoot,
 			unmergableTables: unmergableTables,
 			isCherryPick:     isCherryPick,
+			mergeMessage:     mergeMessage,
 		}
 	}
 
@@ -429,7 +453,7 @@ func (ws *WorkingSet) writeValues(ctx context.Context, db *DoltDB) (
 			return types.Ref{}, types.Ref{}, nil, err
 		}
 
-		mergeState, err = datas.NewMergeState(ctx, db.vrw, preMergeWorking, dCommit, ws.mergeState.commitSpecStr, ws.mergeState.unmergableTables, ws.mergeState.isCherryPick)
+		mergeState, err = datas.NewMergeState(ctx, db.vrw, preMergeWorking, dCommit, ws.mergeState.commitSpecStr, ws.mergeState.unmergableTables, ws.mergeState.isCherryPick, ws.mergeState.mergeMessage)
 		if err != nil {
 			return types.Ref{}, types.Ref{}, nil, err
 		}
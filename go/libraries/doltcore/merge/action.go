@@ -18,12 +18,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 	"github.com/dolthub/dolt/go/store/hash"
 )
@@ -33,6 +36,7 @@ var ErrFailedToReadDatabase = errors.New("failed to read database")
 var ErrMergeFailedToUpdateDocs = errors.New("failed to update docs to the new working root")
 var ErrMergeFailedToUpdateRepoState = errors.New("unable to execute repo state update")
 var ErrFailedToDetermineMergeability = errors.New("failed to determine mergeability")
+var ErrMergeBaseNotAncestor = errors.New("merge base is not an ancestor of both commits being merged")
 
 type MergeSpec struct {
 	HeadH           hash.Hash
@@ -52,6 +56,36 @@ type MergeSpec struct {
 	Email           string
 	Name            string
 	Date            time.Time
+	// MergeBaseC is the ancestor to use for a three-way merge in place of the computed common ancestor of HeadC and
+	// MergeC. It's nil unless set with SetMergeBase.
+	MergeBaseC *doltdb.Commit
+	// PruneViolations lists the types of constraint violations whose rows are deleted by a three-way merge instead of
+	// being recorded. See MergeOpts.PruneViolations.
+	PruneViolations []CvType
+	// OnlyTables limits a three-way merge to the tables named. See MergeOpts.OnlyTables.
+	OnlyTables []string
+	// TextMergeColumns lists the TEXT columns whose cells are merged line by line by a three-way merge. See
+	// MergeOpts.TextMergeColumns.
+	TextMergeColumns []string
+	// ResolveDataConflicts is "ours" or "theirs" to resolve the data conflicts of a three-way merge by taking the
+	// version of each conflicting row from that side of the merge. It's empty if conflicts aren't resolved.
+	ResolveDataConflicts string
+	// RetainInputs is true if the merge base, HeadC and MergeC should be kept from being garbage collected after the
+	// merge by pointing internal refs at them. See MergeInputRefs.
+	RetainInputs bool
+	// ConflictBranch names a new branch to hold a merge that ends with conflicts or constraint violations, leaving the
+	// current branch as it was before the merge. It's empty if a conflicted merge is left on the current branch.
+	ConflictBranch string
+}
+
+// Opts returns the MergeOpts for a three-way merge of this spec.
+func (ms *MergeSpec) Opts() MergeOpts {
+	return MergeOpts{
+		KeepSchemaConflicts: true,
+		PruneViolations:     ms.PruneViolations,
+		OnlyTables:          ms.OnlyTables,
+		TextMergeColumns:    ms.TextMergeColumns,
+	}
 }
 
 // NewMergeSpec returns MergeSpec object using arguments passed into this function, which are doltdb.Roots, username,
@@ -119,6 +153,199 @@ func NewMergeSpec(ctx context.Context, rsr env.RepoStateReader, ddb *doltdb.Dolt
 	}, nil
 }
 
+// SetMergeBase resolves |baseSpecStr| relative to |headRef| and sets the result as the merge base used for a three-way
+// merge of this spec. Callers should use ValidateMergeBase to check that the base is an ancestor of both sides.
+func (ms *MergeSpec) SetMergeBase(ctx context.Context, ddb *doltdb.DoltDB, headRef ref.DoltRef, baseSpecStr string) error {
+	baseCS, err := doltdb.NewCommitSpec(baseSpecStr)
+	if err != nil {
+		return err
+	}
+
+	baseCM, err := ddb.Resolve(ctx, baseCS, headRef)
+	if err != nil {
+		return err
+	}
+
+	ms.MergeBaseC = baseCM
+	return nil
+}
+
+// ValidateMergeBase returns ErrMergeBaseNotAncestor if this spec has a merge base set that isn't an ancestor of both
+// the head commit and the merge commit.
+func (ms *MergeSpec) ValidateMergeBase(ctx context.Context) error {
+	if ms.MergeBaseC == nil {
+		return nil
+	}
+
+	baseH, err := ms.MergeBaseC.HashOf()
+	if err != nil {
+		return err
+	}
+
+	for _, cm := range []*doltdb.Commit{ms.HeadC, ms.MergeC} {
+		ancestor, err := doltdb.GetCommitAncestor(ctx, ms.MergeBaseC, cm)
+		if err == doltdb.ErrNoCommonAncestor {
+			return ErrMergeBaseNotAncestor
+		} else if err != nil {
+			return err
+		}
+
+		ancH, err := ancestor.HashOf()
+		if err != nil {
+			return err
+		}
+		if ancH != baseH {
+			return ErrMergeBaseNotAncestor
+		}
+	}
+
+	return nil
+}
+
+// MaxRetainedMerges is the number of merges whose inputs RetainMergeInputs keeps. Retaining the inputs of another
+// merge releases those of the merges with the oldest ours commits.
+var MaxRetainedMerges = 64
+
+const mergeInputRefPrefix = "merge/"
+
+// MergeInputRefs returns the internal refs that RetainMergeInputs points at the merge base and the two commits of a
+// merge of the commit with hash |theirs| into the commit with hash |ours|.
+func MergeInputRefs(ours, theirs hash.Hash) (base, oursRef, theirsRef ref.DoltRef) {
+	prefix := mergeInputRefPrefix + ours.String() + "/" + theirs.String() + "/"
+	return ref.NewInternalRef(prefix + "base"), ref.NewInternalRef(prefix + "ours"), ref.NewInternalRef(prefix + "theirs")
+}
+
+// RetainedMerge identifies a merge whose inputs are kept by RetainMergeInputs, by the hashes of its two commits.
+type RetainedMerge struct {
+	Ours   hash.Hash
+	Theirs hash.Hash
+}
+
+// RetainedMerges returns the merges whose inputs are kept by RetainMergeInputs, in no particular order.
+func RetainedMerges(ctx context.Context, ddb *doltdb.DoltDB) ([]RetainedMerge, error) {
+	refs, err := ddb.GetRefsOfType(ctx, map[ref.RefType]struct{}{ref.InternalRefType: {}})
+	if err != nil {
+		return nil, err
+	}
+
+	var merges []RetainedMerge
+	for _, r := range refs {
+		parts := strings.Split(r.GetPath(), "/")
+		if len(parts) != 4 || parts[0]+"/" != mergeInputRefPrefix || parts[3] != "ours" {
+			continue
+		}
+		ours, ok := hash.MaybeParse(parts[1])
+		if !ok {
+			continue
+		}
+		theirs, ok := hash.MaybeParse(parts[2])
+		if !ok {
+			continue
+		}
+		merges = append(merges, RetainedMerge{Ours: ours, Theirs: theirs})
+	}
+	return merges, nil
+}
+
+// ReleaseMergeInputs deletes the refs that RetainMergeInputs created for the merge of the commit with hash |theirs|
+// into the commit with hash |ours|, so that garbage collection can collect its inputs once nothing else references
+// them. It does nothing if the merge's inputs aren't retained.
+func ReleaseMergeInputs(ctx context.Context, ddb *doltdb.DoltDB, ours, theirs hash.Hash) error {
+	baseRef, oursRef, theirsRef := MergeInputRefs(ours, theirs)
+	for _, r := range []ref.DoltRef{baseRef, oursRef, theirsRef} {
+		err := ddb.DeleteInternalRef(ctx, r)
+		if err != nil && err != doltdb.ErrBranchNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// RetainMergeInputs points the internal refs given by MergeInputRefs at the merge base and the two commits of |spec|, so
+// that a later garbage collection keeps them even once no branch references them. The merge base is the one set with
+// SetMergeBase, or else the common ancestor of the two commits. If more than MaxRetainedMerges merges are then
+// retained, the inputs of the others with the oldest ours commits are released.
+//
+// The refs are written directly to |ddb| rather than as part of a transaction, so this should only be called once the
+// merge commit has been written.
+func RetainMergeInputs(ctx context.Context, ddb *doltdb.DoltDB, spec *MergeSpec) error {
+	baseC := spec.MergeBaseC
+	if baseC == nil {
+		var err error
+		baseC, err = doltdb.GetCommitAncestor(ctx, spec.HeadC, spec.MergeC)
+		if err != nil {
+			return err
+		}
+	}
+
+	oursH, err := spec.HeadC.HashOf()
+	if err != nil {
+		return err
+	}
+	theirsH, err := spec.MergeC.HashOf()
+	if err != nil {
+		return err
+	}
+
+	baseRef, oursRef, theirsRef := MergeInputRefs(oursH, theirsH)
+	if err = ddb.SetHeadToCommit(ctx, baseRef, baseC); err != nil {
+		return err
+	}
+	if err = ddb.SetHeadToCommit(ctx, oursRef, spec.HeadC); err != nil {
+		return err
+	}
+	if err = ddb.SetHeadToCommit(ctx, theirsRef, spec.MergeC); err != nil {
+		return err
+	}
+
+	return releaseOldestMergeInputs(ctx, ddb, RetainedMerge{Ours: oursH, Theirs: theirsH})
+}
+
+// releaseOldestMergeInputs releases the inputs of the merges with the oldest ours commits until no more than
+// MaxRetainedMerges merges are retained, keeping those of |keep|.
+func releaseOldestMergeInputs(ctx context.Context, ddb *doltdb.DoltDB, keep RetainedMerge) error {
+	merges, err := RetainedMerges(ctx, ddb)
+	if err != nil {
+		return err
+	}
+	if len(merges) <= MaxRetainedMerges {
+		return nil
+	}
+
+	candidates := make([]RetainedMerge, 0, len(merges))
+	times := make(map[RetainedMerge]int64, len(merges))
+	for _, m := range merges {
+		if m == keep {
+			continue
+		}
+		_, oursRef, _ := MergeInputRefs(m.Ours, m.Theirs)
+		cm, err := ddb.ResolveCommitRef(ctx, oursRef)
+		if err != nil {
+			return err
+		}
+		meta, err := cm.GetCommitMeta(ctx)
+		if err != nil {
+			return err
+		}
+		candidates = append(candidates, m)
+		times[m] = meta.UserTimestamp
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return times[candidates[i]] < times[candidates[j]]
+	})
+
+	release := len(merges) - MaxRetainedMerges
+	if release > len(candidates) {
+		release = len(candidates)
+	}
+	for _, m := range candidates[:release] {
+		if err = ReleaseMergeInputs(ctx, ddb, m.Ours, m.Theirs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func ExecNoFFMerge(ctx context.Context, dEnv *env.DoltEnv, spec *MergeSpec) (map[string]*MergeStats, error) {
 	mergedRoot, err := spec.MergeC.GetRootValue(ctx)
 	if err != nil {
@@ -190,7 +417,12 @@ func ExecuteMerge(ctx *sql.Context, dEnv *env.DoltEnv, spec *MergeSpec) (map[str
 		return nil, err
 	}
 	opts := editor.Options{Deaf: dEnv.BulkDbEaFactory(), Tempdir: tmpDir}
-	result, err := MergeCommits(ctx, spec.HeadC, spec.MergeC, opts)
+	var result *Result
+	if spec.MergeBaseC != nil {
+		result, err = MergeCommitsWithBase(ctx, spec.HeadC, spec.MergeC, spec.MergeBaseC, opts)
+	} else {
+		result, err = MergeCommits(ctx, spec.HeadC, spec.MergeC, opts)
+	}
 	if err != nil {
 		switch err {
 		case doltdb.ErrUpToDate:
@@ -0,0 +1,194 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// ConstraintViolation is a typed version of a row of the dolt_constraint_violations_$table system table.
+type ConstraintViolation struct {
+	// Type is the kind of constraint that was violated.
+	Type CvType
+	// SourceRootish is the hash of the commit or working set that was merged in to create the violation.
+	SourceRootish hash.Hash
+	// Key is the primary key of the violating row. It's empty for keyless tables.
+	Key sql.Row
+	// Value is the non-primary-key columns of the violating row.
+	Value sql.Row
+	// Info describes the constraint that was violated. It's a FkCVMeta, UniqCVMeta, CheckCVMeta or NullViolationMeta,
+	// depending on Type.
+	Info types.JSONValue
+}
+
+// GetConstraintViolations returns the constraint violations recorded for |tbl|. Only the new storage format is
+// supported.
+func GetConstraintViolations(ctx *sql.Context, tbl *doltdb.Table) ([]ConstraintViolation, error) {
+	arts, err := tbl.GetArtifacts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	artM := durable.ProllyMapFromArtifactIndex(arts)
+	itr, err := artM.IterAllCVs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	kd, vd := sch.GetMapDescriptors()
+
+	// value tuples encoded in ConstraintViolationMeta may
+	// violate the not null constraints assumed by fixed access
+	kd = kd.WithoutFixedAccess()
+	vd = vd.WithoutFixedAccess()
+
+	keyless := schema.IsKeyless(sch)
+	ns := artM.NodeStore()
+
+	var violations []ConstraintViolation
+	for {
+		art, err := itr.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		var meta prolly.ConstraintViolationMeta
+		err = json.Unmarshal(art.Metadata, &meta)
+		if err != nil {
+			return nil, err
+		}
+
+		cv := ConstraintViolation{
+			Type:          CvTypeFromArtifactType(art.ArtType),
+			SourceRootish: art.SourceRootish,
+		}
+
+		if keyless {
+			// the first field of a keyless value tuple is the row's cardinality
+			cv.Value, err = tupleToRow(ctx, vd, meta.Value, ns, 1)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			cv.Key, err = tupleToRow(ctx, kd, art.SourceKey, ns, 0)
+			if err != nil {
+				return nil, err
+			}
+			cv.Value, err = tupleToRow(ctx, vd, meta.Value, ns, 0)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		cv.Info, err = UnmarshalViolationInfo(art.ArtType, meta.VInfo)
+		if err != nil {
+			return nil, err
+		}
+
+		violations = append(violations, cv)
+	}
+
+	return violations, nil
+}
+
+func tupleToRow(ctx *sql.Context, desc val.TupleDesc, tup val.Tuple, ns tree.NodeStore, from int) (sql.Row, error) {
+	r := make(sql.Row, desc.Count()-from)
+	for i := from; i < desc.Count(); i++ {
+		var err error
+		r[i-from], err = index.GetField(ctx, desc, i, tup, ns)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// CvTypeFromArtifactType returns the CvType for the constraint violation artifact type given. Panics if the artifact
+// type isn't a constraint violation.
+func CvTypeFromArtifactType(artType prolly.ArtifactType) CvType {
+	switch artType {
+	case prolly.ArtifactTypeForeignKeyViol:
+		return CvType_ForeignKey
+	case prolly.ArtifactTypeUniqueKeyViol:
+		return CvType_UniqueIndex
+	case prolly.ArtifactTypeChkConsViol:
+		return CvType_CheckConstraint
+	case prolly.ArtifactTypeNullViol:
+		return CvType_NotNull
+	default:
+		panic("unhandled cv type")
+	}
+}
+
+// ArtifactTypeFromCvType returns the artifact type used to store constraint violations of type |cvType|. Panics if
+// the type is unknown.
+func ArtifactTypeFromCvType(cvType CvType) prolly.ArtifactType {
+	switch cvType {
+	case CvType_ForeignKey:
+		return prolly.ArtifactTypeForeignKeyViol
+	case CvType_UniqueIndex:
+		return prolly.ArtifactTypeUniqueKeyViol
+	case CvType_CheckConstraint:
+		return prolly.ArtifactTypeChkConsViol
+	case CvType_NotNull:
+		return prolly.ArtifactTypeNullViol
+	default:
+		panic("unhandled cv type")
+	}
+}
+
+// UnmarshalViolationInfo decodes the violation info of a constraint violation artifact into the metadata type for
+// its artifact type.
+func UnmarshalViolationInfo(artType prolly.ArtifactType, vInfo []byte) (types.JSONValue, error) {
+	switch artType {
+	case prolly.ArtifactTypeForeignKeyViol:
+		var m FkCVMeta
+		err := json.Unmarshal(vInfo, &m)
+		return m, err
+	case prolly.ArtifactTypeUniqueKeyViol:
+		var m UniqCVMeta
+		err := json.Unmarshal(vInfo, &m)
+		return m, err
+	case prolly.ArtifactTypeNullViol:
+		var m NullViolationMeta
+		err := json.Unmarshal(vInfo, &m)
+		return m, err
+	case prolly.ArtifactTypeChkConsViol:
+		var m CheckCVMeta
+		err := json.Unmarshal(vInfo, &m)
+		return m, err
+	default:
+		return nil, fmt.Errorf("json not implemented for artifact type %d", artType)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	goerrors "gopkg.in/src-d/go-errors.v1"
@@ -46,12 +47,30 @@ var ErrMultipleViolationsForRow = errors.New("multiple violations for row not su
 
 var ErrSameTblAddedTwice = goerrors.NewKind("table with same name '%s' added in 2 commits can't be merged")
 
+var ErrMergeTableNotFound = goerrors.NewKind("table %s does not exist on either side of the merge")
+
 func MergeCommits(ctx *sql.Context, commit, mergeCommit *doltdb.Commit, opts editor.Options) (*Result, error) {
 	ancCommit, err := doltdb.GetCommitAncestor(ctx, commit, mergeCommit)
 	if err != nil {
 		return nil, err
 	}
 
+	return MergeCommitsWithBase(ctx, commit, mergeCommit, ancCommit, opts)
+}
+
+// MergeCommitsWithBase performs a three-way merge of |commit| and |mergeCommit| using |ancCommit| as the merge base,
+// rather than computing their common ancestor.
+func MergeCommitsWithBase(ctx *sql.Context, commit, mergeCommit, ancCommit *doltdb.Commit, opts editor.Options) (*Result, error) {
+	mo := MergeOpts{
+		IsCherryPick:        false,
+		KeepSchemaConflicts: true,
+	}
+	return MergeCommitsWithOpts(ctx, commit, mergeCommit, ancCommit, opts, mo)
+}
+
+// MergeCommitsWithOpts performs a three-way merge of |commit| and |mergeCommit| using |ancCommit| as the merge base,
+// with the merge options given.
+func MergeCommitsWithOpts(ctx *sql.Context, commit, mergeCommit, ancCommit *doltdb.Commit, opts editor.Options, mo MergeOpts) (*Result, error) {
 	ourRoot, err := commit.GetRootValue(ctx)
 	if err != nil {
 		return nil, err
@@ -67,10 +86,6 @@ func MergeCommits(ctx *sql.Context, commit, mergeCommit *doltdb.Commit, opts edi
 		return nil, err
 	}
 
-	mo := MergeOpts{
-		IsCherryPick:        false,
-		KeepSchemaConflicts: true,
-	}
 	return MergeRoots(ctx, ourRoot, theirRoot, ancRoot, mergeCommit, ancCommit, opts, mo)
 }
 
@@ -191,6 +206,11 @@ func MergeRoots(
 		return nil, err
 	}
 
+	skipped, err := tablesSkippedByMerge(tblNames, mergeOpts.OnlyTables)
+	if err != nil {
+		return nil, err
+	}
+
 	tblToStats := make(map[string]*MergeStats)
 
 	mergedRoot := ourRoot
@@ -205,6 +225,11 @@ func MergeRoots(
 
 	var schConflicts []SchemaConflict
 	for _, tblName := range tblNames {
+		if _, ok := skipped[tblName]; ok {
+			tblToStats[tblName] = &MergeStats{Operation: TableUnmodified, Skipped: true}
+			continue
+		}
+
 		mergedTable, stats, err := merger.MergeTable(ctx, tblName, opts, mergeOpts)
 		if err != nil {
 			// If a Full-Text table was both modified and deleted, then we want to ignore the deletion.
@@ -273,6 +298,16 @@ func MergeRoots(
 	if len(conflicts) > 0 {
 		return nil, fmt.Errorf("foreign key conflicts")
 	}
+	if len(skipped) > 0 {
+		ourFKColl, err := ourRoot.GetForeignKeyCollection(ctx)
+		if err != nil {
+			return nil, err
+		}
+		err = restoreForeignKeys(mergedFKColl, ourFKColl, skipped)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	mergedRoot, err = mergedRoot.PutForeignKeyCollection(ctx, mergedFKColl)
 	if err != nil {
@@ -290,6 +325,22 @@ func MergeRoots(
 	}
 
 	if types.IsFormat_DOLT(ourRoot.VRW().Format()) {
+		for len(mergeOpts.PruneViolations) > 0 {
+			var removed int
+			mergedRoot, removed, err = pruneViolatingRows(ctx, mergedRoot, ourRoot, h, mergeOpts.PruneViolations, tblToStats)
+			if err != nil {
+				return nil, err
+			}
+			if removed == 0 {
+				break
+			}
+			// deleted rows may have been referenced by other tables' foreign keys
+			mergedRoot, _, err = AddForeignKeyViolations(ctx, mergedRoot, ancRoot, nil, h)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		err = getConstraintViolationStats(ctx, mergedRoot, tblToStats)
 		if err != nil {
 			return nil, err
@@ -329,6 +380,53 @@ func MergeRoots(
 	}, nil
 }
 
+// tablesSkippedByMerge returns the names in |tblNames| that aren't in |onlyTables|, which is compared
+// case-insensitively. Full-text index tables are never skipped, since they're rebuilt from their parent tables. Returns
+// ErrMergeTableNotFound if a table in |onlyTables| isn't in |tblNames|.
+func tablesSkippedByMerge(tblNames, onlyTables []string) (map[string]struct{}, error) {
+	if len(onlyTables) == 0 {
+		return nil, nil
+	}
+
+	only := make(map[string]bool, len(onlyTables))
+	for _, name := range onlyTables {
+		only[strings.ToLower(name)] = false
+	}
+
+	skipped := make(map[string]struct{})
+	for _, tblName := range tblNames {
+		lwr := strings.ToLower(tblName)
+		if _, ok := only[lwr]; ok {
+			only[lwr] = true
+		} else if !doltdb.IsFullTextTable(tblName) {
+			skipped[tblName] = struct{}{}
+		}
+	}
+
+	for _, name := range onlyTables {
+		if !only[strings.ToLower(name)] {
+			return nil, ErrMergeTableNotFound.New(name)
+		}
+	}
+
+	return skipped, nil
+}
+
+// restoreForeignKeys replaces the foreign keys declared on the tables in |skipped| in |merged| with the ones declared
+// on them in |ours|.
+func restoreForeignKeys(merged, ours *doltdb.ForeignKeyCollection, skipped map[string]struct{}) error {
+	for tblName := range skipped {
+		mergedFks, _ := merged.KeysForTable(tblName)
+		merged.RemoveKeys(mergedFks...)
+		ourFks, _ := ours.KeysForTable(tblName)
+		err := merged.AddKeys(ourFks...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // mergeCVsWithStash merges the table constraint violations in |stash| with |root|.
 // Returns an updated root with all the merged CVs.
 func mergeCVsWithStash(ctx context.Context, root *doltdb.RootValue, stash *violationStash) (*doltdb.RootValue, error) {
@@ -249,6 +249,9 @@ func mergeNomsTableData(
 					if err != nil {
 						return err
 					}
+					if rowMergeResult.didCellMerge {
+						stats.AutoMergedRows++
+					}
 				}
 
 				change = types.ValueChanged{}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/memory"
 	"github.com/dolthub/go-mysql-server/sql"
@@ -53,8 +54,9 @@ var ErrUnableToMergeColumnDefaultValue = errorkinds.NewKind("unable to automatic
 // instance, along with merge stats and any error. If |rewriteRows| is true, then any existing rows in the
 // table's primary index will also be rewritten. This function merges the table's artifacts (e.g. recorded
 // conflicts), migrates any existing table data to the specified |mergedSch|, and merges table data from both
-// sides of the merge together.
-func mergeProllyTable(ctx context.Context, tm *TableMerger, mergedSch schema.Schema, rewriteRows bool) (*doltdb.Table, *MergeStats, error) {
+// sides of the merge together. Cells of the columns named in |textMergeCols| that were changed on both sides are
+// merged line by line.
+func mergeProllyTable(ctx context.Context, tm *TableMerger, mergedSch schema.Schema, rewriteRows bool, textMergeCols []string) (*doltdb.Table, *MergeStats, error) {
 	mergeTbl, err := mergeTableArtifacts(ctx, tm, tm.leftTbl)
 	if err != nil {
 		return nil, nil, err
@@ -71,6 +73,7 @@ func mergeProllyTable(ctx context.Context, tm *TableMerger, mergedSch schema.Sch
 	}
 	leftRows := durable.ProllyMapFromIndex(lr)
 	valueMerger := newValueMerger(mergedSch, tm.leftSch, tm.rightSch, tm.ancSch, leftRows.Pool())
+	valueMerger.enableTextMerge(mergedSch, textMergeCols, leftRows.NodeStore())
 	leftMapping := valueMerger.leftMapping
 
 	// We need a sql.Context to apply column default values in merges; if we don't have one already,
@@ -250,6 +253,7 @@ func mergeProllyTableData(ctx *sql.Context, tm *TableMerger, finalSch schema.Sch
 			// In this case, both sides of the merge have made different changes to a row, but we were able to
 			// resolve them automatically.
 			s.Modifications++
+			s.AutoMergedRows++
 			err = pri.merge(ctx, diff, nil)
 			if err != nil {
 				return nil, nil, err
@@ -273,6 +277,8 @@ func mergeProllyTableData(ctx *sql.Context, tm *TableMerger, finalSch schema.Sch
 		}
 	}
 
+	s.TextMergedCells = valueMerger.textMergedCells
+
 	finalRows, err := pri.finalize(ctx)
 	if err != nil {
 		return nil, nil, err
@@ -1372,6 +1378,13 @@ type valueMerger struct {
 	leftMapping, rightMapping, baseMapping val.OrdinalMapping
 	syncPool                               pool.BuffPool
 	keyless                                bool
+
+	// textMerge is true for the columns whose cells are merged line by line, and is nil if there are none.
+	textMerge []bool
+	ns        tree.NodeStore
+	// textMergedCells counts the cells merged line by line in rows that were merged successfully, and
+	// rowTextMerges the cells merged line by line so far in the row being merged.
+	textMergedCells, rowTextMerges int
 }
 
 func newValueMerger(merged, leftSch, rightSch, baseSch schema.Schema, syncPool pool.BuffPool) *valueMerger {
@@ -1388,6 +1401,27 @@ func newValueMerger(merged, leftSch, rightSch, baseSch schema.Schema, syncPool p
 	}
 }
 
+// enableTextMerge turns on line by line merging of the cells of the TEXT columns of |merged| named in |cols|.
+func (m *valueMerger) enableTextMerge(merged schema.Schema, cols []string, ns tree.NodeStore) {
+	if len(cols) == 0 || m.keyless {
+		return
+	}
+	m.ns = ns
+	for i, col := range merged.GetNonPKCols().GetColumns() {
+		if m.vD.Types[i].Enc != val.StringAddrEnc {
+			continue
+		}
+		for _, name := range cols {
+			if strings.EqualFold(col.Name, name) {
+				if m.textMerge == nil {
+					m.textMerge = make([]bool, m.numCols)
+				}
+				m.textMerge[i] = true
+			}
+		}
+	}
+}
+
 // generateSchemaMappings returns three schema mappings: 1) mapping the |leftSch| to |mergedSch|,
 // 2) mapping |rightSch| to |mergedSch|, and 3) mapping |baseSch| to |mergedSch|. Columns are
 // mapped from the source schema to destination schema by finding an identical tag, or if no
@@ -1499,17 +1533,17 @@ func migrateDataToMergedSchema(ctx *sql.Context, tm *TableMerger, vm *valueMerge
 // tuples. It returns the merged cell value tuple and a bool indicating if a
 // conflict occurred. tryMerge should only be called if left and right produce
 // non-identical diffs against base.
-func (m *valueMerger) tryMerge(left, right, base val.Tuple) (val.Tuple, bool) {
+func (m *valueMerger) tryMerge(ctx context.Context, left, right, base val.Tuple) (val.Tuple, bool, error) {
 	// If we're merging a keyless table and the keys match, but the values are different,
 	// that means that the row data is the same, but the cardinality has changed, and if the
 	// cardinality has changed in different ways on each merge side, we can't auto resolve.
 	if m.keyless {
-		return nil, false
+		return nil, false, nil
 	}
 
 	if base != nil && (left == nil) != (right == nil) {
 		// One row deleted, the other modified
-		return nil, false
+		return nil, false, nil
 	}
 
 	// Because we have non-identical diffs, left and right are guaranteed to be
@@ -1518,21 +1552,26 @@ func (m *valueMerger) tryMerge(left, right, base val.Tuple) (val.Tuple, bool) {
 		panic("found nil left / right which should never occur")
 	}
 
+	m.rowTextMerges = 0
 	mergedValues := make([][]byte, m.numCols)
 	for i := 0; i < m.numCols; i++ {
-		v, isConflict := m.processColumn(i, left, right, base)
+		v, isConflict, err := m.processColumn(ctx, i, left, right, base)
+		if err != nil {
+			return nil, false, err
+		}
 		if isConflict {
-			return nil, false
+			return nil, false, nil
 		}
 		mergedValues[i] = v
 	}
 
-	return val.NewTuple(m.syncPool, mergedValues...), true
+	m.textMergedCells += m.rowTextMerges
+	return val.NewTuple(m.syncPool, mergedValues...), true, nil
 }
 
 // processColumn returns the merged value of column |i| of the merged schema,
 // based on the |left|, |right|, and |base| schema.
-func (m *valueMerger) processColumn(i int, left, right, base val.Tuple) ([]byte, bool) {
+func (m *valueMerger) processColumn(ctx context.Context, i int, left, right, base val.Tuple) ([]byte, bool, error) {
 	// missing columns are coerced into NULL column values
 	var leftCol []byte
 	if l := m.leftMapping[i]; l != -1 {
@@ -1544,12 +1583,12 @@ func (m *valueMerger) processColumn(i int, left, right, base val.Tuple) ([]byte,
 	}
 
 	if m.vD.Comparator().CompareValues(i, leftCol, rightCol, m.vD.Types[i]) == 0 {
-		return leftCol, false
+		return leftCol, false, nil
 	}
 
 	if base == nil {
 		// Conflicting insert
-		return nil, true
+		return nil, true, nil
 	}
 
 	var baseVal []byte
@@ -1562,10 +1601,47 @@ func (m *valueMerger) processColumn(i int, left, right, base val.Tuple) ([]byte,
 
 	switch {
 	case leftModified && rightModified:
-		return nil, true
+		if m.textMerge == nil || !m.textMerge[i] {
+			return nil, true, nil
+		}
+		merged, ok, err := m.mergeTextCell(ctx, i, leftCol, rightCol, baseVal)
+		if err != nil || !ok {
+			return nil, true, err
+		}
+		m.rowTextMerges++
+		return merged, false, nil
 	case leftModified:
-		return leftCol, false
+		return leftCol, false, nil
 	default:
-		return rightCol, false
+		return rightCol, false, nil
+	}
+}
+
+// mergeTextCell merges the |left| and |right| values of the TEXT column |i| of the merged schema line by line, given
+// their |base| value. It returns false if any of the values is NULL, or if the two sides edited the same lines.
+func (m *valueMerger) mergeTextCell(ctx context.Context, i int, left, right, base []byte) ([]byte, bool, error) {
+	if left == nil || right == nil || base == nil {
+		return nil, false, nil
+	}
+
+	desc := val.NewTupleDescriptor(m.vD.Types[i])
+	var texts [3]string
+	for j, field := range [][]byte{base, left, right} {
+		v, err := index.GetField(ctx, desc, 0, val.NewTuple(m.syncPool, field), m.ns)
+		if err != nil {
+			return nil, false, err
+		}
+		texts[j] = v.(string)
+	}
+
+	merged, ok := mergeTextLines(texts[0], texts[1], texts[2])
+	if !ok {
+		return nil, false, nil
+	}
+
+	tb := val.NewTupleBuilder(desc)
+	if err := index.PutField(ctx, m.ns, tb, 0, merged); err != nil {
+		return nil, false, err
 	}
+	return tb.Build(m.syncPool).GetField(0), true, nil
 }
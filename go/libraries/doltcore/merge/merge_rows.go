@@ -16,6 +16,7 @@ package merge
 
 import (
 	"context"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
@@ -37,6 +38,31 @@ type MergeOpts struct {
 	// KeepSchemaConflicts if schema conflicts should be
 	// stored, otherwise we end the merge with an error.
 	KeepSchemaConflicts bool
+	// PruneViolations lists the types of constraint violations whose rows are deleted by the merge instead of
+	// being recorded as violations. A row is only deleted if every merge artifact for it is a violation of one of
+	// these types. Only supported for the new storage format.
+	PruneViolations []CvType
+	// OnlyTables limits the merge to the tables named, which are compared case-insensitively. Other tables, and the
+	// foreign keys declared on them, are left as they are on the left side of the merge. All tables are merged if
+	// it's empty.
+	OnlyTables []string
+	// TextMergeColumns lists the TEXT columns, named "table.column" and compared case-insensitively, whose cells are
+	// merged line by line when both sides of the merge change the same cell of a row. Edits to different lines of the
+	// cell are combined, while edits to the same or adjacent lines remain a conflict. Only supported for the new
+	// storage format.
+	TextMergeColumns []string
+}
+
+// textMergeColumns returns the names of the columns of the table |tblName| listed in TextMergeColumns.
+func (mo MergeOpts) textMergeColumns(tblName string) []string {
+	var cols []string
+	prefix := strings.ToLower(tblName) + "."
+	for _, name := range mo.TextMergeColumns {
+		if strings.HasPrefix(strings.ToLower(name), prefix) {
+			cols = append(cols, name[len(prefix):])
+		}
+	}
+	return cols
 }
 
 type TableMerger struct {
@@ -148,13 +174,14 @@ func (rm *RootMerger) MergeTable(ctx *sql.Context, tblName string, opts editor.O
 
 	var tbl *doltdb.Table
 	if types.IsFormat_DOLT(tm.vrw.Format()) {
-		tbl, stats, err = mergeProllyTable(ctx, tm, mergeSch, tableRewrite)
+		tbl, stats, err = mergeProllyTable(ctx, tm, mergeSch, tableRewrite, mergeOpts.textMergeColumns(tblName))
 	} else {
 		tbl, stats, err = mergeNomsTable(ctx, tm, mergeSch, rm.vrw, opts)
 	}
 	if err != nil {
 		return nil, nil, err
 	}
+	stats.AutoResolvedSchemaConflicts = len(schConflicts.AutoResolved)
 	return &MergedTable{table: tbl}, stats, nil
 }
 
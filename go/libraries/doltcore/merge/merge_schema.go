@@ -52,6 +52,10 @@ type SchemaConflict struct {
 	ColConflicts []ColConflict
 	IdxConflicts []IdxConflict
 	ChkConflicts []ChkConflict
+	// AutoResolved are the columns that were changed differently on both sides of the merge, but only by widening their
+	// types, so that the merge took the wider of the two types instead of reporting a conflict. They aren't included in
+	// Count.
+	AutoResolved []ColConflict
 }
 
 var _ error = SchemaConflict{}
@@ -168,7 +172,7 @@ func SchemaMerge(ctx context.Context, format *storetypes.NomsBinFormat, ourSch,
 	}
 
 	var mergedCC *schema.ColCollection
-	mergedCC, sc.ColConflicts, tableRewrite, err = mergeColumns(tblName, format, ourSch.GetAllCols(), theirSch.GetAllCols(), ancSch.GetAllCols())
+	mergedCC, sc.ColConflicts, sc.AutoResolved, tableRewrite, err = mergeColumns(tblName, format, ourSch.GetAllCols(), theirSch.GetAllCols(), ancSch.GetAllCols())
 	if err != nil {
 		return nil, SchemaConflict{}, false, err
 	}
@@ -366,28 +370,31 @@ func checkUnmergeableNewColumns(tblName string, columnMappings columnMappings) e
 // conflicting changes to the columns in |ourCC| and |theirCC|, then a set of ColConflict instances are returned
 // describing the conflicts. |format| indicates what storage format is in use, and is needed to determine compatibility
 // between types, since different storage formats have different restrictions on how much types can change and remain
-// compatible with the current stored format. The merged columns, any column conflicts, and a boolean value stating if
-// a full table rewrite is needed to align the existing table rows with the new, merged schema. If any unexpected error
-// occurs, then that error is returned and the other response fields should be ignored.
-func mergeColumns(tblName string, format *storetypes.NomsBinFormat, ourCC, theirCC, ancCC *schema.ColCollection) (*schema.ColCollection, []ColConflict, bool, error) {
+// compatible with the current stored format. When a column's type was changed differently on both sides, but one of
+// the new types can hold every value of the other, the wider type is used and the column is returned in the list of
+// auto-resolved conflicts. The merged columns, any column conflicts, any auto-resolved conflicts, and a boolean value
+// stating if a full table rewrite is needed to align the existing table rows with the new, merged schema. If any
+// unexpected error occurs, then that error is returned and the other response fields should be ignored.
+func mergeColumns(tblName string, format *storetypes.NomsBinFormat, ourCC, theirCC, ancCC *schema.ColCollection) (*schema.ColCollection, []ColConflict, []ColConflict, bool, error) {
 	columnMappings, err := mapColumns(ourCC, theirCC, ancCC)
 	if err != nil {
-		return nil, nil, false, err
+		return nil, nil, nil, false, err
 	}
 
-	conflicts, err := checkSchemaConflicts(columnMappings)
+	compatChecker := newTypeCompatabilityCheckerForStorageFormat(format)
+
+	conflicts, err := checkSchemaConflicts(columnMappings, compatChecker)
 	if err != nil {
-		return nil, nil, false, err
+		return nil, nil, nil, false, err
 	}
 
 	err = checkUnmergeableNewColumns(tblName, columnMappings)
 	if err != nil {
-		return nil, nil, false, err
+		return nil, nil, nil, false, err
 	}
 
-	compatChecker := newTypeCompatabilityCheckerForStorageFormat(format)
-
 	tableRewrite := false
+	var autoResolved []ColConflict
 
 	// After we've checked for schema conflicts, merge the columns together
 	// TODO: We don't currently preserve all column position changes; the returned merged columns are always based on
@@ -416,9 +423,20 @@ func mergeColumns(tblName string, format *storetypes.NomsBinFormat, ourCC, their
 				theirsChanged := !anc.Equals(*theirs)
 				if oursChanged && theirsChanged {
 					// If both columns changed in the same way, the modifications converge, so accept the column.
-					// If not, don't report a conflict, since this case is already handled in checkForColumnConflicts.
+					// If one side only widened the type of the other, use the wider type. If not, don't report a
+					// conflict, since this case is already handled in checkSchemaConflicts.
 					if ours.Equals(*theirs) {
 						mergedColumns = append(mergedColumns, *theirs)
+					} else if wider, rewrite, ok := widerColumn(compatChecker, *ours, *theirs); ok {
+						if rewrite {
+							tableRewrite = true
+						}
+						mergedColumns = append(mergedColumns, wider)
+						autoResolved = append(autoResolved, ColConflict{
+							Kind:   TagCollision,
+							Ours:   *ours,
+							Theirs: *theirs,
+						})
 					}
 				} else if theirsChanged {
 					// In this case, only theirsChanged, so we need to check if moving from ours->theirs
@@ -469,10 +487,30 @@ func mergeColumns(tblName string, format *storetypes.NomsBinFormat, ourCC, their
 	// Check that there are no duplicate column names or tags in the merged column set
 	conflicts = append(conflicts, checkForColumnConflicts(mergedColumns)...)
 	if conflicts != nil {
-		return nil, conflicts, false, nil
+		return nil, conflicts, nil, false, nil
 	}
 
-	return schema.NewColCollection(mergedColumns...), nil, tableRewrite, nil
+	return schema.NewColCollection(mergedColumns...), nil, autoResolved, tableRewrite, nil
+}
+
+// widerColumn returns whichever of |ours| and |theirs| has the wider type, when the columns differ only in their types
+// and the other column's type can be changed to it compatibly, along with whether that type change requires a full
+// table rewrite. Returns false if neither type is a widening of the other, or the columns differ in other ways.
+func widerColumn(compatChecker TypeCompatibilityChecker, ours, theirs schema.Column) (schema.Column, bool, bool) {
+	sameType := ours
+	sameType.Kind = theirs.Kind
+	sameType.TypeInfo = theirs.TypeInfo
+	if !sameType.Equals(theirs) {
+		return schema.Column{}, false, false
+	}
+
+	if compatible, rewrite := compatChecker.IsTypeChangeCompatible(ours.TypeInfo, theirs.TypeInfo); compatible {
+		return theirs, rewrite, true
+	}
+	if compatible, rewrite := compatChecker.IsTypeChangeCompatible(theirs.TypeInfo, ours.TypeInfo); compatible {
+		return ours, rewrite, true
+	}
+	return schema.Column{}, false, false
 }
 
 // checkForColumnConflicts iterates over |mergedColumns|, checks for duplicate column names or column tags, and returns
@@ -507,8 +545,9 @@ func checkForColumnConflicts(mergedColumns []schema.Column) []ColConflict {
 }
 
 // checkSchemaConflicts iterates over |columnMappings| and returns any column schema conflicts from column changes
-// that can't be automatically merged.
-func checkSchemaConflicts(columnMappings columnMappings) ([]ColConflict, error) {
+// that can't be automatically merged. |compatChecker| is used to find columns whose type was widened differently on
+// each side, which can be merged by taking the wider type.
+func checkSchemaConflicts(columnMappings columnMappings, compatChecker TypeCompatibilityChecker) ([]ColConflict, error) {
 	var conflicts []ColConflict
 	for _, mapping := range columnMappings {
 		ours := mapping.ours
@@ -536,8 +575,12 @@ func checkSchemaConflicts(columnMappings columnMappings) ([]ColConflict, error)
 				}
 			case theirs != nil && anc != nil:
 				// Column exists on their side and in ancestor
-				// If the column differs from the ancestor on both sides, then we have a conflict
+				// If the column differs from the ancestor on both sides, then we have a conflict, unless one side's
+				// type is a widening of the other's
 				if !anc.Equals(*ours) && !anc.Equals(*theirs) {
+					if _, _, ok := widerColumn(compatChecker, *ours, *theirs); ok {
+						continue
+					}
 					conflicts = append(conflicts, ColConflict{
 						Kind:   TagCollision,
 						Ours:   *ours,
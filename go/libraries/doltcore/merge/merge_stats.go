@@ -31,6 +31,21 @@ type MergeStats struct {
 	DataConflicts        int
 	SchemaConflicts      int
 	ConstraintViolations int
+	// RowsRemovedForViolations is the number of rows deleted by the merge because they violated a constraint of a
+	// type listed in MergeOpts.PruneViolations.
+	RowsRemovedForViolations int
+	// AutoResolvedSchemaConflicts is the number of columns whose types were changed differently on both sides of the
+	// merge, which were resolved automatically by taking the wider type. They aren't counted in SchemaConflicts.
+	AutoResolvedSchemaConflicts int
+	// AutoMergedRows is the number of rows that were changed on both sides of the merge, in different columns, whose
+	// changes were merged automatically. They're also counted in Modifications. Rows changed the same way on both sides
+	// don't need merging and aren't counted.
+	AutoMergedRows int
+	// TextMergedCells is the number of cells of columns listed in MergeOpts.TextMergeColumns that were changed on both
+	// sides of the merge, whose changes were merged line by line. Their rows are also counted in AutoMergedRows.
+	TextMergedCells int
+	// Skipped is true if the table wasn't merged because it isn't listed in MergeOpts.OnlyTables.
+	Skipped bool
 }
 
 func (ms *MergeStats) HasArtifacts() bool {
@@ -0,0 +1,324 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+var cvTypeNames = map[string]CvType{
+	"foreign key":      CvType_ForeignKey,
+	"unique index":     CvType_UniqueIndex,
+	"check constraint": CvType_CheckConstraint,
+	"not null":         CvType_NotNull,
+}
+
+// ParseCvType returns the CvType named by |s|, which is one of the values of the violation_type column of the
+// dolt_constraint_violations_$table system tables, such as "foreign key".
+func ParseCvType(s string) (CvType, error) {
+	if t, ok := cvTypeNames[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return t, nil
+	}
+	return 0, fmt.Errorf("unknown constraint violation type '%s', expected one of 'foreign key', 'unique index', 'check constraint' or 'not null'", s)
+}
+
+// pruneViolatingRows deletes the rows of every table in |root| whose only merge artifacts are constraint violations
+// of the types in |cvTypes|, along with those violations. Only rows with at least one violation created by merging
+// |theirRootish| are deleted, so violations left over from earlier merges don't cause rows to be removed, and rows
+// that also have a conflict are left for the user to resolve. A unique index violation is recorded for both rows
+// sharing the unique value, so a row whose only violations are unique index violations is only deleted if the merge
+// brought it in, that is if it isn't in |ourRoot| unchanged. The number of rows deleted from each table is added to
+// its entry in |tblToStats|. Returns the updated root and the total number of rows deleted.
+func pruneViolatingRows(ctx context.Context, root, ourRoot *doltdb.RootValue, theirRootish hash.Hash, cvTypes []CvType, tblToStats map[string]*MergeStats) (*doltdb.RootValue, int, error) {
+	pruned := make(map[prolly.ArtifactType]bool, len(cvTypes))
+	for _, t := range cvTypes {
+		pruned[ArtifactTypeFromCvType(t)] = true
+	}
+
+	total := 0
+	for tblName, stats := range tblToStats {
+		tbl, ok, err := root.GetTable(ctx, tblName)
+		if err != nil {
+			return nil, 0, err
+		} else if !ok {
+			continue
+		}
+
+		ourTbl, _, err := ourRoot.GetTable(ctx, tblName)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		tbl, n, err := pruneTableViolatingRows(ctx, tbl, ourTbl, theirRootish, pruned)
+		if err != nil {
+			return nil, 0, err
+		} else if tbl == nil {
+			continue
+		}
+
+		root, err = root.PutTable(ctx, tblName, tbl)
+		if err != nil {
+			return nil, 0, err
+		}
+		stats.RowsRemovedForViolations += n
+		total += n
+	}
+
+	return root, total, nil
+}
+
+// pruneTableViolatingRows deletes the prunable rows of |tbl| and their violations, as described in
+// pruneViolatingRows. |ourTbl| is the table before the merge, or nil if it was added by the merge. The unique index
+// violations of our own rows are cleared once no incoming row with a unique index violation is left. Returns a nil
+// table if there was nothing to prune.
+func pruneTableViolatingRows(ctx context.Context, tbl, ourTbl *doltdb.Table, theirRootish hash.Hash, pruned map[prolly.ArtifactType]bool) (*doltdb.Table, int, error) {
+	arts, err := tbl.GetArtifacts(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	artM := durable.ProllyMapFromArtifactIndex(arts)
+
+	itr, err := artM.IterAll(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// group the artifacts by the row they're recorded for, and decide which rows can be deleted
+	type rowArtifacts struct {
+		srcKey   val.Tuple
+		artKeys  []val.Tuple
+		prunable bool
+		fromThis bool
+		// onlyUnique is true if all the row's artifacts are unique index violations
+		onlyUnique bool
+	}
+	var rows []*rowArtifacts
+	byKey := make(map[string]*rowArtifacts)
+	for {
+		art, err := itr.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, 0, err
+		}
+
+		ra, ok := byKey[string(art.SourceKey)]
+		if !ok {
+			ra = &rowArtifacts{srcKey: art.SourceKey, prunable: true, onlyUnique: true}
+			byKey[string(art.SourceKey)] = ra
+			rows = append(rows, ra)
+		}
+		ra.artKeys = append(ra.artKeys, art.ArtKey)
+		ra.prunable = ra.prunable && pruned[art.ArtType]
+		ra.fromThis = ra.fromThis || art.SourceRootish == theirRootish
+		ra.onlyUnique = ra.onlyUnique && art.ArtType == prolly.ArtifactTypeUniqueKeyViol
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	keyless := schema.IsKeyless(sch)
+
+	rowData, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	primary := durable.ProllyMapFromIndex(rowData)
+	primaryMut := primary.Mutate()
+
+	ours, err := newOurRows(ctx, ourTbl, sch)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	indexes, err := tbl.GetIndexSet(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	secondary, err := GetMutableSecondaryIdxs(ctx, sch, indexes)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	artEditor := artM.Editor()
+
+	changed := false
+	removed := 0
+	uniqueLeft := false
+	var ourUnique []*rowArtifacts
+	for _, ra := range rows {
+		var value val.Tuple
+		err = primary.Get(ctx, ra.srcKey, func(_, v val.Tuple) error {
+			value = v
+			return nil
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if ra.onlyUnique {
+			isOurs, err := ours.has(ctx, ra.srcKey, value)
+			if err != nil {
+				return nil, 0, err
+			}
+			if isOurs {
+				// the other row sharing the unique value is the one to delete
+				if ra.prunable && ra.fromThis {
+					ourUnique = append(ourUnique, ra)
+				}
+				continue
+			}
+		}
+
+		if !ra.prunable || !ra.fromThis {
+			uniqueLeft = uniqueLeft || ra.onlyUnique
+			continue
+		}
+		changed = true
+
+		if value != nil {
+			for _, idx := range secondary {
+				err = idx.DeleteEntry(ctx, ra.srcKey, value)
+				if err != nil {
+					return nil, 0, err
+				}
+			}
+			err = primaryMut.Delete(ctx, ra.srcKey)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			if keyless {
+				removed += int(val.ReadKeylessCardinality(value))
+			} else {
+				removed++
+			}
+		}
+
+		for _, artKey := range ra.artKeys {
+			err = artEditor.Delete(ctx, artKey)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+
+	if !uniqueLeft {
+		for _, ra := range ourUnique {
+			changed = true
+			for _, artKey := range ra.artKeys {
+				err = artEditor.Delete(ctx, artKey)
+				if err != nil {
+					return nil, 0, err
+				}
+			}
+		}
+	}
+
+	if !changed {
+		return nil, 0, nil
+	}
+
+	primary, err = primaryMut.Map(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	tbl, err = tbl.UpdateRows(ctx, durable.IndexFromProllyMap(primary))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, idx := range secondary {
+		idxMap, err := idx.Map(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		indexes, err = indexes.PutIndex(ctx, idx.Name, durable.IndexFromProllyMap(idxMap))
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	tbl, err = tbl.SetIndexSet(ctx, indexes)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	artM, err = artEditor.Flush(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	tbl, err = tbl.SetArtifacts(ctx, durable.ArtifactIndexFromProllyMap(artM))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return tbl, removed, nil
+}
+
+// ourRows finds the rows of a table as it was on our side of a merge.
+type ourRows struct {
+	m prolly.Map
+	// sameSchema is true if rows can be compared with the merged table's by value
+	sameSchema bool
+	ok         bool
+}
+
+func newOurRows(ctx context.Context, ourTbl *doltdb.Table, mergedSch schema.Schema) (ourRows, error) {
+	if ourTbl == nil {
+		return ourRows{}, nil
+	}
+	ourSch, err := ourTbl.GetSchema(ctx)
+	if err != nil {
+		return ourRows{}, err
+	}
+	rowData, err := ourTbl.GetRowData(ctx)
+	if err != nil {
+		return ourRows{}, err
+	}
+	return ourRows{
+		m:          durable.ProllyMapFromIndex(rowData),
+		sameSchema: schema.SchemasAreEqual(ourSch, mergedSch),
+		ok:         true,
+	}, nil
+}
+
+// has returns whether the merged row with |key| and |value| is one of our rows that the merge left unchanged. When the
+// merge changed the table's schema, rows can't be compared by value and any row with the same key is considered ours.
+func (o ourRows) has(ctx context.Context, key, value val.Tuple) (bool, error) {
+	if !o.ok {
+		return false, nil
+	}
+	var ourValue val.Tuple
+	err := o.m.Get(ctx, key, func(_, v val.Tuple) error {
+		ourValue = v
+		return nil
+	})
+	if err != nil || ourValue == nil {
+		return false, err
+	}
+	return !o.sameSchema || bytes.Equal(ourValue, value), nil
+}
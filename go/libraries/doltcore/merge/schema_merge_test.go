@@ -346,6 +346,21 @@ var typeChangeTests = []schemaMergeTest{
 		right:    tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a char(20), b int)"), row(1, "2", 3)),
 		merged:   tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a char(20), b int)"), row(1, "2", 3)),
 	},
+	{
+		name:                "widen column type differently on both sides",
+		ancestor:            tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a varchar(10), b int)"), row(1, "2", 3)),
+		left:                tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a varchar(20), b int)"), row(1, "2", 3)),
+		right:               tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a varchar(30), b int)"), row(1, "2", 3)),
+		merged:              tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a varchar(30), b int)"), row(1, "2", 3)),
+		skipFlipOnOldFormat: true,
+	},
+	{
+		name:     "incompatibly modify column type on both sides",
+		ancestor: tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a varchar(10), b int)"), row(1, "2", 3)),
+		left:     tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a varchar(20), b int)"), row(1, "2", 3)),
+		right:    tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a int, b int)        "), row(1, 2, 3)),
+		conflict: true,
+	},
 	// column changes one side, data changes other side
 }
 
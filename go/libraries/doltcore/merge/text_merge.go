@@ -0,0 +1,131 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// lineEdit replaces the lines [start, end) of a base text with |lines|.
+type lineEdit struct {
+	start, end int
+	lines      []string
+}
+
+func (e lineEdit) equals(other lineEdit) bool {
+	if e.start != other.start || e.end != other.end || len(e.lines) != len(other.lines) {
+		return false
+	}
+	for i := range e.lines {
+		if e.lines[i] != other.lines[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeTextLines three-way merges the |left| and |right| versions of the text |base| line by line. Edits made by
+// only one side are applied, as are identical edits made by both sides. It returns false if the two sides made
+// different edits to the same lines, or to adjacent lines, which are treated as a conflict the same way git does.
+func mergeTextLines(base, left, right string) (string, bool) {
+	baseLines := splitLines(base)
+	leftEdits, rightEdits := lineEdits(base, left), lineEdits(base, right)
+
+	var sb strings.Builder
+	pos, i, j := 0, 0, 0
+	for i < len(leftEdits) || j < len(rightEdits) {
+		var e lineEdit
+		switch {
+		case j == len(rightEdits):
+			e = leftEdits[i]
+			i++
+		case i == len(leftEdits):
+			e = rightEdits[j]
+			j++
+		case leftEdits[i].equals(rightEdits[j]):
+			e = leftEdits[i]
+			i++
+			j++
+		case leftEdits[i].start <= rightEdits[j].end && rightEdits[j].start <= leftEdits[i].end:
+			return "", false
+		case leftEdits[i].start < rightEdits[j].start:
+			e = leftEdits[i]
+			i++
+		default:
+			e = rightEdits[j]
+			j++
+		}
+
+		for _, line := range baseLines[pos:e.start] {
+			sb.WriteString(line)
+		}
+		for _, line := range e.lines {
+			sb.WriteString(line)
+		}
+		pos = e.end
+	}
+	for _, line := range baseLines[pos:] {
+		sb.WriteString(line)
+	}
+
+	return sb.String(), true
+}
+
+// lineEdits returns the edits that turn |base| into |other|, ordered by their position in |base|.
+func lineEdits(base, other string) []lineEdit {
+	dmp := diffmatchpatch.New()
+	baseChars, otherChars, lineArray := dmp.DiffLinesToChars(base, other)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(baseChars, otherChars, false), lineArray)
+
+	var edits []lineEdit
+	pos, inEdit := 0, false
+	for _, d := range diffs {
+		lines := splitLines(d.Text)
+		if d.Type == diffmatchpatch.DiffEqual {
+			pos += len(lines)
+			inEdit = false
+			continue
+		}
+
+		if !inEdit {
+			edits = append(edits, lineEdit{start: pos, end: pos})
+			inEdit = true
+		}
+		e := &edits[len(edits)-1]
+		if d.Type == diffmatchpatch.DiffDelete {
+			e.end += len(lines)
+			pos += len(lines)
+		} else {
+			e.lines = append(e.lines, lines...)
+		}
+	}
+
+	return edits
+}
+
+// splitLines splits |s| into lines, keeping the line ending of each line. The last line has no line ending if |s|
+// doesn't end with a newline.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
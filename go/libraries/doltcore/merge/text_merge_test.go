@@ -0,0 +1,95 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeTextLines(t *testing.T) {
+	tests := []struct {
+		name              string
+		base, left, right string
+		expected          string
+		conflict          bool
+	}{
+		{
+			name:     "edits to different lines",
+			base:     "one\ntwo\nthree\nfour\n",
+			left:     "ONE\ntwo\nthree\nfour\n",
+			right:    "one\ntwo\nthree\nFOUR\n",
+			expected: "ONE\ntwo\nthree\nFOUR\n",
+		},
+		{
+			name:     "insert and delete",
+			base:     "one\ntwo\nthree\nfour\n",
+			left:     "zero\none\ntwo\nthree\nfour\n",
+			right:    "one\ntwo\nthree\n",
+			expected: "zero\none\ntwo\nthree\n",
+		},
+		{
+			name:     "identical edits",
+			base:     "one\ntwo\nthree\n",
+			left:     "one\nTWO\nthree\nfour\n",
+			right:    "one\nTWO\nthree\n",
+			expected: "one\nTWO\nthree\nfour\n",
+		},
+		{
+			name:     "last line without newline",
+			base:     "one\ntwo\nthree",
+			left:     "ONE\ntwo\nthree",
+			right:    "one\ntwo\nTHREE",
+			expected: "ONE\ntwo\nTHREE",
+		},
+		{
+			name:     "edits to the same line",
+			base:     "one\ntwo\nthree\n",
+			left:     "one\n2\nthree\n",
+			right:    "one\nTWO\nthree\n",
+			conflict: true,
+		},
+		{
+			name:     "edits to adjacent lines",
+			base:     "one\ntwo\nthree\n",
+			left:     "one\nTWO\nthree\n",
+			right:    "one\ntwo\nTHREE\n",
+			conflict: true,
+		},
+		{
+			name:     "inserts at the same place",
+			base:     "one\ntwo\n",
+			left:     "one\nleft\ntwo\n",
+			right:    "one\nright\ntwo\n",
+			conflict: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged, ok := mergeTextLines(test.base, test.left, test.right)
+			assert.Equal(t, !test.conflict, ok)
+			if ok {
+				assert.Equal(t, test.expected, merged)
+			}
+			merged, ok = mergeTextLines(test.base, test.right, test.left)
+			assert.Equal(t, !test.conflict, ok)
+			if ok {
+				assert.Equal(t, test.expected, merged)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ package encoding
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sync"
@@ -147,6 +148,36 @@ func (enc encodedTypeInfo) decodeTypeInfo() (typeinfo.TypeInfo, error) {
 	return typeinfo.FromTypeParams(id, enc.Params)
 }
 
+// jsonTypeInfo is the JSON encoding of encodedTypeInfo. Some type params, such as the values of enums and sets, are
+// binary, and JSON strings can only hold valid UTF-8, so params are written as base64 encoded bytes instead.
+type jsonTypeInfo struct {
+	Type   string            `json:"type"`
+	Params map[string][]byte `json:"params"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (enc encodedTypeInfo) MarshalJSON() ([]byte, error) {
+	params := make(map[string][]byte, len(enc.Params))
+	for k, v := range enc.Params {
+		params[k] = []byte(v)
+	}
+	return json.Marshal(jsonTypeInfo{Type: enc.Type, Params: params})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (enc *encodedTypeInfo) UnmarshalJSON(data []byte) error {
+	var ti jsonTypeInfo
+	if err := json.Unmarshal(data, &ti); err != nil {
+		return err
+	}
+	enc.Type = ti.Type
+	enc.Params = make(map[string]string, len(ti.Params))
+	for k, v := range ti.Params {
+		enc.Params[k] = string(v)
+	}
+	return nil
+}
+
 type encodedIndex struct {
 	Name            string              `noms:"name" json:"name"`
 	Tags            []uint64            `noms:"tags" json:"tags"`
@@ -410,6 +441,28 @@ func MarshalSchema(ctx context.Context, vrw types.ValueReadWriter, sch schema.Sc
 	return types.EmptyStruct(vrw.Format()), errors.New("Table Schema could not be converted to types.Struct")
 }
 
+// MarshalSchemaAsJSON converts a Schema to a JSON document, using the same field names as the noms encoding of
+// schemas. Column tags are preserved. The result can be converted back into a Schema with UnmarshalSchemaFromJSON.
+func MarshalSchemaAsJSON(sch schema.Schema) ([]byte, error) {
+	sd, err := toSchemaData(sch)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(sd)
+}
+
+// UnmarshalSchemaFromJSON converts a JSON document produced by MarshalSchemaAsJSON back into a Schema.
+func UnmarshalSchemaFromJSON(data []byte) (schema.Schema, error) {
+	var sd encodedSchemaData
+	err := json.Unmarshal(data, &sd)
+	if err != nil {
+		return nil, err
+	}
+
+	return sd.decodeSchema()
+}
+
 type schCacheData struct {
 	schema schema.Schema
 }
@@ -279,6 +279,17 @@ func TestSchemaMarshalling(t *testing.T) {
 	}
 }
 
+func TestSchemaJSONMarshalling(t *testing.T) {
+	schemas := append(getSchemas(t, 100), createTestSchema())
+	for _, sch := range schemas {
+		data, err := MarshalSchemaAsJSON(sch)
+		require.NoError(t, err)
+		s, err := UnmarshalSchemaFromJSON(data)
+		require.NoError(t, err)
+		assert.Equal(t, sch, s)
+	}
+}
+
 func getTypeinfo(t *testing.T) (ti []typeinfo.TypeInfo) {
 	st := getSqlTypes()
 	ti = make([]typeinfo.TypeInfo, len(st))
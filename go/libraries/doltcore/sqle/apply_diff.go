@@ -0,0 +1,249 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/store/pool"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+var ErrApplyDiffConflict = errors.NewKind("cannot apply %s row diff to table %s: the current row %s does not match the expected row %s")
+var ErrApplyDiffKeyless = errors.NewKind("cannot apply a row diff to table %s: diffs can only be applied to tables with a primary key")
+
+// RowDiffIter is a stream of row changes that can be applied to a table with Database.ApplyDiff. It is implemented by
+// *dtables.RowDiffIter, as returned by Database.DiffRows.
+type RowDiffIter interface {
+	// Next returns the next changed row. |diffType| is one of "added", "modified" or "removed", and |from| and |to|
+	// are the row before and after the change. |from| is nil for added rows and |to| is nil for removed rows. Returns
+	// io.EOF when there are no more changes.
+	Next(ctx *sql.Context) (diffType string, from, to sql.Row, err error)
+}
+
+// rowDiffOp is a single validated change to be written by ApplyDiff.
+type rowDiffOp struct {
+	current, to sql.Row
+}
+
+// ApplyDiff applies the row changes read from |diffIter| to the table named in the working set. Rows must be in the
+// table's current schema. Each change is checked before anything is written, against the table as left by the changes
+// before it in the stream: an added row must not exist yet, and a modified or removed row must currently match the
+// diff's |from| row. If any change doesn't apply,
+// ErrApplyDiffConflict is returned and the table is left unchanged. Otherwise all the changes are written with a single
+// update of the working root. Only tables with a primary key are supported.
+func (db Database) ApplyDiff(ctx *sql.Context, tableName string, diffIter RowDiffIter) error {
+	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
+		return err
+	}
+
+	stbl, ok, err := db.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return err
+	} else if !ok {
+		return sql.ErrTableNotFound.New(tableName)
+	}
+
+	var tbl *WritableDoltTable
+	switch t := stbl.(type) {
+	case *AlterableDoltTable:
+		tbl = &t.WritableDoltTable
+	case *WritableDoltTable:
+		tbl = t
+	default:
+		return fmt.Errorf("cannot apply a row diff to table %s", tableName)
+	}
+	if schema.IsKeyless(tbl.sch) {
+		return ErrApplyDiffKeyless.New(tbl.tableName)
+	}
+
+	// Validate the whole diff before writing anything, so that a conflict doesn't leave a partially applied diff in
+	// the session's table writer. |pending| has the rows left by the changes validated so far, by primary key, with
+	// nil for removed rows, so that later changes to the same row are checked against them.
+	keys := newPrimaryKeyEncoder(tbl.sch, tbl.sqlSch.PkOrdinals, db.ddb.NodeStore())
+	pending := make(map[string]sql.Row)
+	var ops []rowDiffOp
+	for {
+		diffType, from, to, err := diffIter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		key := to
+		if diffType != "added" {
+			key = from
+		}
+		if len(key) != len(tbl.sqlSch.Schema) || (to != nil && len(to) != len(tbl.sqlSch.Schema)) {
+			return fmt.Errorf("cannot apply %s row diff to table %s: expected rows with %d columns", diffType, tbl.tableName, len(tbl.sqlSch.Schema))
+		}
+
+		k, err := keys.encode(ctx, key)
+		if err != nil {
+			return err
+		}
+		current, ok := pending[k]
+		if !ok {
+			current, err = tbl.rowForPrimaryKey(ctx, key)
+			if err != nil {
+				return err
+			}
+		}
+
+		switch diffType {
+		case "added":
+			if current != nil {
+				return ErrApplyDiffConflict.New(diffType, tbl.tableName, sql.FormatRow(current), "<none>")
+			}
+		case "modified", "removed":
+			matches := current != nil
+			if matches {
+				matches, err = current.Equals(from, tbl.sqlSch.Schema)
+				if err != nil {
+					return err
+				}
+			}
+			if !matches {
+				currentStr := "<none>"
+				if current != nil {
+					currentStr = sql.FormatRow(current)
+				}
+				return ErrApplyDiffConflict.New(diffType, tbl.tableName, currentStr, sql.FormatRow(from))
+			}
+		default:
+			return fmt.Errorf("unknown diff type %s", diffType)
+		}
+
+		pending[k] = to
+		ops = append(ops, rowDiffOp{current: current, to: to})
+	}
+
+	if len(ops) == 0 {
+		return nil
+	}
+
+	// All three edit interfaces share the session's table writer, which writes its edits to the working root once
+	// when it's closed.
+	ed, err := tbl.getTableEditor(ctx)
+	if err != nil {
+		return err
+	}
+	for _, op := range ops {
+		switch {
+		case op.current == nil:
+			err = ed.Insert(ctx, op.to)
+		case op.to == nil:
+			err = ed.Delete(ctx, op.current)
+		default:
+			err = ed.Update(ctx, op.current, op.to)
+		}
+		if err != nil {
+			ed.Close(ctx)
+			return err
+		}
+	}
+
+	return ed.Close(ctx)
+}
+
+// primaryKeyEncoder encodes the primary key of rows as key tuples, to use them as map keys.
+type primaryKeyEncoder struct {
+	pkOrdinals []int
+	ns         tree.NodeStore
+	kb         *val.TupleBuilder
+}
+
+func newPrimaryKeyEncoder(sch schema.Schema, pkOrdinals []int, ns tree.NodeStore) *primaryKeyEncoder {
+	return &primaryKeyEncoder{
+		pkOrdinals: pkOrdinals,
+		ns:         ns,
+		kb:         val.NewTupleBuilder(sch.GetKeyDescriptor()),
+	}
+}
+
+// encode returns the bytes of the key tuple for the primary key of |row|.
+func (e *primaryKeyEncoder) encode(ctx *sql.Context, row sql.Row) (string, error) {
+	for i, ord := range e.pkOrdinals {
+		if err := index.PutField(ctx, e.ns, e.kb, i, row[ord]); err != nil {
+			e.kb.Recycle()
+			return "", err
+		}
+	}
+	return string(e.kb.BuildPermissive(keyPool)), nil
+}
+
+var keyPool = pool.NewBuffPool()
+
+// rowForPrimaryKey returns the row of this table with the same primary key as |row|, or nil if there isn't one.
+func (t *DoltTable) rowForPrimaryKey(ctx *sql.Context, row sql.Row) (sql.Row, error) {
+	key := make([]interface{}, len(t.sqlSch.PkOrdinals))
+	for i, ord := range t.sqlSch.PkOrdinals {
+		key[i] = row[ord]
+	}
+	return t.rowForKey(ctx, key)
+}
+
+// rowForKey returns the row of this table whose primary key columns have the values in |key|, given in primary key
+// order, or nil if there isn't one.
+func (t *DoltTable) rowForKey(ctx *sql.Context, key []interface{}) (sql.Row, error) {
+	indexes, err := t.GetIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pkIndex sql.Index
+	for _, idx := range indexes {
+		if idx.ID() == "PRIMARY" {
+			pkIndex = idx
+			break
+		}
+	}
+	if pkIndex == nil {
+		return nil, fmt.Errorf("could not find primary key index on table `%s`", t.tableName)
+	}
+
+	builder := sql.NewIndexBuilder(pkIndex)
+	exprs := pkIndex.Expressions()
+	for i, v := range key {
+		builder = builder.Equals(ctx, exprs[i], v)
+	}
+	lookup, err := builder.Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := index.RowIterForIndexLookup(ctx, t, lookup, t.sqlSch, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close(ctx)
+
+	r, err := iter.Next(ctx)
+	if err == io.EOF {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
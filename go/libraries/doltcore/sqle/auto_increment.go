@@ -0,0 +1,190 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+var ErrNoAutoIncrementColumn = errors.NewKind("table %s does not have an auto increment column")
+
+// AutoIncrementClampedWarningCode is the warning code used when an explicitly set auto increment value is raised to
+// preserve the invariant that auto increment values are never reused across branches. 1105 is ER_UNKNOWN_ERROR.
+const AutoIncrementClampedWarningCode int = 1105
+
+// GetAutoIncrementValue returns the next auto increment value for the table named, as tracked across all branches of
+// this database. Returns ErrNoAutoIncrementColumn if the table doesn't have an auto increment column.
+func (db Database) GetAutoIncrementValue(ctx *sql.Context, tableName string) (uint64, error) {
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	tbl, tableName, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return 0, err
+	} else if !ok {
+		return 0, sql.ErrTableNotFound.New(tableName)
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !schema.HasAutoIncrement(sch) {
+		return 0, ErrNoAutoIncrementColumn.New(tableName)
+	}
+
+	ait, err := db.gs.AutoIncrementTracker(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return ait.Current(tableName), nil
+}
+
+// SetAutoIncrementValue sets the next auto increment value for the table named. Values lower than the highest in use
+// on any branch are raised to it, with a warning.
+func (db Database) SetAutoIncrementValue(ctx *sql.Context, tableName string, val uint64) error {
+	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
+		return err
+	}
+
+	ws, err := db.GetWorkingSet(ctx)
+	if err != nil {
+		return err
+	}
+	root := ws.WorkingRoot()
+
+	tbl, tableName, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return err
+	} else if !ok {
+		return sql.ErrTableNotFound.New(tableName)
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+	if !schema.HasAutoIncrement(sch) {
+		return ErrNoAutoIncrementColumn.New(tableName)
+	}
+
+	ait, err := db.gs.AutoIncrementTracker(ctx)
+	if err != nil {
+		return err
+	}
+
+	tbl, err = ait.Set(ctx, tableName, tbl, ws.Ref(), val)
+	if err != nil {
+		return err
+	}
+
+	newRoot, err := root.PutTable(ctx, tableName, tbl)
+	if err != nil {
+		return err
+	}
+
+	if current := ait.Current(tableName); current > val {
+		ctx.Warn(AutoIncrementClampedWarningCode, fmt.Sprintf("auto_increment value for table %s was raised from %d to %d, the next value it can take without reusing a value on any branch", tableName, val, current))
+	}
+
+	return db.SetRoot(ctx, newRoot)
+}
+
+// TruncateAllOpts are the options for Database.TruncateAllTables.
+type TruncateAllOpts struct {
+	// PreserveDocs leaves the rows of dolt_docs in place.
+	PreserveDocs bool
+	// ResetAutoIncrement resets the auto-increment value of every table to 1, as TRUNCATE TABLE does. Otherwise, each
+	// table keeps the auto-increment value it had.
+	ResetAutoIncrement bool
+}
+
+// TruncateAllTables deletes the rows of every user table in the working set, and of dolt_docs unless |opts| says not
+// to, in a single change to the working set.
+func (db Database) TruncateAllTables(ctx *sql.Context, opts TruncateAllOpts) error {
+	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
+		return err
+	}
+
+	ws, err := db.GetWorkingSet(ctx)
+	if err != nil {
+		return err
+	}
+	root := ws.WorkingRoot()
+
+	names, err := root.GetTableNames(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		// full-text index tables are emptied along with their parent tables
+		if doltdb.HasDoltPrefix(name) && !doltdb.IsFullTextTable(name) && (name != doltdb.DocTableName || opts.PreserveDocs) {
+			continue
+		}
+
+		tbl, ok, err := root.GetTable(ctx, name)
+		if err != nil {
+			return err
+		} else if !ok {
+			return sql.ErrTableNotFound.New(name)
+		}
+		sch, err := tbl.GetSchema(ctx)
+		if err != nil {
+			return err
+		}
+
+		newTbl, err := emptyTable(ctx, tbl, sch)
+		if err != nil {
+			return err
+		}
+
+		if schema.HasAutoIncrement(sch) {
+			if opts.ResetAutoIncrement {
+				err = db.removeTableFromAutoIncrementTracker(ctx, name, db.ddb, ws.Ref())
+				if err != nil {
+					return err
+				}
+			} else {
+				autoIncVal, err := tbl.GetAutoIncrementValue(ctx)
+				if err != nil {
+					return err
+				}
+				newTbl, err = newTbl.SetAutoIncrementValue(ctx, autoIncVal)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		root, err = root.PutTable(ctx, name, newTbl)
+		if err != nil {
+			return err
+		}
+	}
+
+	return db.SetRoot(ctx, root)
+}
@@ -0,0 +1,311 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var ErrNothingToCommit = errors.NewKind("nothing to commit")
+var ErrTableNotCommitted = errors.NewKind("table %s has not been committed")
+var ErrPartialCommitForeignKey = errors.NewKind("cannot commit table %s: foreign key %s relates it to table %s, which has uncommitted changes that are not being committed")
+var ErrPartialCommitMerge = errors.NewKind("cannot commit only some tables while a merge is in progress")
+var ErrPartialCommitStaged = errors.NewKind("cannot commit only some tables while other tables are staged: %s")
+
+// CheckoutTables resets the tables named to their staged version, or to HEAD if they aren't staged, discarding their
+// working changes.
+func (db Database) CheckoutTables(ctx *sql.Context, names []string) error {
+	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
+		return err
+	}
+
+	sess := dsess.DSessFromSess(ctx.Session)
+	roots, ok := sess.GetRoots(ctx, db.RevisionQualifiedName())
+	if !ok {
+		return fmt.Errorf("no root value found in session")
+	}
+
+	tableNames := make([]string, len(names))
+	for i, name := range names {
+		var err error
+		tableNames[i], ok, err = roots.Staged.ResolveTableName(ctx, name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			tableNames[i], ok, err = roots.Head.ResolveTableName(ctx, name)
+			if err != nil {
+				return err
+			}
+		}
+		if !ok {
+			return sql.ErrTableNotFound.New(name)
+		}
+	}
+
+	roots, err := actions.MoveTablesFromHeadToWorking(ctx, roots, tableNames)
+	if err != nil {
+		return err
+	}
+
+	ws, err := db.GetWorkingSet(ctx)
+	if err != nil {
+		return err
+	}
+
+	ait, err := db.gs.AutoIncrementTracker(ctx)
+	if err != nil {
+		return err
+	}
+
+	working := roots.Working
+	for _, tableName := range tableNames {
+		tbl, ok, err := working.GetTable(ctx, tableName)
+		if err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+
+		sch, err := tbl.GetSchema(ctx)
+		if err != nil {
+			return err
+		}
+		if !schema.HasAutoIncrement(sch) {
+			continue
+		}
+
+		seq, err := tbl.GetAutoIncrementValue(ctx)
+		if err != nil {
+			return err
+		}
+
+		if current := ait.Current(tableName); current > seq {
+			tbl, err = tbl.SetAutoIncrementValue(ctx, current)
+		} else {
+			tbl, err = ait.Set(ctx, tableName, tbl, ws.Ref(), seq)
+		}
+		if err != nil {
+			return err
+		}
+
+		working, err = working.PutTable(ctx, tableName, tbl)
+		if err != nil {
+			return err
+		}
+	}
+
+	return db.SetRoot(ctx, working)
+}
+
+// CommitOpts are the options for Database.CommitAll.
+type CommitOpts struct {
+	// Name and Email are the author of the commit. If Name is empty, the current SQL user is used, as with
+	// DOLT_COMMIT().
+	Name  string
+	Email string
+	// Date is the date of the commit. If it's zero, the time of the current query is used.
+	Date time.Time
+	// AllowEmpty permits creating a commit with no changes.
+	AllowEmpty bool
+}
+
+// CommitAll stages every table in the working set and commits them to the current branch, as with dolt commit -Am.
+// It must be called inside a transaction, which it commits.
+func (db Database) CommitAll(ctx *sql.Context, msg string, opts CommitOpts) (hash.Hash, error) {
+	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
+		return hash.Hash{}, err
+	}
+
+	sess := dsess.DSessFromSess(ctx.Session)
+	dbName := db.RevisionQualifiedName()
+	roots, ok := sess.GetRoots(ctx, dbName)
+	if !ok {
+		return hash.Hash{}, fmt.Errorf("no root value found in session")
+	}
+
+	roots, err := actions.StageAllTables(ctx, roots, true)
+	if err != nil {
+		return hash.Hash{}, err
+	}
+
+	name, email := opts.Name, opts.Email
+	if name == "" {
+		name = ctx.Client().User
+		email = fmt.Sprintf("%s@%s", ctx.Client().User, ctx.Client().Address)
+	}
+	date := opts.Date
+	if date.IsZero() {
+		date = ctx.QueryTime()
+	}
+
+	pendingCommit, err := sess.NewPendingCommit(ctx, dbName, roots, actions.CommitStagedProps{
+		Message:    msg,
+		Date:       date,
+		AllowEmpty: opts.AllowEmpty,
+		Name:       name,
+		Email:      email,
+	})
+	if err != nil {
+		return hash.Hash{}, err
+	}
+	if pendingCommit == nil {
+		return hash.Hash{}, ErrNothingToCommit.New()
+	}
+
+	newCommit, err := sess.DoltCommit(ctx, dbName, sess.GetTransaction(), pendingCommit)
+	if err != nil {
+		return hash.Hash{}, err
+	}
+
+	return newCommit.HashOf()
+}
+
+// CommitTables commits the tables named to the current branch, leaving the changes to other tables in the working
+// set. It must be called inside a transaction, which it commits.
+func (db Database) CommitTables(ctx *sql.Context, tableNames []string, msg string) (hash.Hash, error) {
+	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
+		return hash.Hash{}, err
+	}
+
+	ws, err := db.GetWorkingSet(ctx)
+	if err != nil {
+		return hash.Hash{}, err
+	}
+	if ws.MergeActive() {
+		return hash.Hash{}, ErrPartialCommitMerge.New()
+	}
+
+	sess := dsess.DSessFromSess(ctx.Session)
+	dbName := db.RevisionQualifiedName()
+	roots, ok := sess.GetRoots(ctx, dbName)
+	if !ok {
+		return hash.Hash{}, fmt.Errorf("no root value found in session")
+	}
+
+	tbls := set.NewStrSet(nil)
+	for _, name := range tableNames {
+		resolved, ok, err := roots.Working.ResolveTableName(ctx, name)
+		if err != nil {
+			return hash.Hash{}, err
+		}
+		if !ok {
+			resolved, ok, err = roots.Head.ResolveTableName(ctx, name)
+			if err != nil {
+				return hash.Hash{}, err
+			} else if !ok {
+				return hash.Hash{}, sql.ErrTableNotFound.New(name)
+			}
+		}
+		tbls.Add(resolved)
+	}
+
+	fkc, err := roots.Working.GetForeignKeyCollection(ctx)
+	if err != nil {
+		return hash.Hash{}, err
+	}
+	for _, name := range tbls.AsSortedSlice() {
+		declaredFks, referencedByFks := fkc.KeysForTable(name)
+		for _, fk := range append(declaredFks, referencedByFks...) {
+			other := fk.ReferencedTableName
+			if strings.EqualFold(other, name) {
+				other = fk.TableName
+			}
+			if tbls.Contains(other) {
+				continue
+			}
+			changed, err := tableChanged(ctx, other, roots.Head, roots.Working)
+			if err != nil {
+				return hash.Hash{}, err
+			} else if changed {
+				return hash.Hash{}, ErrPartialCommitForeignKey.New(name, fk.Name, other)
+			}
+		}
+	}
+
+	// the commit replaces the staged root, so committing would unstage any other staged tables
+	stagedDeltas, err := diff.GetTableDeltas(ctx, roots.Head, roots.Staged)
+	if err != nil {
+		return hash.Hash{}, err
+	}
+	var otherStaged []string
+	for _, td := range stagedDeltas {
+		name := td.ToName
+		if td.IsDrop() {
+			name = td.FromName
+		}
+		if !tbls.Contains(name) {
+			otherStaged = append(otherStaged, name)
+		}
+	}
+	if len(otherStaged) > 0 {
+		sort.Strings(otherStaged)
+		return hash.Hash{}, ErrPartialCommitStaged.New(strings.Join(otherStaged, ", "))
+	}
+
+	roots.Staged, err = actions.MoveTablesBetweenRoots(ctx, tbls.AsSlice(), roots.Working, roots.Head)
+	if err != nil {
+		return hash.Hash{}, err
+	}
+
+	pendingCommit, err := sess.NewPendingCommit(ctx, dbName, roots, actions.CommitStagedProps{
+		Message: msg,
+		Date:    ctx.QueryTime(),
+		Name:    ctx.Client().User,
+		Email:   fmt.Sprintf("%s@%s", ctx.Client().User, ctx.Client().Address),
+	})
+	if err != nil {
+		return hash.Hash{}, err
+	}
+	if pendingCommit == nil {
+		return hash.Hash{}, ErrNothingToCommit.New()
+	}
+
+	newCommit, err := sess.DoltCommit(ctx, dbName, sess.GetTransaction(), pendingCommit)
+	if err != nil {
+		return hash.Hash{}, err
+	}
+
+	return newCommit.HashOf()
+}
+
+// tableChanged returns whether the table named differs between |from| and |to|, including being added or dropped.
+func tableChanged(ctx context.Context, tableName string, from, to *doltdb.RootValue) (bool, error) {
+	fromHash, fromOk, err := from.GetTableHash(ctx, tableName)
+	if err != nil {
+		return false, err
+	}
+	toHash, toOk, err := to.GetTableHash(ctx, tableName)
+	if err != nil {
+		return false, err
+	}
+	return fromOk != toOk || fromHash != toHash, nil
+}
@@ -0,0 +1,181 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// CommitGraphNode is a commit in the commit graph, along with the edges to its parents, as returned by a
+// CommitGraphIter.
+type CommitGraphNode struct {
+	// Commit is the hash of the commit.
+	Commit hash.Hash
+	// Parents are the hashes of the commit's parents, first parent first. Parents beyond the maximum depth of the walk
+	// are still listed here, but aren't returned as nodes themselves.
+	Parents []hash.Hash
+	// Depth is the length of the shortest path from one of the heads of the walk to this commit. Heads have depth 0.
+	Depth int
+	// Meta is the commit's metadata, or nil if it wasn't requested.
+	Meta *datas.CommitMeta
+}
+
+// CommitGraphIter streams the commits reachable from a set of heads, in reverse topological order, so that every
+// commit is returned before its parents. It's returned by Database.CommitGraph.
+type CommitGraphIter struct {
+	itr         doltdb.CommitItr
+	maxDepth    int
+	includeMeta bool
+	// depths holds the depth of each commit that's been reached but not yet returned
+	depths map[hash.Hash]int
+}
+
+// CommitGraph returns an iterator over the commit graph reachable from the refs |heads|, each of which may be anything
+// accepted by Database.ResolveRef. Commits further than |maxDepth| parent edges from every head aren't returned, and
+// the walk stops once there are no more commits within that depth. If |maxDepth| is negative, the whole history is
+// walked. If |includeMeta| is true, each commit's author, date and message are loaded and returned with it.
+func (db Database) CommitGraph(ctx *sql.Context, heads []string, maxDepth int, includeMeta bool) (*CommitGraphIter, error) {
+	depths := make(map[hash.Hash]int, len(heads))
+	startHashes := make([]hash.Hash, 0, len(heads))
+	for _, head := range heads {
+		cm, _, err := db.ResolveRef(ctx, head)
+		if err != nil {
+			return nil, err
+		}
+		h, err := cm.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := depths[h]; !ok {
+			depths[h] = 0
+			startHashes = append(startHashes, h)
+		}
+	}
+
+	itr, err := commitwalk.GetTopologicalOrderIterator(ctx, db.ddb, startHashes, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CommitGraphIter{
+		itr:         itr,
+		maxDepth:    maxDepth,
+		includeMeta: includeMeta,
+		depths:      depths,
+	}, nil
+}
+
+// WalkHistory calls |visit| with each commit reachable from |startRef|, which may be anything accepted by ResolveRef,
+// starting with the commit it resolves to. Commits are visited in reverse topological order, so every commit is
+// visited before its parents. Ties between commits on concurrent branches go to the commit with the greater height,
+// and then to the newer commit, as in dolt_log. Each commit is visited once. The walk stops without an error when
+// |visit| returns true, and stops with |visit|'s error if it returns one, or with the context's error if it's
+// canceled.
+func (db Database) WalkHistory(ctx *sql.Context, startRef string, visit func(*doltdb.Commit) (stop bool, err error)) error {
+	cm, _, err := db.ResolveRef(ctx, startRef)
+	if err != nil {
+		return err
+	}
+	h, err := cm.HashOf()
+	if err != nil {
+		return err
+	}
+
+	itr, err := commitwalk.GetTopologicalOrderIterator(ctx, db.ddb, []hash.Hash{h}, nil)
+	if err != nil {
+		return err
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		_, curr, err := itr.Next(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		stop, err := visit(curr)
+		if err != nil {
+			return err
+		}
+		if stop {
+			return nil
+		}
+	}
+}
+
+// Next returns the next commit in the graph. Returns io.EOF when there are no more commits, and the context's error if
+// it's canceled.
+func (itr *CommitGraphIter) Next(ctx *sql.Context) (CommitGraphNode, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return CommitGraphNode{}, err
+		}
+
+		// every commit within the maximum depth has been returned, so there's no need to walk the rest of the history
+		if len(itr.depths) == 0 {
+			return CommitGraphNode{}, io.EOF
+		}
+
+		h, cm, err := itr.itr.Next(ctx)
+		if err != nil {
+			return CommitGraphNode{}, err
+		}
+
+		// commits are returned after all of their children, so the depth of this commit is final
+		depth, ok := itr.depths[h]
+		if !ok {
+			continue
+		}
+		delete(itr.depths, h)
+
+		parents, err := cm.ParentHashes(ctx)
+		if err != nil {
+			return CommitGraphNode{}, err
+		}
+
+		if itr.maxDepth < 0 || depth < itr.maxDepth {
+			for _, p := range parents {
+				if d, ok := itr.depths[p]; !ok || depth+1 < d {
+					itr.depths[p] = depth + 1
+				}
+			}
+		}
+
+		node := CommitGraphNode{
+			Commit:  h,
+			Parents: parents,
+			Depth:   depth,
+		}
+		if itr.includeMeta {
+			if node.Meta, err = cm.GetCommitMeta(ctx); err != nil {
+				return CommitGraphNode{}, err
+			}
+		}
+
+		return node, nil
+	}
+}
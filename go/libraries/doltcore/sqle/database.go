@@ -17,6 +17,7 @@ package sqle
 import (
 	"context"
 	"encoding/json"
+	goerrors "errors"
 	"fmt"
 	"io"
 	"strings"
@@ -39,12 +40,20 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/globalstate"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/hash"
 )
 
 var ErrInvalidTableName = errors.NewKind("Invalid table name %s.")
 var ErrReservedTableName = errors.NewKind("Invalid table name %s. Table names beginning with `dolt_` are reserved for internal use")
 var ErrSystemTableAlter = errors.NewKind("Cannot alter table %s: system tables cannot be dropped or altered")
+var ErrSystemTableAsOf = errors.NewKind("AS OF is not supported for system table %s, which only reflects the current working set")
+var ErrAmbiguousStoredProcedure = errors.NewKind("stored procedure %s is ambiguous: %d procedures match, specify a definer")
+var ErrReflogNotSupported = errors.NewKind("cannot resolve %s: this database doesn't keep a reflog, so refs can only be resolved to the commits they point to now")
+var ErrNotWorkingSetHash = errors.NewKind("%s is not the hash of a working set")
+var ErrInvalidCommitRef = errors.NewKind("%s is not a valid commit ref")
+var ErrCommitRefNotFound = errors.NewKind("cannot resolve %s: there is no branch, tag or commit with that name")
+var ErrCommitAncestorNotFound = errors.NewKind("cannot resolve %s: the commit it starts from doesn't have that many ancestors")
 
 // Database implements sql.Database for a dolt DB.
 type Database struct {
@@ -57,8 +66,12 @@ type Database struct {
 	editOpts      editor.Options
 	revision      string
 	revType       dsess.RevisionType
+	rootValidator RootValidator
 }
 
+// RootValidator checks a new working root before SetRoot sets it, returning an error to reject it.
+type RootValidator func(ctx *sql.Context, oldRoot, newRoot *doltdb.RootValue) error
+
 var _ dsess.SqlDatabase = Database{}
 var _ dsess.RevisionDatabase = Database{}
 var _ globalstate.GlobalStateProvider = Database{}
@@ -125,6 +138,13 @@ func (db Database) RevisionType() dsess.RevisionType {
 	return db.revType
 }
 
+// WithRootValidator returns a copy of this database whose SetRoot method checks every new root with |validator|
+// before setting it. Revision databases created from the copy use the same validator.
+func (db Database) WithRootValidator(validator RootValidator) Database {
+	db.rootValidator = validator
+	return db
+}
+
 func (db Database) EditOptions() editor.Options {
 	return db.editOpts
 }
@@ -189,6 +209,11 @@ func (db Database) RequestedName() string {
 	return db.requestedName
 }
 
+// Rename renames the database this is a revision of to |newName|. This Database can't be used afterward.
+func (db Database) Rename(ctx *sql.Context, newName string) error {
+	return dsess.DSessFromSess(ctx.Session).Provider().RenameDatabase(ctx, db.baseName, newName)
+}
+
 // GetDoltDB gets the underlying DoltDB of the Database
 func (db Database) GetDoltDB() *doltdb.DoltDB {
 	return db.ddb
@@ -243,11 +268,16 @@ func (db Database) GetTableInsensitive(ctx *sql.Context, tblName string) (sql.Ta
 	return tbl, true, nil
 }
 
-// GetTableInsensitiveAsOf implements sql.VersionedDatabase
+// GetTableInsensitiveAsOf implements sql.VersionedDatabase. System tables that only describe the session's working
+// set return ErrSystemTableAsOf, and those that describe the database as a whole ignore |asOf|.
 func (db Database) GetTableInsensitiveAsOf(ctx *sql.Context, tableName string, asOf interface{}) (sql.Table, bool, error) {
 	if asOf == nil {
 		return db.GetTableInsensitive(ctx, tableName)
 	}
+	if isWorkingSetSystemTable(tableName) {
+		return nil, false, ErrSystemTableAsOf.New(tableName)
+	}
+
 	head, root, err := resolveAsOf(ctx, db, asOf)
 	if err != nil {
 		return nil, false, err
@@ -255,6 +285,10 @@ func (db Database) GetTableInsensitiveAsOf(ctx *sql.Context, tableName string, a
 		return nil, false, nil
 	}
 
+	if strings.ToLower(tableName) == doltdb.IgnoreTableName {
+		return db.getIgnoreTableAsOf(ctx, root)
+	}
+
 	sess := dsess.DSessFromSess(ctx.Session)
 
 	table, ok, err := db.getTableInsensitive(ctx, head, sess, root, tableName)
@@ -295,6 +329,35 @@ func (db Database) GetTableInsensitiveAsOf(ctx *sql.Context, tableName string, a
 	}
 }
 
+// isWorkingSetSystemTable returns whether the system table named only describes the session's working set, and so
+// cannot be resolved AS OF a revision.
+func isWorkingSetSystemTable(tableName string) bool {
+	switch strings.ToLower(tableName) {
+	case doltdb.StatusTableName, doltdb.MergeStatusTableName, doltdb.TableOfTablesInConflictName, doltdb.SchemaConflictsTableName,
+		doltdb.ActiveRevisionsTableName, doltdb.ConflictsSummaryTableName, doltdb.MergeStatsTableName:
+		return true
+	default:
+		return false
+	}
+}
+
+// getIgnoreTableAsOf returns the dolt_ignore table with its backing table locked to the root value given.
+func (db Database) getIgnoreTableAsOf(ctx *sql.Context, root *doltdb.RootValue) (sql.Table, bool, error) {
+	backingTable, ok, err := db.getTable(ctx, root, doltdb.IgnoreTableName)
+	if err != nil {
+		return nil, false, err
+	}
+	if !ok {
+		return dtables.NewIgnoreTable(ctx, db.ddb, nil), true, nil
+	}
+
+	locked, err := backingTable.(*WritableDoltTable).LockedToRoot(ctx, root)
+	if err != nil {
+		return nil, false, err
+	}
+	return dtables.NewIgnoreTable(ctx, db.ddb, locked), true, nil
+}
+
 func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds *dsess.DoltSession, root *doltdb.RootValue, tblName string) (sql.Table, bool, error) {
 	lwrName := strings.ToLower(tblName)
 
@@ -320,7 +383,7 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 
 	case strings.HasPrefix(lwrName, doltdb.DoltCommitDiffTablePrefix):
 		suffix := tblName[len(doltdb.DoltCommitDiffTablePrefix):]
-		dt, err := dtables.NewCommitDiffTable(ctx, suffix, db.ddb, root)
+		dt, err := dtables.NewCommitDiffTable(ctx, suffix, db.ddb, root, db.rootAtRef)
 		if err != nil {
 			return nil, false, err
 		}
@@ -346,6 +409,10 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 
 		return NewHistoryTable(baseTable.(*AlterableDoltTable).DoltTable, db.ddb, head), true, nil
 
+	case lwrName == doltdb.ConflictsSummaryTableName:
+		// This name also has the conflicts table prefix, so it must be matched first
+		return dtables.NewConflictsSummaryTable(ctx, db.RevisionQualifiedName()), true, nil
+
 	case strings.HasPrefix(lwrName, doltdb.DoltConfTablePrefix):
 		suffix := tblName[len(doltdb.DoltConfTablePrefix):]
 		srcTable, ok, err := db.getTableInsensitive(ctx, head, ds, root, suffix)
@@ -434,6 +501,12 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 		dt, found = dtables.NewMergeStatusTable(db.RevisionQualifiedName()), true
 	case doltdb.TagsTableName:
 		dt, found = dtables.NewTagsTable(ctx, db.ddb), true
+	case doltdb.StorageStatsTableName:
+		dt, found = dtables.NewStorageStatsTable(ctx, db.ddb), true
+	case doltdb.ActiveRevisionsTableName:
+		dt, found = dtables.NewActiveRevisionsTable(), true
+	case doltdb.MergeStatsTableName:
+		dt, found = dtables.NewMergeStatsTable(db.Name()), true
 	case dtables.AccessTableName:
 		basCtx := branch_control.GetBranchAwareSession(ctx)
 		if basCtx != nil {
@@ -466,27 +539,18 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 
 // resolveAsOf resolves given expression to a commit, if one exists.
 func resolveAsOf(ctx *sql.Context, db Database, asOf interface{}) (*doltdb.Commit, *doltdb.RootValue, error) {
-	head, err := db.rsr.CWBHeadRef()
-	if err != nil {
-		return nil, nil, err
-	}
 	switch x := asOf.(type) {
 	case time.Time:
-		return resolveAsOfTime(ctx, db.ddb, head, x)
+		return resolveAsOfTime(ctx, db, x)
 	case string:
-		return resolveAsOfCommitRef(ctx, db, head, x)
+		return db.ResolveRef(ctx, x)
 	default:
 		return nil, nil, fmt.Errorf("unsupported AS OF type %T", asOf)
 	}
 }
 
-func resolveAsOfTime(ctx *sql.Context, ddb *doltdb.DoltDB, head ref.DoltRef, asOf time.Time) (*doltdb.Commit, *doltdb.RootValue, error) {
-	cs, err := doltdb.NewCommitSpec("HEAD")
-	if err != nil {
-		return nil, nil, err
-	}
-
-	cm, err := ddb.Resolve(ctx, cs, head)
+func resolveAsOfTime(ctx *sql.Context, db Database, asOf time.Time) (*doltdb.Commit, *doltdb.RootValue, error) {
+	cm, _, err := db.ResolveRef(ctx, "HEAD")
 	if err != nil {
 		return nil, nil, err
 	}
@@ -496,7 +560,7 @@ func resolveAsOfTime(ctx *sql.Context, ddb *doltdb.DoltDB, head ref.DoltRef, asO
 		return nil, nil, err
 	}
 
-	cmItr, err := commitwalk.GetTopologicalOrderIterator(ctx, ddb, []hash.Hash{h}, nil)
+	cmItr, err := commitwalk.GetTopologicalOrderIterator(ctx, db.ddb, []hash.Hash{h}, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -525,25 +589,67 @@ func resolveAsOfTime(ctx *sql.Context, ddb *doltdb.DoltDB, head ref.DoltRef, asO
 	return nil, nil, nil
 }
 
-func resolveAsOfCommitRef(ctx *sql.Context, db Database, head ref.DoltRef, commitRef string) (*doltdb.Commit, *doltdb.RootValue, error) {
-	ddb := db.ddb
+// ResolveRef resolves the ref string given, such as a branch, tag, commit hash, HEAD~2, WORKING or STAGED, to a commit
+// and its root value. Reflog refs such as HEAD@{2} return ErrReflogNotSupported.
+func (db Database) ResolveRef(ctx *sql.Context, refStr string) (*doltdb.Commit, *doltdb.RootValue, error) {
+	cm, root, _, err := db.rootAtRef(ctx, refStr)
+	return cm, root, err
+}
 
-	if commitRef == doltdb.Working || commitRef == doltdb.Staged {
-		sess := dsess.DSessFromSess(ctx.Session)
-		root, _, _, err := sess.ResolveRootForRef(ctx, ctx.GetCurrentDatabase(), commitRef)
+// resolveCommitRef resolves |refStr| as ResolveRef does, except for the working sets of other branches.
+func (db Database) resolveCommitRef(ctx *sql.Context, refStr string) (*doltdb.Commit, *doltdb.RootValue, error) {
+	sess := dsess.DSessFromSess(ctx.Session)
+
+	// "@{" can't appear in a ref name, so this can only be meant as a reflog ref
+	if strings.Contains(refStr, "@{") {
+		return nil, nil, ErrReflogNotSupported.New(refStr)
+	}
+
+	if strings.EqualFold(refStr, doltdb.Working) || strings.EqualFold(refStr, doltdb.Staged) {
+		roots, ok := sess.GetRoots(ctx, db.RevisionQualifiedName())
+		if !ok {
+			return nil, nil, fmt.Errorf("no root value found in session")
+		}
+
+		cm, err := sess.GetHeadCommit(ctx, db.RevisionQualifiedName())
 		if err != nil {
 			return nil, nil, err
 		}
 
-		cm, err := ddb.ResolveCommitRef(ctx, head)
+		if strings.EqualFold(refStr, doltdb.Working) {
+			return cm, roots.Working, nil
+		}
+		return cm, roots.Staged, nil
+	}
+
+	// A database pinned to a commit has no branch for HEAD to name, so HEAD and its ancestors, such as HEAD~5 or
+	// HEAD^^, are resolved from the commit it's pinned to. Otherwise HEAD is the head of the current branch.
+	baseSpec, ancestorSpec, err := doltdb.SplitAncestorSpec(refStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if strings.EqualFold(baseSpec, "HEAD") && db.revType == dsess.RevisionTypeCommit {
+		cm, err := sess.GetHeadCommit(ctx, db.RevisionQualifiedName())
+		if err != nil {
+			return nil, nil, err
+		}
+		cm, err = cm.GetAncestor(ctx, ancestorSpec)
+		if err != nil {
+			return nil, nil, err
+		}
+		root, err := cm.GetRootValue(ctx)
 		if err != nil {
 			return nil, nil, err
 		}
 		return cm, root, nil
 	}
 
-	cs, err := doltdb.NewCommitSpec(commitRef)
+	cs, err := doltdb.NewCommitSpec(refStr)
+	if err != nil {
+		return nil, nil, err
+	}
 
+	headRef, err := db.rsr.CWBHeadRef()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -553,7 +659,7 @@ func resolveAsOfCommitRef(ctx *sql.Context, db Database, head ref.DoltRef, commi
 		return nil, nil, err
 	}
 
-	cm, err := ddb.ResolveByNomsRoot(ctx, cs, head, nomsRoot)
+	cm, err := db.ddb.ResolveByNomsRoot(ctx, cs, headRef, nomsRoot)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -566,6 +672,29 @@ func resolveAsOfCommitRef(ctx *sql.Context, db Database, head ref.DoltRef, commi
 	return cm, root, nil
 }
 
+// RootForCommit returns the root value that |commitRef| resolves to with ResolveRef.
+func (db Database) RootForCommit(ctx *sql.Context, commitRef string) (*doltdb.RootValue, error) {
+	// the ancestor spec is checked up front, since an invalid one returns the same error as a missing ancestor
+	if _, _, err := doltdb.SplitAncestorSpec(commitRef); err != nil || strings.TrimSpace(commitRef) == "" {
+		return nil, ErrInvalidCommitRef.New(commitRef)
+	}
+
+	_, root, err := db.ResolveRef(ctx, commitRef)
+	switch {
+	case err == nil:
+		return root, nil
+	case goerrors.Is(err, doltdb.ErrInvalidBranchOrHash):
+		return nil, ErrInvalidCommitRef.New(commitRef)
+	case goerrors.Is(err, doltdb.ErrBranchNotFound), goerrors.Is(err, doltdb.ErrTagNotFound),
+		goerrors.Is(err, doltdb.ErrHashNotFound), goerrors.Is(err, datas.ErrCommitNotFound):
+		return nil, ErrCommitRefNotFound.New(commitRef)
+	case goerrors.Is(err, doltdb.ErrInvalidAncestorSpec):
+		return nil, ErrCommitAncestorNotFound.New(commitRef)
+	default:
+		return nil, err
+	}
+}
+
 // GetTableNamesAsOf implements sql.VersionedDatabase
 func (db Database) GetTableNamesAsOf(ctx *sql.Context, time interface{}) ([]string, error) {
 	_, root, err := resolveAsOf(ctx, db, time)
@@ -598,9 +727,12 @@ func (db Database) getTable(ctx *sql.Context, root *doltdb.RootValue, tableName
 		return nil, false, err
 	}
 
-	cachedTable, ok := dbState.SessionCache().GetCachedTable(key, tableName)
-	if ok {
-		return cachedTable, true, nil
+	cacheSize, cacheEnabled := sess.TableCacheSize()
+	if cacheEnabled {
+		cachedTable, ok := dbState.SessionCache().GetCachedTable(key, tableName)
+		if ok {
+			return cachedTable, true, nil
+		}
 	}
 
 	tableNames, err := getAllTableNames(ctx, root)
@@ -626,11 +758,22 @@ func (db Database) getTable(ctx *sql.Context, root *doltdb.RootValue, tableName
 		return nil, false, err
 	}
 
+	table, err := db.newCachedTable(dbState, key, cacheSize, cacheEnabled, tableName, tbl, sch)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return table, true, nil
+}
+
+// newCachedTable constructs the sql.Table for the table given, which is named |tableName| in the root with cache key
+// |key|, and caches it in the session unless |cacheEnabled| is false.
+func (db Database) newCachedTable(dbState dsess.SessionState, key doltdb.DataCacheKey, cacheSize int, cacheEnabled bool, tableName string, tbl *doltdb.Table, sch schema.Schema) (sql.Table, error) {
 	var table sql.Table
 
 	readonlyTable, err := NewDoltTable(tableName, sch, tbl, db, db.editOpts)
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
 	if doltdb.IsReadOnlySystemTable(tableName) {
 		table = readonlyTable
@@ -640,9 +783,55 @@ func (db Database) getTable(ctx *sql.Context, root *doltdb.RootValue, tableName
 		table = &AlterableDoltTable{WritableDoltTable{DoltTable: readonlyTable, db: db}}
 	}
 
-	dbState.SessionCache().CacheTable(key, tableName, table)
+	if cacheEnabled {
+		dbState.SessionCache().CacheTable(key, tableName, table, cacheSize)
+	}
 
-	return table, true, nil
+	return table, nil
+}
+
+// ForEachTable calls |cb| with each table in the working set, in the order of GetAllTableNames, reading one table at
+// a time. Iteration stops at the first error |cb| returns.
+func (db Database) ForEachTable(ctx *sql.Context, cb func(name string, tbl sql.Table) error) error {
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	sess := dsess.DSessFromSess(ctx.Session)
+	dbState, ok, err := sess.LookupDbState(ctx, db.RevisionQualifiedName())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("no state for database %s", db.RevisionQualifiedName())
+	}
+
+	key, err := doltdb.NewDataCacheKey(root)
+	if err != nil {
+		return err
+	}
+
+	cacheSize, cacheEnabled := sess.TableCacheSize()
+	return root.IterTables(ctx, func(name string, tbl *doltdb.Table, sch schema.Schema) (bool, error) {
+		var table sql.Table
+		var cached bool
+		if cacheEnabled {
+			table, cached = dbState.SessionCache().GetCachedTable(key, name)
+		}
+		if !cached {
+			var err error
+			table, err = db.newCachedTable(dbState, key, cacheSize, cacheEnabled, name, tbl, sch)
+			if err != nil {
+				return true, err
+			}
+		}
+
+		if err := cb(name, table); err != nil {
+			return true, err
+		}
+		return false, nil
+	})
 }
 
 // GetTableNames returns the names of all user tables. System tables in user space (e.g. dolt_docs, dolt_query_catalog)
@@ -683,6 +872,25 @@ func filterDoltInternalTables(tblNames []string) []string {
 	return result
 }
 
+// IsIgnored returns whether the table name given matches the patterns in this database's dolt_ignore table.
+func (db Database) IsIgnored(ctx *sql.Context, tableName string) (bool, error) {
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	ignorePatterns, err := doltdb.GetIgnoredTablePatterns(ctx, doltdb.Roots{Working: root})
+	if err != nil {
+		return false, err
+	}
+
+	ignored, err := ignorePatterns.IsTableNameIgnored(tableName)
+	if err != nil {
+		return false, err
+	}
+	return ignored == doltdb.Ignore, nil
+}
+
 // GetRoot returns the root value for this database session
 func (db Database) GetRoot(ctx *sql.Context) (*doltdb.RootValue, error) {
 	sess := dsess.DSessFromSess(ctx.Session)
@@ -697,9 +905,37 @@ func (db Database) GetRoot(ctx *sql.Context) (*doltdb.RootValue, error) {
 	return dbState.WorkingRoot(), nil
 }
 
+// GetRootHash returns the hash of the root value returned by GetRoot. Callers can compare it with a previously returned
+// hash to detect whether the session's working root has changed.
+func (db Database) GetRootHash(ctx *sql.Context) (hash.Hash, error) {
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return hash.Hash{}, err
+	}
+	return root.HashOf()
+}
+
+// CreateSavepoint creates a savepoint with the name given in the current transaction, as with SAVEPOINT.
+func (db Database) CreateSavepoint(ctx *sql.Context, name string) error {
+	return dsess.DSessFromSess(ctx.Session).CreateSavepoint(ctx, ctx.GetTransaction(), name)
+}
+
+// RollbackToSavepoint restores the working roots and temporary tables recorded by the savepoint with the name given,
+// as with the ROLLBACK TO SAVEPOINT statement. Savepoints created after it are released.
+func (db Database) RollbackToSavepoint(ctx *sql.Context, name string) error {
+	return dsess.DSessFromSess(ctx.Session).RollbackToSavepoint(ctx, ctx.GetTransaction(), name)
+}
+
+// ReleaseSavepoint removes the savepoint with the name given from the current transaction, as with the RELEASE
+// SAVEPOINT statement.
+func (db Database) ReleaseSavepoint(ctx *sql.Context, name string) error {
+	return dsess.DSessFromSess(ctx.Session).ReleaseSavepoint(ctx, ctx.GetTransaction(), name)
+}
+
 // GetWorkingSet gets the current working set for the database.
 // If there is no working set (most likely because the DB is in Detached Head mode, return an error.
-// If a command needs to work while in Detached Head, that command should call sess.LookupDbState directly.
+// If a command needs to work while in Detached Head, that command should check IsDetachedHead first, or call
+// sess.LookupDbState directly.
 // TODO: This is a temporary measure to make sure that new commands that call GetWorkingSet don't unexpectedly receive
 // a null pointer. In the future, we should replace all uses of dbState.WorkingSet, including this, with a new interface
 // where users avoid handling the WorkingSet directly.
@@ -719,8 +955,18 @@ func (db Database) GetWorkingSet(ctx *sql.Context) (*doltdb.WorkingSet, error) {
 }
 
 // SetRoot should typically be called on the Session, which is where this state lives. But it's available here as a
-// convenience.
+// convenience. If the database has a RootValidator, the new root is only set if the validator accepts it.
 func (db Database) SetRoot(ctx *sql.Context, newRoot *doltdb.RootValue) error {
+	if db.rootValidator != nil {
+		oldRoot, err := db.GetRoot(ctx)
+		if err != nil {
+			return err
+		}
+		if err = db.rootValidator(ctx, oldRoot, newRoot); err != nil {
+			return err
+		}
+	}
+
 	sess := dsess.DSessFromSess(ctx.Session)
 	return sess.SetRoot(ctx, db.RevisionQualifiedName(), newRoot)
 }
@@ -735,6 +981,16 @@ func (db Database) GetHeadRoot(ctx *sql.Context) (*doltdb.RootValue, error) {
 	return head.GetRootValue(ctx)
 }
 
+// RootForWorkingSetHash returns the working root of the working set with the hash |h|, or ErrNotWorkingSetHash if
+// there's no such working set.
+func (db Database) RootForWorkingSetHash(ctx *sql.Context, h hash.Hash) (*doltdb.RootValue, error) {
+	root, err := db.ddb.ReadWorkingSetRoot(ctx, h)
+	if err == doltdb.ErrHashNotFound || err == doltdb.ErrFoundHashNotAWorkingSet {
+		return nil, ErrNotWorkingSetHash.New(h.String())
+	}
+	return root, err
+}
+
 // DropTable drops the table with the name given.
 // The planner returns the correct case sensitive name in tableName
 func (db Database) DropTable(ctx *sql.Context, tableName string) error {
@@ -744,6 +1000,9 @@ func (db Database) DropTable(ctx *sql.Context, tableName string) error {
 	if doltdb.IsNonAlterableSystemTable(tableName) {
 		return ErrSystemTableAlter.New(tableName)
 	}
+	if err := db.checkDropTableDependents(ctx, tableName); err != nil {
+		return err
+	}
 
 	return db.dropTable(ctx, tableName)
 }
@@ -802,16 +1061,37 @@ func (db Database) removeTableFromAutoIncrementTracker(
 	ddb *doltdb.DoltDB,
 	ws ref.WorkingSetRef,
 ) error {
-	branches, err := ddb.GetBranches(ctx)
+	wses, err := otherBranchWorkingSets(ctx, ddb, ws)
+	if err != nil {
+		return err
+	}
+
+	ait, err := db.gs.AutoIncrementTracker(ctx)
 	if err != nil {
 		return err
 	}
 
+	err = ait.DropTable(ctx, tableName, wses...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// otherBranchWorkingSets returns the working sets of the branches of |ddb| other than the one of |ws|. Branches without
+// a working set are skipped.
+func otherBranchWorkingSets(ctx *sql.Context, ddb *doltdb.DoltDB, ws ref.WorkingSetRef) ([]*doltdb.WorkingSet, error) {
+	branches, err := ddb.GetBranches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var wses []*doltdb.WorkingSet
 	for _, b := range branches {
 		wsRef, err := ref.WorkingSetRefForHead(b)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		if wsRef == ws {
@@ -824,23 +1104,28 @@ func (db Database) removeTableFromAutoIncrementTracker(
 			// skip, continue working on other branches
 			continue
 		} else if err != nil {
-			return err
+			return nil, err
 		}
 
 		wses = append(wses, ws)
 	}
+	return wses, nil
+}
 
-	ait, err := db.gs.AutoIncrementTracker(ctx)
+// rootAtRef returns the root value at |refStr|, which may also name a branch's working set, in which case
+// |isBranchWorking| is true and the commit is that branch's head.
+func (db Database) rootAtRef(ctx *sql.Context, refStr string) (cm *doltdb.Commit, root *doltdb.RootValue, isBranchWorking bool, err error) {
+	cm, root, isBranchWorking, err = dsess.ResolveBranchWorkingRoot(ctx, db.ddb, db.Name(), refStr)
 	if err != nil {
-		return err
+		return nil, nil, false, err
 	}
-
-	err = ait.DropTable(ctx, tableName, wses...)
-	if err != nil {
-		return err
+	if !isBranchWorking {
+		cm, root, err = db.resolveCommitRef(ctx, refStr)
+		if err != nil {
+			return nil, nil, false, err
+		}
 	}
-
-	return nil
+	return cm, root, isBranchWorking, nil
 }
 
 // CreateTable creates a table with the name and schema given.
@@ -861,7 +1146,63 @@ func (db Database) CreateTable(ctx *sql.Context, tableName string, sch sql.Prima
 		return ErrInvalidTableName.New(tableName)
 	}
 
-	return db.createSqlTable(ctx, tableName, sch, collation)
+	return db.WithSchemaLock(ctx, func() error {
+		return db.createSqlTable(ctx, tableName, sch, collation)
+	})
+}
+
+// WithSchemaLock calls |fn| while holding the database's schema lock, which is shared by all its sessions and
+// revisions. The lock is reentrant, and is released before the session's transaction commits.
+func (db Database) WithSchemaLock(ctx *sql.Context, fn func() error) error {
+	lock := db.gs.SchemaLock()
+	if err := lock.Lock(ctx); err != nil {
+		return err
+	}
+	defer lock.Unlock()
+	return fn()
+}
+
+// CreateTableIfNotExists creates a table like CreateTable unless one with that name already exists, and returns
+// whether it was created.
+func (db Database) CreateTableIfNotExists(ctx *sql.Context, tableName string, sch sql.PrimaryKeySchema, collation sql.CollationID) (bool, error) {
+	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
+		return false, err
+	}
+
+	var created bool
+	err := db.WithSchemaLock(ctx, func() error {
+		root, err := db.GetRoot(ctx)
+		if err != nil {
+			return err
+		}
+		if _, exists, err := root.ResolveTableName(ctx, tableName); err != nil || exists {
+			return err
+		}
+
+		if err = db.CreateTable(ctx, tableName, sch, collation); err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+// CreateTableWithTags creates a table like CreateTable, using the column tags given instead of generating them.
+func (db Database) CreateTableWithTags(ctx *sql.Context, tableName string, sch sql.PrimaryKeySchema, collation sql.CollationID, tags map[string]uint64) error {
+	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
+		return err
+	}
+	if doltdb.HasDoltPrefix(tableName) {
+		return ErrReservedTableName.New(tableName)
+	}
+	if !doltdb.IsValidTableName(tableName) {
+		return ErrInvalidTableName.New(tableName)
+	}
+
+	return db.WithSchemaLock(ctx, func() error {
+		return db.createSqlTableWithTags(ctx, tableName, sch, collation, tags)
+	})
 }
 
 // CreateIndexedTable creates a table with the name and schema given.
@@ -882,7 +1223,107 @@ func (db Database) CreateIndexedTable(ctx *sql.Context, tableName string, sch sq
 		return ErrInvalidTableName.New(tableName)
 	}
 
-	return db.createIndexedSqlTable(ctx, tableName, sch, idxDef, collation)
+	return db.WithSchemaLock(ctx, func() error {
+		return db.createIndexedSqlTable(ctx, tableName, sch, idxDef, collation)
+	})
+}
+
+// CreateTableComplete creates a table along with its secondary |indexes| and the foreign keys |fks| it declares in a
+// single write of the working root.
+func (db Database) CreateTableComplete(ctx *sql.Context, tableName string, sch sql.PrimaryKeySchema, collation sql.CollationID, indexes []sql.IndexDef, fks []sql.ForeignKeyConstraint) error {
+	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
+		return err
+	}
+	if doltdb.HasDoltPrefix(tableName) {
+		return ErrReservedTableName.New(tableName)
+	}
+	if !doltdb.IsValidTableName(tableName) {
+		return ErrInvalidTableName.New(tableName)
+	}
+
+	return db.WithSchemaLock(ctx, func() error {
+		return db.createTableComplete(ctx, tableName, sch, collation, indexes, fks)
+	})
+}
+
+// createTableComplete is the private version of CreateTableComplete. It doesn't enforce any table name checks.
+func (db Database) createTableComplete(ctx *sql.Context, tableName string, sch sql.PrimaryKeySchema, collation sql.CollationID, indexes []sql.IndexDef, fks []sql.ForeignKeyConstraint) error {
+	sch, collation, err := db.withDefaultCollation(ctx, sch, collation)
+	if err != nil {
+		return err
+	}
+
+	ws, err := db.GetWorkingSet(ctx)
+	if err != nil {
+		return err
+	}
+	root := ws.WorkingRoot()
+
+	headRoot, err := db.GetHeadRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	doltSch, err := sqlutil.ToDoltSchema(ctx, root, tableName, sch, headRoot, collation)
+	if err != nil {
+		return err
+	}
+
+	if err = checkNewTableSchema(tableName, doltSch); err != nil {
+		return err
+	}
+
+	for _, idx := range indexes {
+		if err = addIndexToSchema(doltSch, sch, idx); err != nil {
+			return err
+		}
+	}
+
+	newRoot, err := createEmptyDoltTable(ctx, tableName, root, doltSch)
+	if err != nil {
+		return err
+	}
+
+	if len(fks) > 0 {
+		newRoot, err = db.addForeignKeysToNewTable(ctx, newRoot, tableName, doltSch, fks)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = db.trackNewTable(ctx, tableName, doltSch); err != nil {
+		return err
+	}
+
+	return db.SetRoot(ctx, newRoot)
+}
+
+// addIndexToSchema adds the secondary index |idx| to |doltSch|, the dolt schema of a new table with the SQL schema
+// |sch|.
+func addIndexToSchema(doltSch schema.Schema, sch sql.PrimaryKeySchema, idx sql.IndexDef) error {
+	if idx.Constraint != sql.IndexConstraint_None && idx.Constraint != sql.IndexConstraint_Unique && idx.Constraint != sql.IndexConstraint_Spatial {
+		return fmt.Errorf("only the following types of index constraints are supported: none, unique, spatial")
+	}
+
+	columns := make([]string, len(idx.Columns))
+	for i, idxCol := range idx.Columns {
+		columns[i] = idxCol.Name
+		// Prevent any tables that use BINARY, CHAR, VARBINARY, VARCHAR prefixes in Primary Key
+		if colIdx := sch.Schema.IndexOfColName(idxCol.Name); colIdx >= 0 {
+			col := sch.Schema[colIdx]
+			if col.PrimaryKey && types.IsText(col.Type) && idxCol.Length > 0 {
+				return sql.ErrUnsupportedIndexPrefix.New(col.Name)
+			}
+		}
+	}
+
+	_, err := doltSch.Indexes().AddIndexByColNames(idx.Name, columns, allocatePrefixLengths(idx.Columns), schema.IndexProperties{
+		IsUnique:      idx.Constraint == sql.IndexConstraint_Unique,
+		IsSpatial:     idx.Constraint == sql.IndexConstraint_Spatial,
+		IsUserDefined: true,
+		Comment:       idx.Comment,
+	})
+	return err
 }
 
 // CreateFulltextTableNames returns a set of names that will be used to create Full-Text pseudo-index tables.
@@ -913,6 +1354,40 @@ OuterLoop:
 
 // createSqlTable is the private version of CreateTable. It doesn't enforce any table name checks.
 func (db Database) createSqlTable(ctx *sql.Context, tableName string, sch sql.PrimaryKeySchema, collation sql.CollationID) error {
+	return db.createSqlTableWithTags(ctx, tableName, sch, collation, nil)
+}
+
+// withDefaultCollation returns |sch| and |collation| with unspecified collations replaced by the database's.
+func (db Database) withDefaultCollation(ctx *sql.Context, sch sql.PrimaryKeySchema, collation sql.CollationID) (sql.PrimaryKeySchema, sql.CollationID, error) {
+	if collation != sql.Collation_Unspecified {
+		return sch, collation, nil
+	}
+
+	collation = db.GetCollation(ctx)
+	cols := make(sql.Schema, len(sch.Schema))
+	for i, col := range sch.Schema {
+		cols[i] = col
+		if collatedType, ok := col.Type.(sql.TypeWithCollation); ok && collatedType.Collation() == sql.Collation_Unspecified {
+			newType, err := collatedType.WithNewCollation(collation)
+			if err != nil {
+				return sql.PrimaryKeySchema{}, sql.Collation_Unspecified, err
+			}
+			newCol := *col
+			newCol.Type = newType
+			cols[i] = &newCol
+		}
+	}
+
+	return sql.PrimaryKeySchema{Schema: cols, PkOrdinals: sch.PkOrdinals}, collation, nil
+}
+
+// createSqlTableWithTags is like createSqlTable, but uses the tags given for the columns they name.
+func (db Database) createSqlTableWithTags(ctx *sql.Context, tableName string, sch sql.PrimaryKeySchema, collation sql.CollationID, tags map[string]uint64) error {
+	sch, collation, err := db.withDefaultCollation(ctx, sch, collation)
+	if err != nil {
+		return err
+	}
+
 	ws, err := db.GetWorkingSet(ctx)
 	if err != nil {
 		return err
@@ -930,31 +1405,52 @@ func (db Database) createSqlTable(ctx *sql.Context, tableName string, sch sql.Pr
 		return err
 	}
 
-	doltSch, err := sqlutil.ToDoltSchema(ctx, root, tableName, sch, headRoot, collation)
+	doltSch, err := sqlutil.ToDoltSchemaWithTags(ctx, root, tableName, sch, headRoot, collation, tags)
 	if err != nil {
 		return err
 	}
 
+	if err = checkNewTableSchema(tableName, doltSch); err != nil {
+		return err
+	}
+
+	if err = db.trackNewTable(ctx, tableName, doltSch); err != nil {
+		return err
+	}
+
+	return db.createDoltTable(ctx, tableName, root, doltSch)
+}
+
+// checkNewTableSchema returns an error if a table named |tableName| can't be created with the schema |doltSch|.
+func checkNewTableSchema(tableName string, doltSch schema.Schema) error {
 	// Prevent any tables that use Spatial Types as Primary Key from being created
 	if schema.IsUsingSpatialColAsKey(doltSch) {
 		return schema.ErrUsingSpatialKey.New(tableName)
 	}
+	return nil
+}
 
-	// Prevent any tables that use BINARY, CHAR, VARBINARY, VARCHAR prefixes
-
-	if schema.HasAutoIncrement(doltSch) {
-		ait, err := db.gs.AutoIncrementTracker(ctx)
-		if err != nil {
-			return err
-		}
-		ait.AddNewTable(tableName)
+// trackNewTable adds the new table named, which has the schema |doltSch|, to the auto increment tracker if it has an
+// auto increment column.
+func (db Database) trackNewTable(ctx *sql.Context, tableName string, doltSch schema.Schema) error {
+	if !schema.HasAutoIncrement(doltSch) {
+		return nil
 	}
-
-	return db.createDoltTable(ctx, tableName, root, doltSch)
+	ait, err := db.gs.AutoIncrementTracker(ctx)
+	if err != nil {
+		return err
+	}
+	ait.AddNewTable(tableName)
+	return nil
 }
 
 // createIndexedSqlTable is the private version of createSqlTable. It doesn't enforce any table name checks.
 func (db Database) createIndexedSqlTable(ctx *sql.Context, tableName string, sch sql.PrimaryKeySchema, idxDef sql.IndexDef, collation sql.CollationID) error {
+	sch, collation, err := db.withDefaultCollation(ctx, sch, collation)
+	if err != nil {
+		return err
+	}
+
 	ws, err := db.GetWorkingSet(ctx)
 	if err != nil {
 		return err
@@ -977,9 +1473,8 @@ func (db Database) createIndexedSqlTable(ctx *sql.Context, tableName string, sch
 		return err
 	}
 
-	// Prevent any tables that use Spatial Types as Primary Key from being created
-	if schema.IsUsingSpatialColAsKey(doltSch) {
-		return schema.ErrUsingSpatialKey.New(tableName)
+	if err = checkNewTableSchema(tableName, doltSch); err != nil {
+		return err
 	}
 
 	// Prevent any tables that use BINARY, CHAR, VARBINARY, VARCHAR prefixes in Primary Key
@@ -990,12 +1485,8 @@ func (db Database) createIndexedSqlTable(ctx *sql.Context, tableName string, sch
 		}
 	}
 
-	if schema.HasAutoIncrement(doltSch) {
-		ait, err := db.gs.AutoIncrementTracker(ctx)
-		if err != nil {
-			return err
-		}
-		ait.AddNewTable(tableName)
+	if err = db.trackNewTable(ctx, tableName, doltSch); err != nil {
+		return err
 	}
 
 	return db.createDoltTable(ctx, tableName, root, doltSch)
@@ -1003,10 +1494,20 @@ func (db Database) createIndexedSqlTable(ctx *sql.Context, tableName string, sch
 
 // createDoltTable creates a table on the database using the given dolt schema while not enforcing table baseName checks.
 func (db Database) createDoltTable(ctx *sql.Context, tableName string, root *doltdb.RootValue, doltSch schema.Schema) error {
-	if exists, err := root.HasTable(ctx, tableName); err != nil {
+	newRoot, err := createEmptyDoltTable(ctx, tableName, root, doltSch)
+	if err != nil {
 		return err
+	}
+
+	return db.SetRoot(ctx, newRoot)
+}
+
+// createEmptyDoltTable returns |root| with an empty table with the name and dolt schema given added to it.
+func createEmptyDoltTable(ctx *sql.Context, tableName string, root *doltdb.RootValue, doltSch schema.Schema) (*doltdb.RootValue, error) {
+	if exists, err := root.HasTable(ctx, tableName); err != nil {
+		return nil, err
 	} else if exists {
-		return sql.ErrTableAlreadyExists.New(tableName)
+		return nil, sql.ErrTableAlreadyExists.New(tableName)
 	}
 
 	var conflictingTbls []string
@@ -1023,15 +1524,10 @@ func (db Database) createDoltTable(ctx *sql.Context, tableName string, root *dol
 	})
 
 	if len(conflictingTbls) > 0 {
-		return fmt.Errorf(strings.Join(conflictingTbls, "\n"))
+		return nil, fmt.Errorf(strings.Join(conflictingTbls, "\n"))
 	}
 
-	newRoot, err := root.CreateEmptyTable(ctx, tableName, doltSch)
-	if err != nil {
-		return err
-	}
-
-	return db.SetRoot(ctx, newRoot)
+	return root.CreateEmptyTable(ctx, tableName, doltSch)
 }
 
 // CreateTemporaryTable creates a table that only exists the length of a session.
@@ -1059,11 +1555,6 @@ func (db Database) RenameTable(ctx *sql.Context, oldName, newName string) error
 	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
 		return err
 	}
-	root, err := db.GetRoot(ctx)
-
-	if err != nil {
-		return err
-	}
 
 	if doltdb.IsNonAlterableSystemTable(oldName) {
 		return ErrSystemTableAlter.New(oldName)
@@ -1077,17 +1568,23 @@ func (db Database) RenameTable(ctx *sql.Context, oldName, newName string) error
 		return ErrInvalidTableName.New(newName)
 	}
 
-	if _, ok, _ := db.GetTableInsensitive(ctx, newName); ok {
-		return sql.ErrTableAlreadyExists.New(newName)
-	}
+	return db.WithSchemaLock(ctx, func() error {
+		root, err := db.GetRoot(ctx)
+		if err != nil {
+			return err
+		}
 
-	newRoot, err := renameTable(ctx, root, oldName, newName)
+		if _, ok, _ := db.GetTableInsensitive(ctx, newName); ok {
+			return sql.ErrTableAlreadyExists.New(newName)
+		}
 
-	if err != nil {
-		return err
-	}
+		newRoot, err := renameTable(ctx, root, oldName, newName)
+		if err != nil {
+			return err
+		}
 
-	return db.SetRoot(ctx, newRoot)
+		return db.SetRoot(ctx, newRoot)
+	})
 }
 
 // GetViewDefinition implements sql.ViewDatabase
@@ -1144,6 +1641,28 @@ func (db Database) GetViewDefinition(ctx *sql.Context, viewName string) (sql.Vie
 	return viewDef, found, nil
 }
 
+// InvalidateSchemaCache discards the views cached by this session for the current root of this database.
+func (db Database) InvalidateSchemaCache(ctx *sql.Context) error {
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	key, err := doltdb.NewDataCacheKey(root)
+	if err != nil {
+		return err
+	}
+
+	ds := dsess.DSessFromSess(ctx.Session)
+	dbState, _, err := ds.LookupDbState(ctx, db.RevisionQualifiedName())
+	if err != nil {
+		return err
+	}
+
+	dbState.SessionCache().ClearViewCache(key)
+	return nil
+}
+
 func getViewDefinitionFromSchemaFragmentsOfView(ctx *sql.Context, tbl *WritableDoltTable, viewName string) ([]sql.ViewDefinition, sql.ViewDefinition, bool, error) {
 	fragments, err := getSchemaFragmentsOfType(ctx, tbl, viewFragment)
 	if err != nil {
@@ -1201,7 +1720,18 @@ func (db Database) AllViews(ctx *sql.Context) ([]sql.ViewDefinition, error) {
 // with that name already exists.
 func (db Database) CreateView(ctx *sql.Context, name string, selectStatement, createViewStmt string) error {
 	err := sql.ErrExistingView.New(db.Name(), name)
-	return db.addFragToSchemasTable(ctx, "view", name, createViewStmt, time.Unix(0, 0).UTC(), err)
+	return db.addFragToSchemasTable(ctx, "view", name, createViewStmt, time.Unix(0, 0).UTC(), sql.LoadSqlMode(ctx), err)
+}
+
+// CreateViewWithSqlMode is like CreateView, but stores the view with the SQL mode given instead of the session's.
+func (db Database) CreateViewWithSqlMode(ctx *sql.Context, name string, selectStatement, createViewStmt, sqlMode string) error {
+	mode := sql.NewSqlModeFromString(sqlMode)
+	if _, err := sqlparser.ParseWithOptions(createViewStmt, mode.ParserOptions()); err != nil {
+		return fmt.Errorf("cannot create view %s with sql_mode '%s': %w", name, mode.String(), err)
+	}
+
+	err := sql.ErrExistingView.New(db.Name(), name)
+	return db.addFragToSchemasTable(ctx, "view", name, createViewStmt, time.Unix(0, 0).UTC(), mode, err)
 }
 
 // DropView implements sql.ViewDropper. Removes a view from persistence in the
@@ -1250,6 +1780,7 @@ func (db Database) CreateTrigger(ctx *sql.Context, definition sql.TriggerDefinit
 		definition.Name,
 		definition.CreateStatement,
 		definition.CreatedAt,
+		sql.LoadSqlMode(ctx),
 		fmt.Errorf("triggers `%s` already exists", definition.Name), //TODO: add a sql error and return that instead
 	)
 }
@@ -1322,6 +1853,7 @@ func (db Database) SaveEvent(ctx *sql.Context, ed sql.EventDefinition) error {
 		ed.Name,
 		ed.CreateStatement,
 		ed.CreatedAt,
+		sql.LoadSqlMode(ctx),
 		sql.ErrEventAlreadyExists.New(ed.Name),
 	)
 }
@@ -1341,16 +1873,62 @@ func (db Database) UpdateEvent(ctx *sql.Context, originalName string, ed sql.Eve
 	return db.SaveEvent(ctx, ed)
 }
 
-// GetStoredProcedure implements sql.StoredProcedureDatabase.
+// GetStoredProcedure implements sql.StoredProcedureDatabase. The name is not case-sensitive. Returns
+// ErrAmbiguousStoredProcedure if more than one procedure matches the name.
 func (db Database) GetStoredProcedure(ctx *sql.Context, name string) (sql.StoredProcedureDetails, bool, error) {
-	procedures, err := DoltProceduresGetAll(ctx, db, strings.ToLower(name))
+	return db.GetStoredProcedureForDefiner(ctx, name, "")
+}
+
+// GetStoredProcedureForDefiner returns the stored procedure with the name given created by |definer|, or by any
+// definer if it's empty. Returns ErrAmbiguousStoredProcedure if more than one matches.
+func (db Database) GetStoredProcedureForDefiner(ctx *sql.Context, name, definer string) (sql.StoredProcedureDetails, bool, error) {
+	procedures, err := doltProceduresGetAllFold(ctx, db, name)
 	if err != nil {
+		return sql.StoredProcedureDetails{}, false, err
+	}
+
+	var matches []sql.StoredProcedureDetails
+	for _, procedure := range procedures {
+		if definer != "" {
+			procDefiner, err := storedProcedureDefiner(procedure)
+			if err != nil {
+				return sql.StoredProcedureDetails{}, false, err
+			}
+			if normalizeDefiner(procDefiner) != normalizeDefiner(definer) {
+				continue
+			}
+		}
+		matches = append(matches, procedure)
+	}
+
+	switch len(matches) {
+	case 0:
 		return sql.StoredProcedureDetails{}, false, nil
+	case 1:
+		return matches[0], true, nil
+	default:
+		return sql.StoredProcedureDetails{}, false, ErrAmbiguousStoredProcedure.New(name, len(matches))
+	}
+}
+
+// storedProcedureDefiner returns the definer named in the CREATE PROCEDURE statement of the procedure given, or an
+// empty string if it doesn't name one.
+func storedProcedureDefiner(procedure sql.StoredProcedureDetails) (string, error) {
+	stmt, err := sqlparser.ParseWithOptions(procedure.CreateStatement, sql.NewSqlModeFromString(procedure.SqlMode).ParserOptions())
+	if err != nil {
+		return "", err
 	}
-	if len(procedures) == 1 {
-		return procedures[0], true, nil
+	ddl, ok := stmt.(*sqlparser.DDL)
+	if !ok || ddl.ProcedureSpec == nil {
+		return "", fmt.Errorf("unexpected create statement for stored procedure %s: %s", procedure.Name, procedure.CreateStatement)
 	}
-	return sql.StoredProcedureDetails{}, false, nil
+	return ddl.ProcedureSpec.Definer, nil
+}
+
+// normalizeDefiner returns the definer given with any quoting removed and in lower case, so that user@host,
+// `user`@`host` and 'user'@'host' all compare equal.
+func normalizeDefiner(definer string) string {
+	return strings.ToLower(strings.NewReplacer("`", "", "'", "", "\"", "").Replace(definer))
 }
 
 // GetStoredProcedures implements sql.StoredProcedureDatabase.
@@ -1374,7 +1952,7 @@ func (db Database) DropStoredProcedure(ctx *sql.Context, name string) error {
 	return DoltProceduresDropProcedure(ctx, db, name)
 }
 
-func (db Database) addFragToSchemasTable(ctx *sql.Context, fragType, name, definition string, created time.Time, existingErr error) (err error) {
+func (db Database) addFragToSchemasTable(ctx *sql.Context, fragType, name, definition string, created time.Time, sqlMode *sql.SqlMode, existingErr error) (err error) {
 	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
 		return err
 	}
@@ -1408,36 +1986,59 @@ func (db Database) addFragToSchemasTable(ctx *sql.Context, fragType, name, defin
 		return err
 	}
 
-	sqlMode := sql.LoadSqlMode(ctx)
-
 	return inserter.Insert(ctx, sql.Row{fragType, name, definition, extraJSON, sqlMode.String()})
 }
 
 func (db Database) dropFragFromSchemasTable(ctx *sql.Context, fragType, name string, missingErr error) error {
+	return db.dropFragsFromSchemasTable(ctx, []FragSpec{{Type: fragType, Name: name}}, func(FragSpec) error {
+		return missingErr
+	})
+}
+
+// dropFragsFromSchemasTable deletes the schema fragments given from the dolt_schemas table, returning the error
+// produced by |missingErr| without deleting anything if any of them doesn't exist.
+func (db Database) dropFragsFromSchemasTable(ctx *sql.Context, frags []FragSpec, missingErr func(FragSpec) error) error {
 	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
 		return err
 	}
+	if len(frags) == 0 {
+		return nil
+	}
 
 	stbl, found, err := db.GetTableInsensitive(ctx, doltdb.SchemasTableName)
 	if err != nil {
 		return err
 	}
 	if !found {
-		return missingErr
+		return missingErr(frags[0])
 	}
 
 	tbl := stbl.(*WritableDoltTable)
-	row, exists, err := fragFromSchemasTable(ctx, tbl, fragType, name)
-	if err != nil {
-		return err
-	}
-	if !exists {
-		return missingErr
+	seen := make(map[FragSpec]bool, len(frags))
+	rows := make([]sql.Row, 0, len(frags))
+	for _, frag := range frags {
+		key := FragSpec{Type: strings.ToLower(frag.Type), Name: strings.ToLower(frag.Name)}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		row, exists, err := fragFromSchemasTable(ctx, tbl, frag.Type, frag.Name)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return missingErr(frag)
+		}
+		rows = append(rows, row)
 	}
+
 	deleter := tbl.Deleter(ctx)
-	err = deleter.Delete(ctx, row)
-	if err != nil {
-		return err
+	for _, row := range rows {
+		err = deleter.Delete(ctx, row)
+		if err != nil {
+			return err
+		}
 	}
 
 	err = deleter.Close(ctx)
@@ -1501,6 +2102,37 @@ func (db Database) GetCollation(ctx *sql.Context) sql.CollationID {
 	return sql.CollationID(collation)
 }
 
+// GetTableCollation returns the collation of the table named in the working root.
+func (db Database) GetTableCollation(ctx *sql.Context, tableName string) (sql.CollationID, error) {
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return sql.Collation_Unspecified, err
+	}
+
+	tbl, _, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return sql.Collation_Unspecified, err
+	}
+	if !ok {
+		return sql.Collation_Unspecified, sql.ErrTableNotFound.New(tableName)
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return sql.Collation_Unspecified, err
+	}
+
+	if collation := sch.GetCollation(); collation != schema.Collation_Unspecified {
+		return sql.CollationID(collation), nil
+	}
+
+	dbCollation, err := root.GetCollation(ctx)
+	if err != nil {
+		return sql.Collation_Unspecified, err
+	}
+	return sql.CollationID(dbCollation), nil
+}
+
 // SetCollation implements the interface sql.CollatedDatabase.
 func (db Database) SetCollation(ctx *sql.Context, collation sql.CollationID) error {
 	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
@@ -677,6 +677,170 @@ func (p DoltDatabaseProvider) DropDatabase(ctx *sql.Context, name string) error
 	return p.invalidateDbStateInAllSessions(ctx, name)
 }
 
+// loadMovedDatabase loads the database in the directory |dbLoc| as a database named |name|, locking it if a server is
+// running. If the database can't be loaded, it's closed again so that its directory can be moved.
+func (p DoltDatabaseProvider) loadMovedDatabase(ctx *sql.Context, dbLoc, name string, opts editor.Options) (Database, *env.DoltEnv, error) {
+	fs, err := p.fs.WithWorkingDir(dbLoc)
+	if err != nil {
+		return Database{}, nil, err
+	}
+	dEnv := env.Load(ctx, env.GetCurrentUserHomeDir, fs, p.dbFactoryUrl, "TODO")
+	if dEnv.DBLoadError != nil {
+		return Database{}, nil, dEnv.DBLoadError
+	}
+
+	_, lckDeets := sqlserver.GetRunningServer()
+	if lckDeets != nil {
+		err = dEnv.Lock(lckDeets)
+		if err != nil {
+			ctx.GetLogger().Warnf("Failed to lock renamed database: %s", err.Error())
+		}
+	}
+
+	opts.Deaf = dEnv.DbEaFactory()
+	db, err := NewDatabase(ctx, name, dEnv.DbData(), opts)
+	if err != nil {
+		if lckDeets != nil {
+			_ = dEnv.Unlock()
+		}
+		_ = dEnv.DoltDB.Close()
+		_ = dbfactory.DeleteFromSingletonCache(dbLoc + "/.dolt/noms")
+		return Database{}, nil, err
+	}
+	return db, dEnv, nil
+}
+
+// reopenRenamedDatabase reopens |db|, which was closed to be renamed, from |dbLoc| after renaming it failed with
+// |renameErr|, and registers it under its old name again in place of the closed database. It returns |renameErr|, or
+// an error describing both failures if the database can't be reopened.
+func (p DoltDatabaseProvider) reopenRenamedDatabase(ctx *sql.Context, db Database, dbLoc string, renameErr error) error {
+	reopened, dEnv, err := p.loadMovedDatabase(ctx, dbLoc, db.Name(), db.EditOptions())
+	if err != nil {
+		return fmt.Errorf("%w; unable to reopen database %s: %s", renameErr, db.Name(), err.Error())
+	}
+
+	// Revision databases derived from the database share the closed DoltDB, so they're loaded again when next used
+	key := formatDbMapKeyName(db.Name())
+	for dbName := range p.databases {
+		if strings.HasPrefix(strings.ToLower(dbName), key+dsess.DbRevisionDelimiter) {
+			delete(p.databases, dbName)
+		}
+	}
+	p.databases[key] = reopened
+	p.dbLocations[key] = dEnv.FS
+	if err = p.invalidateDbStateInAllSessions(ctx, db.Name()); err != nil {
+		return fmt.Errorf("%w; %s", renameErr, err.Error())
+	}
+	return renameErr
+}
+
+// RenameDatabase implements dsess.DoltDatabaseProvider. It moves the directory of the database named to a directory
+// with the new name and reloads it from there, so its history, branches and working sets are all preserved. Revision
+// databases such as `mydb/branch1` can't be renamed themselves, but are available under the new name afterward. Every
+// session's state for the database is invalidated, and the current session switches to the new name if it was using
+// the database. The database must have its own directory in the data directory, and the new name must not be in use.
+func (p DoltDatabaseProvider) RenameDatabase(ctx *sql.Context, oldName, newName string) error {
+	if _, revision := dsess.SplitRevisionDbName(oldName); revision != "" {
+		return fmt.Errorf("unable to rename revision database: %s", oldName)
+	}
+	if newName == "" || strings.ContainsAny(newName, dsess.DbRevisionDelimiter+"\\") {
+		return fmt.Errorf("invalid database name: '%s'", newName)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	oldKey, newKey := formatDbMapKeyName(oldName), formatDbMapKeyName(newName)
+	sqlDb, ok := p.databases[oldKey]
+	if !ok {
+		return sql.ErrDatabaseNotFound.New(oldName)
+	}
+	db, ok := sqlDb.(Database)
+	if !ok {
+		return fmt.Errorf("unable to rename database %s: renaming %T databases is not supported", oldName, sqlDb)
+	}
+	if _, ok := p.databases[newKey]; ok && newKey != oldKey {
+		return sql.ErrDatabaseExists.New(newName)
+	}
+
+	dbLoc := p.dbLocations[oldKey]
+	if dbLoc == nil {
+		return sql.ErrDatabaseNotFound.New(oldName)
+	}
+	oldDbLoc, err := dbLoc.Abs("")
+	if err != nil {
+		return err
+	}
+	rootDbLoc, err := p.fs.Abs("")
+	if err != nil {
+		return err
+	}
+	if oldDbLoc == rootDbLoc {
+		return fmt.Errorf("unable to rename database %s: it is stored at the root of the data directory", oldName)
+	}
+	newDbLoc, err := p.fs.Abs(newName)
+	if err != nil {
+		return err
+	}
+	if newDbLoc != oldDbLoc {
+		if exists, _ := p.fs.Exists(newDbLoc); exists {
+			return fmt.Errorf("unable to rename database %s: file exists at %s", oldName, newName)
+		}
+	}
+
+	err = db.ddb.Close()
+	if err != nil {
+		return err
+	}
+	err = dbfactory.DeleteFromSingletonCache(oldDbLoc + "/.dolt/noms")
+	if err != nil {
+		return err
+	}
+
+	err = p.fs.MoveFile(oldDbLoc, newDbLoc)
+	if err != nil {
+		return p.reopenRenamedDatabase(ctx, db, oldDbLoc, err)
+	}
+
+	newDb, newEnv, err := p.loadMovedDatabase(ctx, newDbLoc, newName, db.EditOptions())
+	if err != nil {
+		if newDbLoc != oldDbLoc {
+			if mvErr := p.fs.MoveFile(newDbLoc, oldDbLoc); mvErr != nil {
+				return fmt.Errorf("%w; unable to move database %s back from %s: %s", err, oldName, newName, mvErr.Error())
+			}
+		}
+		return p.reopenRenamedDatabase(ctx, db, oldDbLoc, err)
+	}
+
+	// Revision databases derived from the old name, e.g. by USE or connection strings, no longer exist
+	derivativeNamePrefix := oldKey + dsess.DbRevisionDelimiter
+	for dbName := range p.databases {
+		if strings.HasPrefix(strings.ToLower(dbName), derivativeNamePrefix) {
+			delete(p.databases, dbName)
+		}
+	}
+	delete(p.databases, oldKey)
+	delete(p.dbLocations, oldKey)
+	p.databases[newKey] = newDb
+	p.dbLocations[newKey] = newEnv.FS
+
+	err = p.invalidateDbStateInAllSessions(ctx, oldName)
+	if err != nil {
+		return err
+	}
+
+	currentBaseName, revision := dsess.SplitRevisionDbName(ctx.GetCurrentDatabase())
+	if strings.EqualFold(currentBaseName, oldName) {
+		if revision != "" {
+			ctx.SetCurrentDatabase(dsess.RevisionDbName(newName, revision))
+		} else {
+			ctx.SetCurrentDatabase(newName)
+		}
+	}
+
+	return nil
+}
+
 // invalidateDbStateInAllSessions removes the db state for this database from every session. This is necessary when a
 // database is dropped, so that other sessions don't use stale db state.
 func (p DoltDatabaseProvider) invalidateDbStateInAllSessions(ctx *sql.Context, name string) error {
@@ -1199,6 +1363,9 @@ func (p DoltDatabaseProvider) TableFunction(_ *sql.Context, name string) (sql.Ta
 	case "dolt_query_diff":
 		dtf := &QueryDiffTableFunction{}
 		return dtf, nil
+	case "dolt_fsck":
+		dtf := &FsckTableFunction{}
+		return dtf, nil
 	}
 
 	return nil, sql.ErrTableFunctionNotFound.New(name)
@@ -90,7 +90,16 @@ func (t *HashOf) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 					return nil, orgErr
 				}
 			} else {
-				return nil, err
+				// fall back to commit spec resolution, which also matches fully qualified refs like remotes/origin/main
+				orgErr := err
+				cs, csErr := doltdb.NewCommitSpec(name)
+				if csErr != nil {
+					return nil, orgErr
+				}
+				cm, err = ddb.Resolve(ctx, cs, nil)
+				if err != nil {
+					return nil, orgErr
+				}
 			}
 		} else {
 			cm, err = ddb.ResolveCommitRef(ctx, ref)
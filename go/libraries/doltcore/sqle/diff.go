@@ -0,0 +1,281 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlfmt"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+	"github.com/dolthub/dolt/go/store/hash"
+	storetypes "github.com/dolthub/dolt/go/store/types"
+)
+
+var ErrNotRootHash = errors.NewKind("%s is not the hash of a root value")
+
+// DiffRows returns an iterator over the rows of the table named that changed between |fromRef| and |toRef|.
+func (db Database) DiffRows(ctx *sql.Context, tableName, fromRef, toRef string) (*dtables.RowDiffIter, error) {
+	toTbl, toName, toDate, err := db.tableAtRef(ctx, tableName, toRef)
+	if err != nil {
+		return nil, err
+	}
+
+	fromTbl, fromName, fromDate, err := db.tableAtRef(ctx, tableName, fromRef)
+	if err != nil {
+		return nil, err
+	}
+
+	if toTbl == nil && fromTbl == nil {
+		return nil, sql.ErrTableNotFound.New(tableName)
+	}
+
+	return dtables.NewRowDiffIter(ctx, db.ddb, toTbl, fromTbl, toName, fromName, toDate, fromDate)
+}
+
+// DiffAsSQL writes the INSERT, UPDATE and DELETE statements that make the row changes made to the table named between
+// |fromRef| and |toRef| to |w|, one per line. Schema changes aren't written.
+func (db Database) DiffAsSQL(ctx *sql.Context, tableName, fromRef, toRef string, w io.Writer) error {
+	toTbl, toName, toDate, err := db.tableAtRef(ctx, tableName, toRef)
+	if err != nil {
+		return err
+	}
+
+	fromTbl, fromName, fromDate, err := db.tableAtRef(ctx, tableName, fromRef)
+	if err != nil {
+		return err
+	}
+
+	tbl := toTbl
+	if tbl == nil {
+		tbl = fromTbl
+	}
+	if tbl == nil {
+		return sql.ErrTableNotFound.New(tableName)
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+
+	iter, err := dtables.NewRowDiffIter(ctx, db.ddb, toTbl, fromTbl, toName, fromName, toDate, fromDate)
+	if err != nil {
+		return err
+	}
+	defer iter.Close(ctx)
+
+	keyless := schema.IsKeyless(sch)
+	cols := sch.GetAllCols().GetColumns()
+	for {
+		diffType, from, to, err := iter.Next(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		var stmts []string
+		switch {
+		case diffType == "added":
+			stmt, err := sqlfmt.SqlRowAsInsertStmt(to, tableName, sch)
+			if err != nil {
+				return err
+			}
+			stmts = append(stmts, stmt)
+		case diffType == "removed" || keyless:
+			var limit uint64
+			if keyless {
+				limit = 1
+			}
+			stmt, err := sqlfmt.SqlRowAsDeleteStmt(from, tableName, sch, limit)
+			if err != nil {
+				return err
+			}
+			stmts = append(stmts, stmt)
+			if to != nil {
+				stmt, err = sqlfmt.SqlRowAsInsertStmt(to, tableName, sch)
+				if err != nil {
+					return err
+				}
+				stmts = append(stmts, stmt)
+			}
+		default:
+			changed := set.NewEmptyStrSet()
+			for i, col := range cols {
+				cmp, err := col.TypeInfo.ToSqlType().Compare(from[i], to[i])
+				if err != nil {
+					return err
+				}
+				if cmp != 0 {
+					changed.Add(col.Name)
+				}
+			}
+			if changed.Size() == 0 {
+				continue
+			}
+			stmt, err := sqlfmt.SqlRowAsUpdateStmt(to, tableName, sch, changed)
+			if err != nil {
+				return err
+			}
+			stmts = append(stmts, stmt)
+		}
+
+		for _, stmt := range stmts {
+			if _, err := io.WriteString(w, stmt+"\n"); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ChangesSince returns an iterator over the rows of the table named that changed since the root with hash |sinceRoot|,
+// along with the hash of the current working root to pass next time.
+func (db Database) ChangesSince(ctx *sql.Context, tableName string, sinceRoot hash.Hash) (*dtables.RowDiffIter, hash.Hash, error) {
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return nil, hash.Hash{}, err
+	}
+	rootHash, err := root.HashOf()
+	if err != nil {
+		return nil, hash.Hash{}, err
+	}
+	if rootHash == sinceRoot {
+		return dtables.EmptyRowDiffIter(), rootHash, nil
+	}
+
+	toTbl, _, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return nil, hash.Hash{}, err
+	} else if !ok {
+		toTbl = nil
+	}
+
+	var fromTbl *doltdb.Table
+	if !sinceRoot.IsEmpty() {
+		fromRoot, err := db.ddb.ReadRootValue(ctx, sinceRoot)
+		if err == doltdb.ErrNoRootValAtHash {
+			return nil, hash.Hash{}, ErrNotRootHash.New(sinceRoot.String())
+		} else if err != nil {
+			return nil, hash.Hash{}, err
+		}
+		fromTbl, _, ok, err = fromRoot.GetTableInsensitive(ctx, tableName)
+		if err != nil {
+			return nil, hash.Hash{}, err
+		} else if !ok {
+			fromTbl = nil
+		}
+	}
+
+	iter, err := db.rowDiffIterIfChanged(ctx, toTbl, fromTbl, doltdb.Working, sinceRoot.String())
+	if err != nil {
+		return nil, hash.Hash{}, err
+	}
+	return iter, rootHash, nil
+}
+
+// WorkingDiff returns an iterator over the uncommitted changes to the rows of the table named.
+func (db Database) WorkingDiff(ctx *sql.Context, tableName string) (*dtables.RowDiffIter, error) {
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	headRoot, err := db.GetHeadRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	toTbl, _, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		toTbl = nil
+	}
+	fromTbl, _, ok, err := headRoot.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		fromTbl = nil
+	}
+
+	if toTbl == nil && fromTbl == nil {
+		return nil, sql.ErrTableNotFound.New(tableName)
+	}
+	return db.rowDiffIterIfChanged(ctx, toTbl, fromTbl, doltdb.Working, "HEAD")
+}
+
+// rowDiffIterIfChanged returns an iterator over the changes from |fromTbl| to |toTbl|, either of which may be nil, or
+// an empty iterator without reading any rows if the two tables have the same hash or are both nil.
+func (db Database) rowDiffIterIfChanged(ctx *sql.Context, toTbl, fromTbl *doltdb.Table, toName, fromName string) (*dtables.RowDiffIter, error) {
+	if toTbl == nil && fromTbl == nil {
+		return dtables.EmptyRowDiffIter(), nil
+	}
+	if toTbl != nil && fromTbl != nil {
+		toHash, err := toTbl.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		fromHash, err := fromTbl.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		if toHash == fromHash {
+			return dtables.EmptyRowDiffIter(), nil
+		}
+	}
+
+	return dtables.NewRowDiffIter(ctx, db.ddb, toTbl, fromTbl, toName, fromName, nil, nil)
+}
+
+// tableAtRef returns the table named at the revision |refStr|, along with the name and commit time of the revision
+// as they appear in the dolt_commit_diff_$table system table. The table is nil if it doesn't exist at that revision.
+func (db Database) tableAtRef(ctx *sql.Context, tableName, refStr string) (*doltdb.Table, string, *storetypes.Timestamp, error) {
+	cm, root, isBranchWorking, err := db.rootAtRef(ctx, refStr)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	tbl, _, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if !ok {
+		tbl = nil
+	}
+
+	if strings.EqualFold(refStr, doltdb.Working) || strings.EqualFold(refStr, doltdb.Staged) {
+		return tbl, strings.ToUpper(refStr), nil, nil
+	}
+	if isBranchWorking {
+		return tbl, refStr, nil, nil
+	}
+
+	h, err := cm.HashOf()
+	if err != nil {
+		return nil, "", nil, err
+	}
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	t := meta.Time()
+
+	return tbl, h.String(), (*storetypes.Timestamp)(&t), nil
+}
@@ -0,0 +1,228 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"io"
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+)
+
+var ErrDiff3SchemaChanged = errors.NewKind("cannot diff table %s three ways: its columns differ between %s and %s")
+var ErrDiff3Keyless = errors.NewKind("cannot diff table %s three ways: only tables with a primary key are supported")
+
+// Diff3Row is the version of a single row at the base, ours and theirs revisions given to Database.Diff3.
+type Diff3Row struct {
+	// Key is the row's primary key, in primary key order.
+	Key sql.Row
+	// Base is the row at the base revision, or nil if it doesn't exist there.
+	Base sql.Row
+	// Ours is the row at the ours revision, or nil if it doesn't exist there.
+	Ours sql.Row
+	// Theirs is the row at the theirs revision, or nil if it doesn't exist there.
+	Theirs sql.Row
+}
+
+// Diff3 returns the rows of the table named at three revisions, matched up by primary key: |base|, |ours| and
+// |theirs|, which may be any refs accepted by ResolveRef, and are usually the merge base and the two sides of a merge.
+// If |onlyDiffering| is true, only rows that aren't the same at all three revisions are returned, which are found by
+// diffing |ours| and |theirs| against |base| rather than reading every row. Rows are returned in primary key order.
+// The table must have a primary key and the same columns at every revision where it exists; ErrDiff3Keyless and
+// ErrDiff3SchemaChanged are returned otherwise.
+func (db Database) Diff3(ctx *sql.Context, tableName, base, ours, theirs string, onlyDiffering bool) ([]Diff3Row, error) {
+	refs := []string{base, ours, theirs}
+	var tbls [3]*doltdb.Table
+	var names [3]string
+	var sch schema.Schema
+	var schRef string
+	for i, refStr := range refs {
+		tbl, name, _, err := db.tableAtRef(ctx, tableName, refStr)
+		if err != nil {
+			return nil, err
+		}
+		if tbl == nil {
+			continue
+		}
+		tbls[i], names[i] = tbl, name
+
+		tblSch, err := tbl.GetSchema(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if sch == nil {
+			sch, schRef = tblSch, refStr
+		} else if !schema.ColCollsAreEqual(sch.GetAllCols(), tblSch.GetAllCols()) {
+			return nil, ErrDiff3SchemaChanged.New(tableName, schRef, refStr)
+		}
+	}
+	if sch == nil {
+		return nil, sql.ErrTableNotFound.New(tableName)
+	}
+	if schema.IsKeyless(sch) {
+		return nil, ErrDiff3Keyless.New(tableName)
+	}
+
+	sqlSch, err := sqlutil.FromDoltSchema(tableName, sch)
+	if err != nil {
+		return nil, err
+	}
+
+	d3 := &diff3Rows{
+		pkSch: sqlSch,
+		keys:  newPrimaryKeyEncoder(sch, sqlSch.PkOrdinals, db.ddb.NodeStore()),
+		byKey: make(map[string]*diff3Entry),
+	}
+
+	// ours and theirs are the same as base for rows that aren't in their diffs against base
+	for side := 1; side <= 2; side++ {
+		if tbls[0] == nil && tbls[side] == nil {
+			continue
+		}
+		err = db.iterDiff3Changes(ctx, tbls[side], tbls[0], names[side], names[0], func(from, to sql.Row) error {
+			keyRow := to
+			if keyRow == nil {
+				keyRow = from
+			}
+			e, err := d3.entry(ctx, keyRow)
+			if err != nil {
+				return err
+			}
+			e.Base = from
+			if side == 1 {
+				e.Ours, e.oursChanged = to, true
+			} else {
+				e.Theirs, e.theirsChanged = to, true
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, e := range d3.entries {
+		if !e.oursChanged {
+			e.Ours = e.Base
+		}
+		if !e.theirsChanged {
+			e.Theirs = e.Base
+		}
+	}
+
+	if !onlyDiffering && tbls[0] != nil {
+		// every row of base that isn't in either diff is the same at all three revisions
+		err = db.iterDiff3Changes(ctx, tbls[0], nil, names[0], "", func(_, to sql.Row) error {
+			key, err := d3.keys.encode(ctx, to)
+			if err != nil {
+				return err
+			}
+			if _, ok := d3.byKey[key]; ok {
+				return nil
+			}
+			e, err := d3.entry(ctx, to)
+			if err != nil {
+				return err
+			}
+			e.Base, e.Ours, e.Theirs = to, to, to
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res := make([]Diff3Row, len(d3.entries))
+	for i, e := range d3.entries {
+		res[i] = e.Diff3Row
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return d3.compareKeys(res[i].Key, res[j].Key) < 0
+	})
+	return res, nil
+}
+
+// iterDiff3Changes calls |cb| with the from and to rows of each row that changed between |fromTbl| and |toTbl|.
+func (db Database) iterDiff3Changes(ctx *sql.Context, toTbl, fromTbl *doltdb.Table, toName, fromName string, cb func(from, to sql.Row) error) error {
+	iter, err := dtables.NewRowDiffIter(ctx, db.ddb, toTbl, fromTbl, toName, fromName, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer iter.Close(ctx)
+
+	for {
+		_, from, to, err := iter.Next(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err = cb(from, to); err != nil {
+			return err
+		}
+	}
+}
+
+// diff3Rows collects the rows of a three-way diff, matching them up by primary key.
+type diff3Rows struct {
+	pkSch   sql.PrimaryKeySchema
+	keys    *primaryKeyEncoder
+	byKey   map[string]*diff3Entry
+	entries []*diff3Entry
+}
+
+// diff3Entry is a row of a three-way diff, along with which sides of it changed the row.
+type diff3Entry struct {
+	Diff3Row
+	oursChanged, theirsChanged bool
+}
+
+// entry returns the entry for the primary key of |row|, adding one if there isn't one yet.
+func (d3 *diff3Rows) entry(ctx *sql.Context, row sql.Row) (*diff3Entry, error) {
+	key, err := d3.keys.encode(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	e, ok := d3.byKey[key]
+	if !ok {
+		e = &diff3Entry{Diff3Row: Diff3Row{Key: d3.key(row)}}
+		d3.byKey[key] = e
+		d3.entries = append(d3.entries, e)
+	}
+	return e, nil
+}
+
+func (d3 *diff3Rows) key(row sql.Row) sql.Row {
+	key := make(sql.Row, len(d3.pkSch.PkOrdinals))
+	for i, ord := range d3.pkSch.PkOrdinals {
+		key[i] = row[ord]
+	}
+	return key
+}
+
+func (d3 *diff3Rows) compareKeys(a, b sql.Row) int {
+	for i, ord := range d3.pkSch.PkOrdinals {
+		cmp, err := d3.pkSch.Schema[ord].Type.Compare(a[i], b[i])
+		if err != nil || cmp != 0 {
+			return cmp
+		}
+	}
+	return 0
+}
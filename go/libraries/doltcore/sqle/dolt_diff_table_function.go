@@ -36,6 +36,10 @@ import (
 
 var ErrInvalidNonLiteralArgument = errors.NewKind("Invalid argument to %s: %s – only literal values supported")
 
+// modifiedColumnsOption is an optional argument to dolt_diff, given as '--modified-columns=c1,c2', which limits the
+// modified rows returned to those in which at least one of the named columns changed.
+const modifiedColumnsOption = "--modified-columns="
+
 var _ sql.TableFunction = (*DiffTableFunction)(nil)
 var _ sql.ExecSourceRel = (*DiffTableFunction)(nil)
 
@@ -52,6 +56,10 @@ type DiffTableFunction struct {
 	tableDelta diff.TableDelta
 	fromDate   *types.Timestamp
 	toDate     *types.Timestamp
+
+	// modifiedColsExpr is the optional --modified-columns argument, if one was given
+	modifiedColsExpr sql.Expression
+	modifiedCols     []string
 }
 
 // NewInstance creates a new instance of TableFunction interface
@@ -83,14 +91,20 @@ func (dtf *DiffTableFunction) WithDatabase(database sql.Database) (sql.Node, err
 
 // Expressions implements the sql.Expressioner interface
 func (dtf *DiffTableFunction) Expressions() []sql.Expression {
+	var exprs []sql.Expression
 	if dtf.dotCommitExpr != nil {
-		return []sql.Expression{
+		exprs = []sql.Expression{
 			dtf.dotCommitExpr, dtf.tableNameExpr,
 		}
+	} else {
+		exprs = []sql.Expression{
+			dtf.fromCommitExpr, dtf.toCommitExpr, dtf.tableNameExpr,
+		}
 	}
-	return []sql.Expression{
-		dtf.fromCommitExpr, dtf.toCommitExpr, dtf.tableNameExpr,
+	if dtf.modifiedColsExpr != nil {
+		exprs = append(exprs, dtf.modifiedColsExpr)
 	}
+	return exprs
 }
 
 // WithExpressions implements the sql.Expressioner interface
@@ -113,6 +127,24 @@ func (dtf *DiffTableFunction) WithExpressions(expression ...sql.Expression) (sql
 	}
 
 	newDtf := *dtf
+	newDtf.modifiedColsExpr = nil
+	positional := make([]sql.Expression, 0, len(expression))
+	for _, expr := range expression {
+		if isModifiedColumnsOption(newDtf.ctx, expr) {
+			if newDtf.modifiedColsExpr != nil {
+				return nil, sql.ErrInvalidArgumentDetails.New(newDtf.Name(), expr.String())
+			}
+			newDtf.modifiedColsExpr = expr
+			continue
+		}
+		positional = append(positional, expr)
+	}
+	expression = positional
+
+	if len(expression) == 0 {
+		return nil, sql.ErrInvalidArgumentNumber.New(newDtf.Name(), "2 to 3", len(expression))
+	}
+
 	if strings.Contains(expression[0].String(), "..") {
 		if len(expression) != 2 {
 			return nil, sql.ErrInvalidArgumentNumber.New(fmt.Sprintf("%v with .. or ...", newDtf.Name()), 2, len(expression))
@@ -138,9 +170,66 @@ func (dtf *DiffTableFunction) WithExpressions(expression ...sql.Expression) (sql
 		return nil, err
 	}
 
+	newDtf.modifiedCols, err = newDtf.evaluateModifiedColumns()
+	if err != nil {
+		return nil, err
+	}
+
 	return &newDtf, nil
 }
 
+// isModifiedColumnsOption returns whether |expr| is a --modified-columns argument
+func isModifiedColumnsOption(ctx *sql.Context, expr sql.Expression) bool {
+	if !gmstypes.IsText(expr.Type()) {
+		return false
+	}
+	val, err := expr.Eval(ctx, nil)
+	if err != nil {
+		return false
+	}
+	s, ok := val.(string)
+	return ok && strings.HasPrefix(s, modifiedColumnsOption)
+}
+
+// evaluateModifiedColumns returns the column names given in the --modified-columns argument, or nil if there wasn't
+// one. Every column named must exist in the table at one of the two revisions being diffed.
+func (dtf *DiffTableFunction) evaluateModifiedColumns() ([]string, error) {
+	if dtf.modifiedColsExpr == nil || !dtf.Resolved() {
+		return nil, nil
+	}
+
+	val, err := dtf.modifiedColsExpr.Eval(dtf.ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var cols []string
+	for _, name := range strings.Split(strings.TrimPrefix(val.(string), modifiedColumnsOption), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		found := false
+		for _, sch := range []schema.Schema{dtf.tableDelta.ToSch, dtf.tableDelta.FromSch} {
+			if sch != nil {
+				if _, ok := sch.GetAllCols().GetByNameCaseInsensitive(name); ok {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return nil, sql.ErrColumnNotFound.New(name)
+		}
+		cols = append(cols, name)
+	}
+
+	if len(cols) == 0 {
+		return nil, sql.ErrInvalidArgumentDetails.New(dtf.Name(), dtf.modifiedColsExpr.String())
+	}
+	return cols, nil
+}
+
 // Children implements the sql.Node interface
 func (dtf *DiffTableFunction) Children() []sql.Node {
 	return nil
@@ -169,6 +258,9 @@ func (dtf *DiffTableFunction) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter,
 
 	ddb := sqledb.DbData().Ddb
 	dp := dtables.NewDiffPartition(dtf.tableDelta.ToTable, dtf.tableDelta.FromTable, toCommitStr, fromCommitStr, dtf.toDate, dtf.fromDate, dtf.tableDelta.ToSch, dtf.tableDelta.FromSch)
+	if len(dtf.modifiedCols) > 0 {
+		*dp = dp.WithModifiedColumns(dtf.modifiedCols)
+	}
 
 	return dtables.NewDiffPartitionRowIter(*dp, ddb, dtf.joiner), nil
 }
@@ -544,15 +636,11 @@ func (dtf *DiffTableFunction) IsReadOnly() bool {
 
 // String implements the Stringer interface
 func (dtf *DiffTableFunction) String() string {
-	if dtf.dotCommitExpr != nil {
-		return fmt.Sprintf("DOLT_DIFF(%s, %s)",
-			dtf.dotCommitExpr.String(),
-			dtf.tableNameExpr.String())
-	}
-	return fmt.Sprintf("DOLT_DIFF(%s, %s, %s)",
-		dtf.fromCommitExpr.String(),
-		dtf.toCommitExpr.String(),
-		dtf.tableNameExpr.String())
+	args := make([]string, 0, 4)
+	for _, expr := range dtf.Expressions() {
+		args = append(args, expr.String())
+	}
+	return fmt.Sprintf("DOLT_DIFF(%s)", strings.Join(args, ", "))
 }
 
 // Name implements the sql.TableFunction interface
@@ -0,0 +1,210 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+var _ sql.TableFunction = (*FsckTableFunction)(nil)
+var _ sql.ExecSourceRel = (*FsckTableFunction)(nil)
+
+// FsckTableFunction is the dolt_fsck table function, which runs Database.Fsck and returns a row for each problem found.
+// It takes the names of the tables to check, and checks every table and the schema fragments when there are none:
+//
+//	select * from dolt_fsck();
+//	select * from dolt_fsck('t1', 't2');
+type FsckTableFunction struct {
+	ctx            *sql.Context
+	tableNameExprs []sql.Expression
+	database       sql.Database
+}
+
+var fsckTableSchema = sql.Schema{
+	&sql.Column{Name: "table_name", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "index_name", Type: types.LongText, Nullable: true},
+	&sql.Column{Name: "problem", Type: types.LongText, Nullable: false},
+}
+
+// fsckDatabase is implemented by the databases that dolt_fsck can check.
+type fsckDatabase interface {
+	Fsck(ctx *sql.Context, opts FsckOpts) (FsckReport, error)
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (ft *FsckTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &FsckTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// Database implements the sql.Databaser interface
+func (ft *FsckTableFunction) Database() sql.Database {
+	return ft.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (ft *FsckTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	nft := *ft
+	nft.database = database
+	return &nft, nil
+}
+
+// Name implements the sql.TableFunction interface
+func (ft *FsckTableFunction) Name() string {
+	return "dolt_fsck"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (ft *FsckTableFunction) Resolved() bool {
+	for _, expr := range ft.tableNameExprs {
+		if !expr.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+func (ft *FsckTableFunction) IsReadOnly() bool {
+	return true
+}
+
+// String implements the Stringer interface
+func (ft *FsckTableFunction) String() string {
+	args := make([]string, len(ft.tableNameExprs))
+	for i, expr := range ft.tableNameExprs {
+		args[i] = expr.String()
+	}
+	return fmt.Sprintf("DOLT_FSCK(%s)", strings.Join(args, ", "))
+}
+
+// Schema implements the sql.Node interface.
+func (ft *FsckTableFunction) Schema() sql.Schema {
+	return fsckTableSchema
+}
+
+// Children implements the sql.Node interface.
+func (ft *FsckTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (ft *FsckTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return ft, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (ft *FsckTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	tblNames, err := ft.evaluateArguments()
+	if err != nil {
+		return false
+	}
+	if len(tblNames) == 0 {
+		tblNames, err = ft.database.GetTableNames(ctx)
+		if err != nil {
+			return false
+		}
+	}
+
+	var operations []sql.PrivilegedOperation
+	for _, tblName := range tblNames {
+		operations = append(operations, sql.NewPrivilegedOperation(ft.database.Name(), tblName, "", sql.PrivilegeType_Select))
+	}
+	return opChecker.UserHasPrivileges(ctx, operations...)
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (ft *FsckTableFunction) Expressions() []sql.Expression {
+	return ft.tableNameExprs
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (ft *FsckTableFunction) WithExpressions(expressions ...sql.Expression) (sql.Node, error) {
+	for _, expr := range expressions {
+		if !expr.Resolved() {
+			return nil, ErrInvalidNonLiteralArgument.New(ft.Name(), expr.String())
+		}
+		// prepared statements resolve functions beforehand, so above check fails
+		if _, ok := expr.(sql.FunctionExpression); ok {
+			return nil, ErrInvalidNonLiteralArgument.New(ft.Name(), expr.String())
+		}
+		if !types.IsText(expr.Type()) {
+			return nil, sql.ErrInvalidArgumentDetails.New(ft.Name(), expr.String())
+		}
+	}
+
+	nft := *ft
+	nft.tableNameExprs = expressions
+	return &nft, nil
+}
+
+// evaluateArguments returns the table names given to the function.
+func (ft *FsckTableFunction) evaluateArguments() ([]string, error) {
+	var tableNames []string
+	for _, expr := range ft.tableNameExprs {
+		val, err := expr.Eval(ft.ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		tableName, ok := val.(string)
+		if !ok {
+			return nil, ErrInvalidTableName.New(expr.String())
+		}
+		tableNames = append(tableNames, tableName)
+	}
+	return tableNames, nil
+}
+
+// RowIter implements the sql.Node interface
+func (ft *FsckTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	tableNames, err := ft.evaluateArguments()
+	if err != nil {
+		return nil, err
+	}
+
+	db, ok := ft.database.(fsckDatabase)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", ft.database)
+	}
+
+	report, err := db.Fsck(ctx, FsckOpts{Tables: tableNames})
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]sql.Row, len(report.Problems))
+	for i, p := range report.Problems {
+		var indexName interface{}
+		if p.Index != "" {
+			indexName = p.Index
+		}
+		rows[i] = sql.Row{p.Table, indexName, p.Err.Error()}
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
@@ -122,7 +122,16 @@ func doDoltCommit(ctx *sql.Context, args []string) (string, bool, error) {
 			}
 			msg = commitMeta.Description
 		} else {
-			return "", false, fmt.Errorf("Must provide commit message.")
+			// when completing a merge, use the message recorded when the merge started
+			ws, err := dSess.WorkingSet(ctx, dbName)
+			if err != nil {
+				return "", false, err
+			}
+			if ws.MergeActive() && ws.MergeState().MergeMessage() != "" {
+				msg = ws.MergeState().MergeMessage()
+			} else {
+				return "", false, fmt.Errorf("Must provide commit message.")
+			}
 		}
 	}
 
@@ -17,6 +17,7 @@ package dprocedures
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -29,6 +30,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
@@ -37,6 +39,14 @@ import (
 
 const DoltMergeWarningCode int = 1105 // Since this our own custom warning we'll use 1105, the code for an unknown error
 
+// autoMergedRowsNoteFormat is the message of the note dolt_merge adds for each table with rows whose changes on both
+// sides of the merge were merged automatically.
+const autoMergedRowsNoteFormat = "%d rows were merged automatically from changes to different columns in table %s"
+
+// rowsRemovedForViolationsNoteFormat is the message of the note dolt_merge adds for each table with rows deleted by
+// --prune-violations.
+const rowsRemovedForViolationsNoteFormat = "%d rows were deleted for constraint violations from table %s"
+
 const (
 	noConflictsOrViolations  int = 0
 	hasConflictsOrViolations int = 1
@@ -48,6 +58,8 @@ const (
 )
 
 var ErrUncommittedChanges = goerrors.NewKind("cannot merge with uncommitted changes")
+var ErrMergeResolveNotDataConflicts = goerrors.NewKind("error: '--resolve' only resolves data conflicts, but the merge has %s in tables: %s")
+var ErrMergeConflictBranchExists = goerrors.NewKind("fatal: A branch named '%s' already exists.")
 
 var doltMergeSchema = []*sql.Column{
 	{
@@ -88,6 +100,7 @@ func doDoltMerge(ctx *sql.Context, args []string) (string, int, int, error) {
 	}
 
 	sess := dsess.DSessFromSess(ctx.Session)
+	sess.SetMergeRowCounts(dbName, nil)
 
 	apr, err := cli.CreateMergeArgParser().Parse(args)
 	if err != nil {
@@ -112,6 +125,9 @@ func doDoltMerge(ctx *sql.Context, args []string) (string, int, int, error) {
 	}
 
 	if apr.Contains(cli.AbortParam) {
+		if apr.Contains(cli.ResolveParam) {
+			return "", noConflictsOrViolations, threeWayMerge, fmt.Errorf("error: Flags '--%s' and '--%s' cannot be used together.\n", cli.AbortParam, cli.ResolveParam)
+		}
 		if !ws.MergeActive() {
 			return "", noConflictsOrViolations, threeWayMerge, fmt.Errorf("fatal: There is no merge to abort")
 		}
@@ -156,6 +172,18 @@ func doDoltMerge(ctx *sql.Context, args []string) (string, int, int, error) {
 	}
 
 	ws, commit, conflicts, fastForward, err := performMerge(ctx, sess, roots, ws, dbName, mergeSpec, apr.Contains(cli.NoCommitFlag), msg)
+	if err == nil && fastForward != 0 && apr.Contains(cli.DateParam) {
+		ctx.Warn(DoltMergeWarningCode, "--date was ignored because the merge was a fast-forward, which doesn't create a commit; use --no-ff to create a merge commit with the date given")
+	}
+	if err == nil && mergeSpec.RetainInputs {
+		// The merge commit has already been written, so the refs that keep its inputs can be too. A merge that's left
+		// uncommitted could still be rolled back, and the refs would outlive it.
+		if commit != "" && conflicts == 0 && fastForward == 0 {
+			err = merge.RetainMergeInputs(ctx, dbData.Ddb, mergeSpec)
+		} else {
+			ctx.Warn(DoltMergeWarningCode, "--no-gc-hint was ignored because the merge didn't create a merge commit")
+		}
+	}
 	if err != nil || conflicts != 0 || fastForward != 0 {
 		return commit, conflicts, fastForward, err
 	}
@@ -194,10 +222,23 @@ func performMerge(ctx *sql.Context, sess *dsess.DoltSession, roots doltdb.Roots,
 		}
 	}
 
+	// An explicit merge base or a partial merge always gets a three-way merge
+	if spec.MergeBaseC != nil || len(spec.OnlyTables) > 0 {
+		canFF = false
+	}
+
 	if canFF {
 		if spec.Noff {
 			var commit *doltdb.Commit
+			preNoFFWs := ws
 			ws, commit, err = executeNoFFMerge(ctx, sess, spec, dbName, ws, dbData, noCommit)
+			if err == doltdb.ErrUnresolvedConflictsOrViolations && spec.ConflictBranch != "" {
+				ws, err = moveMergeToConflictBranch(ctx, sess, dbData.Ddb, dbName, preNoFFWs, ws, spec)
+				if err != nil {
+					return ws, "", noConflictsOrViolations, threeWayMerge, err
+				}
+				return ws, "", hasConflictsOrViolations, threeWayMerge, nil
+			}
 			if err == doltdb.ErrUnresolvedConflictsOrViolations {
 				// if there are unresolved conflicts, write the resulting working set back to the session and return an
 				// error message
@@ -210,6 +251,9 @@ func performMerge(ctx *sql.Context, sess *dsess.DoltSession, roots doltdb.Roots,
 
 				return ws, "", hasConflictsOrViolations, threeWayMerge, nil
 			}
+			if err == nil {
+				recordMerge(dbName, false, nil)
+			}
 			if commit != nil {
 				if h, cerr := commit.HashOf(); cerr == nil {
 					return ws, h.String(), noConflictsOrViolations, threeWayMerge, err
@@ -219,6 +263,9 @@ func performMerge(ctx *sql.Context, sess *dsess.DoltSession, roots doltdb.Roots,
 		}
 
 		ws, err = executeFFMerge(ctx, dbName, spec.Squash, ws, dbData, spec.MergeC, spec)
+		if err == nil {
+			recordMerge(dbName, true, nil)
+		}
 		if h, cerr := spec.MergeC.HashOf(); cerr == nil {
 			return ws, h.String(), noConflictsOrViolations, fastForwardMerge, err
 		}
@@ -232,7 +279,29 @@ func performMerge(ctx *sql.Context, sess *dsess.DoltSession, roots doltdb.Roots,
 		return ws, "", noConflictsOrViolations, threeWayMerge, sql.ErrDatabaseNotFound.New(dbName)
 	}
 
-	ws, err = executeMerge(ctx, sess, dbName, spec.Squash, spec.HeadC, spec.MergeC, spec.MergeCSpecStr, ws, dbState.EditOpts(), spec.WorkingDiffs)
+	// A partial merge is recorded like a squash merge, with only the current commit as its parent. If the commit being
+	// merged were recorded as a parent, the changes to the tables left out would be lost from any later merge of it.
+	squash := spec.Squash || len(spec.OnlyTables) > 0
+
+	preMergeWs := ws
+	ws, err = executeMerge(ctx, sess, dbName, squash, spec.HeadC, spec.MergeC, spec.MergeBaseC, spec.MergeCSpecStr, ws, dbState.EditOpts(), spec.WorkingDiffs, spec.Opts(), msg)
+	if err == doltdb.ErrUnresolvedConflictsOrViolations && spec.ResolveDataConflicts != "" {
+		ws, err = resolveMergeDataConflicts(ctx, sess, dbName, ws, spec)
+		if err != nil {
+			// leave the session as it was before the merge rather than with a partially resolved merge
+			if wsErr := sess.SetWorkingSet(ctx, dbName, preMergeWs); wsErr != nil {
+				return ws, "", noConflictsOrViolations, threeWayMerge, wsErr
+			}
+			return preMergeWs, "", noConflictsOrViolations, threeWayMerge, err
+		}
+	}
+	if err == doltdb.ErrUnresolvedConflictsOrViolations && spec.ConflictBranch != "" {
+		ws, err = moveMergeToConflictBranch(ctx, sess, dbData.Ddb, dbName, preMergeWs, ws, spec)
+		if err != nil {
+			return ws, "", noConflictsOrViolations, threeWayMerge, err
+		}
+		return ws, "", hasConflictsOrViolations, threeWayMerge, nil
+	}
 	if err == doltdb.ErrUnresolvedConflictsOrViolations {
 		// if there are unresolved conflicts, write the resulting working set back to the session and return an
 		// error message
@@ -255,16 +324,124 @@ func performMerge(ctx *sql.Context, sess *dsess.DoltSession, roots doltdb.Roots,
 
 	var commit string
 	if !noCommit {
-		author := fmt.Sprintf("%s <%s>", spec.Name, spec.Email)
-		commit, _, err = doDoltCommit(ctx, []string{"-m", msg, "--author", author})
+		roots, ok := sess.GetRoots(ctx, dbName)
+		if !ok {
+			return ws, "", noConflictsOrViolations, threeWayMerge, sql.ErrDatabaseNotFound.New(dbName)
+		}
+		cm, err := commitMerge(ctx, sess, dbName, roots, spec, msg)
 		if err != nil {
-			return ws, commit, noConflictsOrViolations, threeWayMerge, fmt.Errorf("dolt_commit failed")
+			return ws, "", noConflictsOrViolations, threeWayMerge, fmt.Errorf("dolt_commit failed")
+		}
+		h, err := cm.HashOf()
+		if err != nil {
+			return ws, "", noConflictsOrViolations, threeWayMerge, err
 		}
+		commit = h.String()
 	}
 
 	return ws, commit, noConflictsOrViolations, threeWayMerge, nil
 }
 
+// moveMergeToConflictBranch saves the conflicted merge in |mergedWs| to the working set of the new branch named by
+// |spec.ConflictBranch|, which starts at the current HEAD commit, and puts the session back to |preMergeWs|. The merge
+// can't be saved as a commit, since commits can't hold unresolved conflicts, so it's left in progress on the new branch
+// to be resolved and committed there. Returns the working set of the current branch.
+func moveMergeToConflictBranch(ctx *sql.Context, sess *dsess.DoltSession, ddb *doltdb.DoltDB, dbName string, preMergeWs, mergedWs *doltdb.WorkingSet, spec *merge.MergeSpec) (*doltdb.WorkingSet, error) {
+	branchRef := ref.NewBranchRef(spec.ConflictBranch)
+	err := ddb.NewBranchAtCommit(ctx, branchRef, spec.HeadC, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = branch_control.AddAdminForContext(ctx, spec.ConflictBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	wsRef, err := ref.WorkingSetRefForHead(branchRef)
+	if err != nil {
+		return nil, err
+	}
+	branchWs, err := ddb.ResolveWorkingSet(ctx, wsRef)
+	if err != nil {
+		return nil, err
+	}
+	prevHash, err := branchWs.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	branchWs = branchWs.WithWorkingRoot(mergedWs.WorkingRoot()).WithStagedRoot(mergedWs.StagedRoot()).WithMergeState(mergedWs.MergeState())
+	err = ddb.UpdateWorkingSet(ctx, wsRef, branchWs, prevHash, doltdb.TodoWorkingSetMeta(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sess.SetWorkingSet(ctx, dbName, preMergeWs)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.Warn(DoltMergeWarningCode, fmt.Sprintf("merge has unresolved conflicts or constraint violations, which were saved to branch '%s'", spec.ConflictBranch))
+	return preMergeWs, nil
+}
+
+// resolveMergeDataConflicts resolves the data conflicts left in |ws| by a three-way merge, taking the side of the
+// merge given by |spec.ResolveDataConflicts|, and stages the result. Returns ErrMergeResolveNotDataConflicts if the
+// merge has schema conflicts or constraint violations, which can't be resolved this way.
+func resolveMergeDataConflicts(ctx *sql.Context, sess *dsess.DoltSession, dbName string, ws *doltdb.WorkingSet, spec *merge.MergeSpec) (*doltdb.WorkingSet, error) {
+	if ws.MergeActive() && ws.MergeState().HasSchemaConflicts() {
+		return nil, ErrMergeResolveNotDataConflicts.New("schema conflicts", strings.Join(ws.MergeState().TablesWithSchemaConflicts(), ", "))
+	}
+
+	working := ws.WorkingRoot()
+	violations, err := working.TablesWithConstraintViolations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(violations) > 0 {
+		return nil, ErrMergeResolveNotDataConflicts.New("constraint violations", strings.Join(violations, ", "))
+	}
+
+	conflicts, err := working.TablesWithDataConflicts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = ResolveDataConflicts(ctx, sess, working, dbName, spec.ResolveDataConflicts == cli.OursFlag, conflicts)
+	if err != nil {
+		return nil, err
+	}
+
+	ws, err = sess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stage the merge result, leaving out the uncommitted changes to tables the merge didn't touch, as a merge without
+	// conflicts would
+	staged := ws.WorkingRoot()
+	if len(spec.WorkingDiffs) > 0 {
+		headRoot, err := spec.HeadC.GetRootValue(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for tblName := range spec.WorkingDiffs {
+			tbl, ok, err := headRoot.GetTable(ctx, tblName)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				staged, err = staged.PutTable(ctx, tblName, tbl)
+			} else {
+				staged, err = staged.RemoveTables(ctx, true, true, tblName)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return ws.WithStagedRoot(staged), nil
+}
+
 func abortMerge(ctx *sql.Context, workingSet *doltdb.WorkingSet, roots doltdb.Roots) (*doltdb.WorkingSet, error) {
 	tbls, err := doltdb.UnionTableNames(ctx, roots.Working, roots.Staged, roots.Head)
 	if err != nil {
@@ -302,8 +479,17 @@ func abortMerge(ctx *sql.Context, workingSet *doltdb.WorkingSet, roots doltdb.Ro
 	return workingSet, nil
 }
 
-func executeMerge(ctx *sql.Context, sess *dsess.DoltSession, dbName string, squash bool, head, cm *doltdb.Commit, cmSpec string, ws *doltdb.WorkingSet, opts editor.Options, workingDiffs map[string]hash.Hash) (*doltdb.WorkingSet, error) {
-	result, err := merge.MergeCommits(ctx, head, cm, opts)
+// executeMerge performs a three-way merge of |head| and |cm|. If |base| is nil, their common ancestor is used as the
+// merge base. |msg| is recorded in the merge state as the message for the commit that completes the merge.
+func executeMerge(ctx *sql.Context, sess *dsess.DoltSession, dbName string, squash bool, head, cm, base *doltdb.Commit, cmSpec string, ws *doltdb.WorkingSet, opts editor.Options, workingDiffs map[string]hash.Hash, mo merge.MergeOpts, msg string) (*doltdb.WorkingSet, error) {
+	var err error
+	if base == nil {
+		base, err = doltdb.GetCommitAncestor(ctx, head, cm)
+	}
+	var result *merge.Result
+	if err == nil {
+		result, err = merge.MergeCommitsWithOpts(ctx, head, cm, base, opts, mo)
+	}
 	if err != nil {
 		switch err {
 		case doltdb.ErrUpToDate:
@@ -314,7 +500,62 @@ func executeMerge(ctx *sql.Context, sess *dsess.DoltSession, dbName string, squa
 			return nil, err
 		}
 	}
-	return mergeRootToWorking(ctx, sess, dbName, squash, ws, result, workingDiffs, cm, cmSpec)
+	recordMerge(dbName, false, result.Stats)
+	noteAutoMergedRows(ctx, result.Stats)
+	sess.SetMergeRowCounts(dbName, mergeRowCounts(result.Stats))
+	noteRowsRemovedForViolations(ctx, result.Stats)
+	return mergeRootToWorking(ctx, sess, dbName, squash, ws, result, workingDiffs, cm, cmSpec, msg)
+}
+
+// noteAutoMergedRows adds a note to the session for each table in |stats| with rows that were merged automatically.
+func noteAutoMergedRows(ctx *sql.Context, stats map[string]*merge.MergeStats) {
+	tables := make([]string, 0, len(stats))
+	for tblName, s := range stats {
+		if s.AutoMergedRows > 0 {
+			tables = append(tables, tblName)
+		}
+	}
+	sort.Strings(tables)
+
+	for _, tblName := range tables {
+		ctx.Session.Warn(&sql.Warning{
+			Level:   "Note",
+			Code:    DoltMergeWarningCode,
+			Message: fmt.Sprintf(autoMergedRowsNoteFormat, stats[tblName].AutoMergedRows, tblName),
+		})
+	}
+}
+
+// mergeRowCounts returns the row counts of the tables in |stats| for the dolt_merge_stats system table, leaving out
+// tables without any rows to count.
+func mergeRowCounts(stats map[string]*merge.MergeStats) map[string]dsess.MergeRowCounts {
+	counts := make(map[string]dsess.MergeRowCounts)
+	for tblName, s := range stats {
+		if s.AutoMergedRows > 0 || s.RowsRemovedForViolations > 0 {
+			counts[tblName] = dsess.MergeRowCounts{AutoMerged: s.AutoMergedRows, RemovedForViolations: s.RowsRemovedForViolations}
+		}
+	}
+	return counts
+}
+
+// noteRowsRemovedForViolations adds a note to the session for each table in |stats| with rows that were deleted
+// because they violated constraints the merge was asked to prune.
+func noteRowsRemovedForViolations(ctx *sql.Context, stats map[string]*merge.MergeStats) {
+	tables := make([]string, 0, len(stats))
+	for tblName, s := range stats {
+		if s.RowsRemovedForViolations > 0 {
+			tables = append(tables, tblName)
+		}
+	}
+	sort.Strings(tables)
+
+	for _, tblName := range tables {
+		ctx.Session.Warn(&sql.Warning{
+			Level:   "Note",
+			Code:    DoltMergeWarningCode,
+			Message: fmt.Sprintf(rowsRemovedForViolationsNoteFormat, stats[tblName].RowsRemovedForViolations, tblName),
+		})
+	}
 }
 
 func executeFFMerge(ctx *sql.Context, dbName string, squash bool, ws *doltdb.WorkingSet, dbData env.DbData, cm2 *doltdb.Commit, spec *merge.MergeSpec) (*doltdb.WorkingSet, error) {
@@ -380,7 +621,7 @@ func executeNoFFMerge(
 	}
 	result := &merge.Result{Root: mergeRoot, Stats: make(map[string]*merge.MergeStats)}
 
-	ws, err = mergeRootToWorking(ctx, dSess, dbName, false, ws, result, spec.WorkingDiffs, spec.MergeC, spec.MergeCSpecStr)
+	ws, err = mergeRootToWorking(ctx, dSess, dbName, false, ws, result, spec.WorkingDiffs, spec.MergeC, spec.MergeCSpecStr, spec.Msg)
 	if err != nil {
 		// This error is recoverable, so we return a working set value along with the error
 		return ws, nil, err
@@ -411,8 +652,20 @@ func executeNoFFMerge(
 		return ws.WithStagedRoot(roots.Staged), nil, nil
 	}
 
+	commit, err := commitMerge(ctx, dSess, dbName, roots, spec, spec.Msg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ws, commit, nil
+}
+
+// commitMerge commits the staged changes of a merge with the message given and the author and date of |spec|. Every
+// kind of merge commit is created here, so that they all get the identity resolved once by createMergeSpec, either
+// from --author or from the SQL user.
+func commitMerge(ctx *sql.Context, dSess *dsess.DoltSession, dbName string, roots doltdb.Roots, spec *merge.MergeSpec, msg string) (*doltdb.Commit, error) {
 	pendingCommit, err := dSess.NewPendingCommit(ctx, dbName, roots, actions.CommitStagedProps{
-		Message:    spec.Msg,
+		Message:    msg,
 		Date:       spec.Date,
 		AllowEmpty: spec.AllowEmpty,
 		Force:      spec.Force,
@@ -420,19 +673,14 @@ func executeNoFFMerge(
 		Email:      spec.Email,
 	})
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	if pendingCommit == nil {
-		return nil, nil, errors.New("nothing to commit")
-	}
-
-	commit, err := dSess.DoltCommit(ctx, dbName, dSess.GetTransaction(), pendingCommit)
-	if err != nil {
-		return nil, nil, err
+		return nil, errors.New("nothing to commit")
 	}
 
-	return ws, commit, nil
+	return dSess.DoltCommit(ctx, dbName, dSess.GetTransaction(), pendingCommit)
 }
 
 func createMergeSpec(ctx *sql.Context, sess *dsess.DoltSession, dbName string, apr *argparser.ArgParseResults, commitSpecStr string) (*merge.MergeSpec, error) {
@@ -474,7 +722,102 @@ func createMergeSpec(ctx *sql.Context, sess *dsess.DoltSession, dbName string, a
 	if apr.Contains(cli.NoCommitFlag) && apr.Contains(cli.CommitFlag) {
 		return nil, errors.New("cannot define both 'commit' and 'no-commit' flags at the same time")
 	}
-	return merge.NewMergeSpec(ctx, dbData.Rsr, ddb, roots, name, email, msg, commitSpecStr, apr.Contains(cli.SquashParam), apr.Contains(cli.NoFFParam), apr.Contains(cli.ForceFlag), apr.Contains(cli.NoCommitFlag), apr.Contains(cli.NoEditFlag), t)
+	spec, err := merge.NewMergeSpec(ctx, dbData.Rsr, ddb, roots, name, email, msg, commitSpecStr, apr.Contains(cli.SquashParam), apr.Contains(cli.NoFFParam), apr.Contains(cli.ForceFlag), apr.Contains(cli.NoCommitFlag), apr.Contains(cli.NoEditFlag), t)
+	if err != nil {
+		return nil, err
+	}
+
+	if baseSpecStr, ok := apr.GetValue(cli.MergeBaseParam); ok {
+		headRef, err := dbData.Rsr.CWBHeadRef()
+		if err != nil {
+			return nil, err
+		}
+		err = spec.SetMergeBase(ctx, ddb, headRef, baseSpecStr)
+		if err != nil {
+			return nil, err
+		}
+		err = spec.ValidateMergeBase(ctx)
+		if errors.Is(err, merge.ErrMergeBaseNotAncestor) && apr.Contains(cli.ForceMergeBase) {
+			ctx.Warn(DoltMergeWarningCode, err.Error())
+		} else if err != nil {
+			return nil, err
+		}
+	} else if apr.Contains(cli.ForceMergeBase) {
+		return nil, fmt.Errorf("error: Flag '--%s' requires '--%s'", cli.ForceMergeBase, cli.MergeBaseParam)
+	}
+
+	if tablesStr, ok := apr.GetValue(cli.OnlyParam); ok {
+		for _, tableName := range strings.Split(tablesStr, ",") {
+			if tableName = strings.TrimSpace(tableName); tableName != "" {
+				spec.OnlyTables = append(spec.OnlyTables, tableName)
+			}
+		}
+		if len(spec.OnlyTables) == 0 {
+			return nil, fmt.Errorf("error: Flag '--%s' requires at least one table name", cli.OnlyParam)
+		}
+	}
+
+	if columnsStr, ok := apr.GetValue(cli.TextMergeParam); ok {
+		for _, column := range strings.Split(columnsStr, ",") {
+			if column = strings.TrimSpace(column); column == "" {
+				continue
+			}
+			if i := strings.LastIndex(column, "."); i <= 0 || i == len(column)-1 {
+				return nil, fmt.Errorf("error: invalid column '%s' for '--%s', expected 'table.column'", column, cli.TextMergeParam)
+			}
+			spec.TextMergeColumns = append(spec.TextMergeColumns, column)
+		}
+		if len(spec.TextMergeColumns) == 0 {
+			return nil, fmt.Errorf("error: Flag '--%s' requires at least one column name", cli.TextMergeParam)
+		}
+	}
+
+	// A three-way merge commit is always allowed to be empty, so only --no-ff merges need the flag
+	if apr.Contains(cli.AllowEmptyFlag) {
+		if !apr.Contains(cli.NoFFParam) {
+			return nil, fmt.Errorf("error: Flag '--%s' requires '--%s'", cli.AllowEmptyFlag, cli.NoFFParam)
+		}
+		spec.AllowEmpty = true
+	}
+
+	if side, ok := apr.GetValue(cli.ResolveParam); ok {
+		if side != cli.OursFlag && side != cli.TheirsFlag {
+			return nil, fmt.Errorf("error: invalid value '%s' for '--%s', expected '%s' or '%s'", side, cli.ResolveParam, cli.OursFlag, cli.TheirsFlag)
+		}
+		spec.ResolveDataConflicts = side
+	}
+
+	spec.RetainInputs = apr.Contains(cli.NoGCHintFlag)
+
+	if branchName, ok := apr.GetValue(cli.ConflictBranchParam); ok {
+		if apr.Contains(cli.ResolveParam) {
+			return nil, fmt.Errorf("error: Flags '--%s' and '--%s' cannot be used together.\n", cli.ConflictBranchParam, cli.ResolveParam)
+		}
+		if !doltdb.IsValidUserBranchName(branchName) {
+			return nil, doltdb.ErrInvBranchName
+		}
+		if err := branch_control.CanCreateBranch(ctx, branchName); err != nil {
+			return nil, err
+		}
+		if _, exists, err := ddb.HasBranch(ctx, branchName); err != nil {
+			return nil, err
+		} else if exists {
+			return nil, ErrMergeConflictBranchExists.New(branchName)
+		}
+		spec.ConflictBranch = branchName
+	}
+
+	if typesStr, ok := apr.GetValue(cli.PruneViolations); ok {
+		for _, typeStr := range strings.Split(typesStr, ",") {
+			cvType, err := merge.ParseCvType(typeStr)
+			if err != nil {
+				return nil, err
+			}
+			spec.PruneViolations = append(spec.PruneViolations, cvType)
+		}
+	}
+
+	return spec, nil
 }
 
 func mergeRootToWorking(
@@ -487,6 +830,7 @@ func mergeRootToWorking(
 	workingDiffs map[string]hash.Hash,
 	cm2 *doltdb.Commit,
 	cm2Spec string,
+	msg string,
 ) (*doltdb.WorkingSet, error) {
 	var err error
 	staged, working := merged.Root, merged.Root
@@ -498,7 +842,7 @@ func mergeRootToWorking(
 	}
 
 	if !squash || merged.HasSchemaConflicts() {
-		ws = ws.StartMerge(cm2, cm2Spec)
+		ws = ws.StartMerge(cm2, cm2Spec).WithMergeMessage(msg)
 		tt := merge.SchemaConflictTableNames(merged.SchemaConflicts)
 		ws = ws.WithUnmergableTables(tt)
 	}
@@ -0,0 +1,57 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"sync"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+)
+
+// MergeMetrics is notified of the merges performed by the DOLT_MERGE and DOLT_PULL procedures, so that a server can
+// export counts of them as metrics.
+type MergeMetrics interface {
+	// MergeCompleted is called after each merge into the database named. |fastForward| is true for fast-forward
+	// merges. |conflicts| is the number of data and schema conflicts found by the merge, and |constraintViolations|
+	// is the number of constraint violations.
+	MergeCompleted(dbName string, fastForward bool, conflicts, constraintViolations int)
+}
+
+var mergeMetricsMu sync.RWMutex
+var mergeMetrics MergeMetrics
+
+// SetMergeMetrics sets the MergeMetrics notified of every merge in this process. Pass nil to stop notifications.
+func SetMergeMetrics(m MergeMetrics) {
+	mergeMetricsMu.Lock()
+	defer mergeMetricsMu.Unlock()
+	mergeMetrics = m
+}
+
+// recordMerge notifies the MergeMetrics set with SetMergeMetrics, if any, of a merge into |dbName|. |stats| are the
+// merge's per-table stats, which are empty for fast-forward merges.
+func recordMerge(dbName string, fastForward bool, stats map[string]*merge.MergeStats) {
+	mergeMetricsMu.RLock()
+	defer mergeMetricsMu.RUnlock()
+	if mergeMetrics == nil {
+		return
+	}
+
+	var conflicts, violations int
+	for _, s := range stats {
+		conflicts += s.DataConflicts + s.SchemaConflicts
+		violations += s.ConstraintViolations
+	}
+	mergeMetrics.MergeCompleted(dbName, fastForward, conflicts, violations)
+}
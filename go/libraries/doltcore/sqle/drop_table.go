@@ -0,0 +1,203 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+var ErrDropTableHasDependents = errors.NewKind("cannot drop table %s: it is referenced by %s")
+
+// DropTableDependentsWarningCode is the warning code used when a dropped table is referenced by views or triggers, and
+// dolt_drop_table_dependents is set to warn. 1105 is ER_UNKNOWN_ERROR.
+const DropTableDependentsWarningCode int = 1105
+
+// checkDropTableDependents looks for views, triggers and events that reference the table named, which is about to be
+// dropped, and warns about them or returns an error as configured by the dolt_drop_table_dependents system variable.
+func (db Database) checkDropTableDependents(ctx *sql.Context, tableName string) error {
+	mode, err := dsess.GetDropTableDependents(ctx)
+	if err != nil {
+		return err
+	}
+	if mode == dsess.DropTableDependentsIgnore {
+		return nil
+	}
+
+	ds := dsess.DSessFromSess(ctx.Session)
+	if _, ok := ds.GetTemporaryTable(ctx, db.Name(), tableName); ok {
+		return nil
+	}
+
+	deps, err := db.FindDependentSchemaObjects(ctx, tableName)
+	if err != nil || len(deps) == 0 {
+		return err
+	}
+
+	descs := make([]string, len(deps))
+	for i, dep := range deps {
+		descs[i] = dep.Type + " " + dep.Name
+	}
+	depList := strings.Join(descs, ", ")
+
+	if mode == dsess.DropTableDependentsError {
+		return ErrDropTableHasDependents.New(tableName, depList)
+	}
+	ctx.Warn(DropTableDependentsWarningCode, fmt.Sprintf("table %s is referenced by %s, which will fail when used", tableName, depList))
+	return nil
+}
+
+// DropTableImpact describes what dropping a table would affect, as reported by Database.WouldDropTable.
+type DropTableImpact struct {
+	// TableName is the name of the table, with the case it's stored with.
+	TableName string
+	// DeclaredForeignKeys are the foreign keys declared on the table, which are dropped along with it.
+	DeclaredForeignKeys []doltdb.ForeignKey
+	// ReferencingForeignKeys are the foreign keys of other tables that reference the table. The table can't be dropped
+	// while they exist unless foreign_key_checks is disabled, in which case they're left unresolved.
+	ReferencingForeignKeys []doltdb.ForeignKey
+	// DependentFragments are the views, triggers and events that reference the table, and which won't work once it's
+	// dropped.
+	DependentFragments []FragSpec
+	// HasAutoIncrement is true if the table has an auto increment column, whose sequence has to be reconciled across
+	// branches when the table is dropped.
+	HasAutoIncrement bool
+	// AutoIncrementBranches are the other branches whose working sets keep the auto increment sequence going if the
+	// table is created again. If there are none, the sequence starts over at 1.
+	AutoIncrementBranches []string
+}
+
+// WouldDropTable reports what dropping the table named would affect, without changing anything.
+func (db Database) WouldDropTable(ctx *sql.Context, tableName string) (DropTableImpact, error) {
+	if doltdb.IsNonAlterableSystemTable(tableName) {
+		return DropTableImpact{}, ErrSystemTableAlter.New(tableName)
+	}
+
+	ds := dsess.DSessFromSess(ctx.Session)
+	if _, ok := ds.GetTemporaryTable(ctx, db.Name(), tableName); ok {
+		return DropTableImpact{TableName: tableName}, nil
+	}
+
+	ws, err := db.GetWorkingSet(ctx)
+	if err != nil {
+		return DropTableImpact{}, err
+	}
+
+	root := ws.WorkingRoot()
+	tbl, tableName, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return DropTableImpact{}, err
+	} else if !ok {
+		return DropTableImpact{}, sql.ErrTableNotFound.New(tableName)
+	}
+	impact := DropTableImpact{TableName: tableName}
+
+	fkc, err := root.GetForeignKeyCollection(ctx)
+	if err != nil {
+		return DropTableImpact{}, err
+	}
+	declared, referencing := fkc.KeysForTable(tableName)
+	impact.DeclaredForeignKeys = declared
+	for _, fk := range referencing {
+		if !fk.IsSelfReferential() {
+			impact.ReferencingForeignKeys = append(impact.ReferencingForeignKeys, fk)
+		}
+	}
+
+	impact.DependentFragments, err = db.FindDependentSchemaObjects(ctx, tableName)
+	if err != nil {
+		return DropTableImpact{}, err
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return DropTableImpact{}, err
+	}
+	if !schema.HasAutoIncrement(sch) {
+		return impact, nil
+	}
+	impact.HasAutoIncrement = true
+
+	// the same working sets the auto increment tracker reconciles the sequence with when the table is dropped
+	wses, err := otherBranchWorkingSets(ctx, db.ddb, ws.Ref())
+	if err != nil {
+		return DropTableImpact{}, err
+	}
+	for _, otherWs := range wses {
+		otherTbl, _, ok, err := otherWs.WorkingRoot().GetTableInsensitive(ctx, tableName)
+		if err != nil {
+			return DropTableImpact{}, err
+		} else if !ok {
+			continue
+		}
+		otherSch, err := otherTbl.GetSchema(ctx)
+		if err != nil {
+			return DropTableImpact{}, err
+		}
+		if schema.HasAutoIncrement(otherSch) {
+			branch, err := otherWs.Ref().ToHeadRef()
+			if err != nil {
+				return DropTableImpact{}, err
+			}
+			impact.AutoIncrementBranches = append(impact.AutoIncrementBranches, branch.GetPath())
+		}
+	}
+
+	return impact, nil
+}
+
+// FindDependentSchemaObjects returns the views, triggers and events in the working set that reference the table or
+// view named. Fragments that can't be parsed are skipped.
+func (db Database) FindDependentSchemaObjects(ctx *sql.Context, tableName string) ([]FragSpec, error) {
+	tbl, ok, err := db.GetTableInsensitive(ctx, doltdb.SchemasTableName)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var specs []FragSpec
+	for _, fragType := range []string{viewFragment, triggerFragment, eventFragment} {
+		frags, err := getSchemaFragmentsOfType(ctx, tbl.(*WritableDoltTable), fragType)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, frag := range frags {
+			stmt, err := sqlparser.ParseWithOptions(frag.fragment, sql.NewSqlModeFromString(frag.sqlMode).ParserOptions())
+			if err != nil {
+				continue
+			}
+			if ddl, ok := stmt.(*sqlparser.DDL); ok && ddl.TriggerSpec != nil && strings.EqualFold(ddl.Table.Name.String(), tableName) {
+				// triggers defined on the table are dropped along with it
+				continue
+			}
+			for _, name := range referencedTables(stmt, db.Name()) {
+				if strings.EqualFold(name, tableName) {
+					specs = append(specs, FragSpec{Type: fragType, Name: frag.name})
+					break
+				}
+			}
+		}
+	}
+
+	return specs, nil
+}
@@ -24,6 +24,18 @@ import (
 // This has to live here, rather than in the branch_control package, to prevent a dependency cycle with that package.
 // We could also avoid this by defining branchController as an interface used by dsess.
 func CheckAccessForDb(ctx context.Context, db SqlDatabase, flags branch_control.Permissions) error {
+	if db.RevisionType() != RevisionTypeBranch {
+		// not a branch db, no check necessary
+		return nil
+	}
+
+	dbName, branch := SplitRevisionDbName(db.RevisionQualifiedName())
+	return CheckAccessForBranch(ctx, dbName, branch, flags)
+}
+
+// CheckAccessForBranch checks whether the current user has the given permissions for the branch named in the database
+// named, which need not be the branch the session has checked out.
+func CheckAccessForBranch(ctx context.Context, dbName, branch string, flags branch_control.Permissions) error {
 	branchAwareSession := branch_control.GetBranchAwareSession(ctx)
 	// A nil session means we're not in the SQL context, so we allow all operations
 	if branchAwareSession == nil {
@@ -42,13 +54,6 @@ func CheckAccessForDb(ctx context.Context, db SqlDatabase, flags branch_control.
 	user := branchAwareSession.GetUser()
 	host := branchAwareSession.GetHost()
 
-	if db.RevisionType() != RevisionTypeBranch {
-		// not a branch db, no check necessary
-		return nil
-	}
-
-	dbName, branch := SplitRevisionDbName(db.RevisionQualifiedName())
-
 	// Get the permissions for the branch, user, and host combination
 	_, perms := controller.Access.Match(dbName, branch, user, host)
 	// If either the flags match or the user is an admin for this branch, then we allow access
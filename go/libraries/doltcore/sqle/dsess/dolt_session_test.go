@@ -299,6 +299,10 @@ func (e emptyRevisionDatabaseProvider) CreateDatabase(ctx *sql.Context, dbName s
 	return nil
 }
 
+func (e emptyRevisionDatabaseProvider) RenameDatabase(ctx *sql.Context, oldName, newName string) error {
+	return nil
+}
+
 func (e emptyRevisionDatabaseProvider) RevisionDbState(_ *sql.Context, revDB string) (InitialDbState, error) {
 	return InitialDbState{}, sql.ErrDatabaseNotFound.New(revDB)
 }
@@ -77,14 +77,16 @@ func NewGlobalStateStoreForDb(ctx context.Context, dbName string, db *doltdb.Dol
 	}
 
 	return GlobalStateImpl{
-		aiTracker: tracker,
-		mu:        &sync.Mutex{},
+		aiTracker:  tracker,
+		mu:         &sync.Mutex{},
+		schemaLock: &SchemaLock{sem: make(chan struct{}, 1)},
 	}, nil
 }
 
 type GlobalStateImpl struct {
-	aiTracker AutoIncrementTracker
-	mu        *sync.Mutex
+	aiTracker  AutoIncrementTracker
+	mu         *sync.Mutex
+	schemaLock *SchemaLock
 }
 
 var _ globalstate.GlobalState = GlobalStateImpl{}
@@ -92,3 +94,52 @@ var _ globalstate.GlobalState = GlobalStateImpl{}
 func (g GlobalStateImpl) AutoIncrementTracker(ctx *sql.Context) (globalstate.AutoIncrementTracker, error) {
 	return g.aiTracker, nil
 }
+
+// SchemaLock returns the lock that serializes schema changes to this database.
+func (g GlobalStateImpl) SchemaLock() *SchemaLock {
+	return g.schemaLock
+}
+
+// SchemaLock serializes the schema changes made to a database by different sessions. It's held by one session at a
+// time, and the session holding it may lock it again, as long as each Lock is matched by an Unlock.
+type SchemaLock struct {
+	// sem is a channel with a buffer of one, so that waiting for the lock can be abandoned when a context is done
+	sem   chan struct{}
+	mu    sync.Mutex
+	owner sql.Session
+	holds int
+}
+
+// Lock blocks until the session of |ctx| holds the lock, or until |ctx| is done, in which case the context's error is
+// returned.
+func (l *SchemaLock) Lock(ctx *sql.Context) error {
+	l.mu.Lock()
+	if l.holds > 0 && l.owner == ctx.Session {
+		l.holds++
+		l.mu.Unlock()
+		return nil
+	}
+	l.mu.Unlock()
+
+	select {
+	case l.sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.owner, l.holds = ctx.Session, 1
+	return nil
+}
+
+// Unlock releases one hold of the lock by the session holding it.
+func (l *SchemaLock) Unlock() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.holds--
+	if l.holds == 0 {
+		l.owner = nil
+		<-l.sem
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -60,6 +61,13 @@ type DoltSession struct {
 	branchController *branch_control.Controller
 	mu               *sync.Mutex
 	fs               filesys.Filesys
+	// mergeRowCounts holds the row counts of the last call to dolt_merge for each database, by table name
+	mergeRowCounts map[string]map[string]MergeRowCounts
+
+	// tableCacheSize and tableCacheDisabled are read from the dolt_table_cache_size and dolt_disable_table_cache
+	// system variables when each transaction starts
+	tableCacheSize     int
+	tableCacheDisabled bool
 
 	// If non-nil, this will be returned from ValidateSession.
 	// Used by sqle/cluster to put a session into a terminal err state.
@@ -85,6 +93,7 @@ func DefaultSession(pro DoltDatabaseProvider) *DoltSession {
 		branchController: branch_control.CreateDefaultController(), // Default sessions are fine with the default controller
 		mu:               &sync.Mutex{},
 		fs:               pro.FileSystem(),
+		mergeRowCounts:   make(map[string]map[string]MergeRowCounts),
 	}
 }
 
@@ -111,6 +120,7 @@ func NewDoltSession(
 		branchController: branchController,
 		mu:               &sync.Mutex{},
 		fs:               pro.FileSystem(),
+		mergeRowCounts:   make(map[string]map[string]MergeRowCounts),
 	}
 
 	return sess, nil
@@ -217,11 +227,76 @@ func (d *DoltSession) LookupDbState(ctx *sql.Context, dbName string) (SessionSta
 	return s, ok, nil
 }
 
+// ActiveRevision describes a revision of a database that a session has loaded state for.
+type ActiveRevision struct {
+	// BaseName is the name of the database, without a revision qualifier
+	BaseName string
+	// Revision is the branch, tag or commit hash the state is for
+	Revision string
+	// RevisionType is the type of Revision
+	RevisionType RevisionType
+}
+
+// ActiveRevisions returns every revision of every database that this session has loaded state for in the current
+// transaction, ordered by database name and then revision. A revision is loaded the first time it's referred to in a
+// transaction, either by its revision-qualified name, e.g. `mydb/branch1`, or by the base name of a database, which
+// refers to the revision last checked out.
+func (d *DoltSession) ActiveRevisions() []ActiveRevision {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var revisions []ActiveRevision
+	for baseName, dbState := range d.dbStates {
+		for _, bs := range dbState.heads {
+			revisions = append(revisions, ActiveRevision{
+				BaseName:     baseName,
+				Revision:     bs.head,
+				RevisionType: bs.revisionType,
+			})
+		}
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		if revisions[i].BaseName != revisions[j].BaseName {
+			return revisions[i].BaseName < revisions[j].BaseName
+		}
+		return revisions[i].Revision < revisions[j].Revision
+	})
+	return revisions
+}
+
+// MergeRowCounts are the number of rows of a table that the last call to dolt_merge merged automatically or deleted.
+type MergeRowCounts struct {
+	// AutoMerged is the number of rows whose changes on both sides of the merge were merged automatically
+	AutoMerged int
+	// RemovedForViolations is the number of rows deleted by --prune-violations
+	RemovedForViolations int
+}
+
+// SetMergeRowCounts records the row counts of a call to dolt_merge on the database named, by table name, replacing
+// those of the previous call. They're shown by the dolt_merge_stats system table.
+func (d *DoltSession) SetMergeRowCounts(dbName string, counts map[string]MergeRowCounts) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	baseName, _ := SplitRevisionDbName(dbName)
+	d.mergeRowCounts[strings.ToLower(baseName)] = counts
+}
+
+// MergeRowCounts returns the row counts of the last call to dolt_merge on the database named in this session, by
+// table name.
+func (d *DoltSession) MergeRowCounts(dbName string) map[string]MergeRowCounts {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	baseName, _ := SplitRevisionDbName(dbName)
+	return d.mergeRowCounts[strings.ToLower(baseName)]
+}
+
 // RemoveDbState invalidates any cached db state in this session, for example, if a database is dropped.
 func (d *DoltSession) RemoveDbState(_ *sql.Context, dbName string) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 	delete(d.dbStates, strings.ToLower(dbName))
+	delete(d.mergeRowCounts, strings.ToLower(dbName))
 	// also clear out any db-level caches for this db
 	d.dbCache.Clear()
 	return nil
@@ -319,6 +394,10 @@ func (d *DoltSession) StartTransaction(ctx *sql.Context, tCharacteristic sql.Tra
 	// New transaction, clear all session state
 	d.clear()
 
+	if err := d.loadTableCacheSettings(ctx); err != nil {
+		return nil, err
+	}
+
 	// Take a snapshot of the current noms root for every database under management
 	doltDatabases := d.provider.DoltDatabases()
 	txDbs := make([]SqlDatabase, 0, len(doltDatabases))
@@ -379,6 +458,43 @@ func (d *DoltSession) StartTransaction(ctx *sql.Context, tCharacteristic sql.Tra
 	return tx, nil
 }
 
+// loadTableCacheSettings reads the table cache system variables for the transaction being started, and drops any
+// tables already cached if the cache has been turned off.
+func (d *DoltSession) loadTableCacheSettings(ctx *sql.Context) error {
+	size, err := GetTableCacheSize(ctx)
+	if err != nil {
+		return err
+	}
+	disabled, err := GetBooleanSystemVar(ctx, DisableTableCache)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.tableCacheSize, d.tableCacheDisabled = size, disabled
+	if disabled {
+		for _, dbState := range d.dbStates {
+			for _, cache := range dbState.headCache {
+				cache.ClearTableCache()
+			}
+		}
+	}
+
+	return nil
+}
+
+// TableCacheSize returns the maximum number of tables to cache for each branch this session has loaded, as set when
+// the current transaction started. 0 means there is no limit. The second return value is false if the table cache is
+// turned off, in which case tables shouldn't be cached at all.
+func (d *DoltSession) TableCacheSize() (int, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.tableCacheSize, !d.tableCacheDisabled
+}
+
 // clear clears all DB state for this session
 func (d *DoltSession) clear() {
 	d.mu.Lock()
@@ -708,7 +824,8 @@ func (d *DoltSession) Rollback(ctx *sql.Context, tx sql.Transaction) error {
 }
 
 // CreateSavepoint creates a new savepoint for this transaction with the name given. A previously created savepoint
-// with the same name will be overwritten.
+// with the same name will be overwritten. The savepoint records the working root of every branch the session has
+// loaded, including revision databases such as `mydb/branch`, and the contents of the session's temporary tables.
 func (d *DoltSession) CreateSavepoint(ctx *sql.Context, tx sql.Transaction, savepointName string) error {
 	if TransactionsDisabled(ctx) {
 		return nil
@@ -719,20 +836,46 @@ func (d *DoltSession) CreateSavepoint(ctx *sql.Context, tx sql.Transaction, save
 		return fmt.Errorf("expected a DoltTransaction")
 	}
 
-	roots := make(map[string]*doltdb.RootValue)
+	// Make sure the checked out branch of every database is loaded
 	for _, db := range d.provider.DoltDatabases() {
-		branchState, ok, err := d.lookupDbState(ctx, db.Name())
+		_, ok, err := d.lookupDbState(ctx, db.Name())
 		if err != nil {
 			return err
 		}
 		if !ok {
 			return fmt.Errorf("session state for database %s not found", db.Name())
 		}
-		baseName, _ := SplitRevisionDbName(db.Name())
-		roots[strings.ToLower(baseName)] = branchState.WorkingSet().WorkingRoot()
 	}
 
-	dtx.CreateSavepoint(savepointName, roots)
+	roots := make(map[string]*doltdb.RootValue)
+	d.mu.Lock()
+	for _, dbState := range d.dbStates {
+		for _, bs := range dbState.heads {
+			if bs.WorkingSet() != nil {
+				roots[strings.ToLower(bs.RevisionDbName())] = bs.WorkingSet().WorkingRoot()
+			}
+		}
+	}
+	var savepointTables []SavepointTable
+	for _, tables := range d.tempTables {
+		for _, tbl := range tables {
+			if st, ok := tbl.(SavepointTable); ok {
+				savepointTables = append(savepointTables, st)
+			}
+		}
+	}
+	d.mu.Unlock()
+
+	tables := make(map[SavepointTable]*doltdb.Table, len(savepointTables))
+	for _, st := range savepointTables {
+		snapshot, err := st.SavepointSnapshot(ctx)
+		if err != nil {
+			return err
+		}
+		tables[st] = snapshot
+	}
+
+	dtx.CreateSavepoint(savepointName, roots, tables)
 	return nil
 }
 
@@ -748,7 +891,7 @@ func (d *DoltSession) RollbackToSavepoint(ctx *sql.Context, tx sql.Transaction,
 		return fmt.Errorf("expected a DoltTransaction")
 	}
 
-	roots := dtx.RollbackToSavepoint(savepointName)
+	roots, tables := dtx.RollbackToSavepoint(savepointName)
 	if roots == nil {
 		return sql.ErrSavepointDoesNotExist.New(savepointName)
 	}
@@ -760,6 +903,17 @@ func (d *DoltSession) RollbackToSavepoint(ctx *sql.Context, tx sql.Transaction,
 		}
 	}
 
+	// Temporary tables dropped since the savepoint was created stay dropped, as in MySQL
+	for st, snapshot := range tables {
+		if !d.hasTemporaryTable(st) {
+			continue
+		}
+		err := st.RestoreSavepointSnapshot(ctx, snapshot)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -1233,10 +1387,16 @@ func (d *DoltSession) DatabaseCache(ctx *sql.Context) *DatabaseCache {
 }
 
 func (d *DoltSession) AddTemporaryTable(ctx *sql.Context, db string, tbl sql.Table) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
 	d.tempTables[strings.ToLower(db)] = append(d.tempTables[strings.ToLower(db)], tbl)
 }
 
 func (d *DoltSession) DropTemporaryTable(ctx *sql.Context, db, name string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
 	tables := d.tempTables[strings.ToLower(db)]
 	for i, tbl := range d.tempTables[strings.ToLower(db)] {
 		if strings.ToLower(tbl.Name()) == strings.ToLower(name) {
@@ -1247,6 +1407,21 @@ func (d *DoltSession) DropTemporaryTable(ctx *sql.Context, db, name string) {
 	d.tempTables[strings.ToLower(db)] = tables
 }
 
+// hasTemporaryTable returns whether the table given is one of this session's temporary tables.
+func (d *DoltSession) hasTemporaryTable(tbl sql.Table) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, tables := range d.tempTables {
+		for _, t := range tables {
+			if t == tbl {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (d *DoltSession) GetTemporaryTable(ctx *sql.Context, db, name string) (sql.Table, bool) {
 	for _, tbl := range d.tempTables[strings.ToLower(db)] {
 		if strings.ToLower(tbl.Name()) == strings.ToLower(name) {
@@ -1693,3 +1868,63 @@ func DefaultHead(baseName string, db SqlDatabase) (string, error) {
 
 	return head, nil
 }
+
+// ErrBranchWorkingSetNotFound is returned by ResolveBranchWorkingRoot when a branch has no working set.
+var ErrBranchWorkingSetNotFound = errors.New("no working set found for branch")
+
+// ErrAmbiguousBranchWorkingRef is returned by ResolveBranchWorkingRoot when a ref names both the working set of a
+// branch and a remote-tracking branch, such as 'origin/working' when there's a branch named origin.
+var ErrAmbiguousBranchWorkingRef = errors.New("ref names both a branch working set and a remote branch")
+
+// ResolveBranchWorkingRoot resolves a ref of the form 'branch/working' to the working root of that branch in the
+// database given, along with the commit at the head of the branch. This lets a session inspect the uncommitted
+// changes of a branch other than the one it has checked out, as of the last transaction committed to that branch.
+// The current user needs write permission on the branch to see its working set. Returns false if |refStr| doesn't
+// have that form or doesn't name an existing branch, so that the caller can resolve it some other way, such as a
+// remote-tracking branch named working. It's an error if |refStr| names both a branch's working set and a
+// remote-tracking branch.
+func ResolveBranchWorkingRoot(ctx *sql.Context, ddb *doltdb.DoltDB, dbName, refStr string) (*doltdb.Commit, *doltdb.RootValue, bool, error) {
+	suffix := DbRevisionDelimiter + doltdb.Working
+	if len(refStr) <= len(suffix) || !strings.EqualFold(refStr[len(refStr)-len(suffix):], suffix) {
+		return nil, nil, false, nil
+	}
+
+	branch, ok, err := ddb.HasBranch(ctx, refStr[:len(refStr)-len(suffix)])
+	if err != nil || !ok {
+		return nil, nil, false, err
+	}
+
+	if remoteRef, err := ref.NewRemoteRefFromPathStr(refStr); err == nil {
+		isRemote, err := ddb.HasRef(ctx, remoteRef)
+		if err != nil {
+			return nil, nil, false, err
+		} else if isRemote {
+			return nil, nil, false, fmt.Errorf("%w: %s", ErrAmbiguousBranchWorkingRef, refStr)
+		}
+	}
+
+	baseName, _ := SplitRevisionDbName(dbName)
+	if err = CheckAccessForBranch(ctx, baseName, branch, branch_control.Permissions_Write); err != nil {
+		return nil, nil, false, err
+	}
+
+	branchRef := ref.NewBranchRef(branch)
+	wsRef, err := ref.WorkingSetRefForHead(branchRef)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	ws, err := ddb.ResolveWorkingSet(ctx, wsRef)
+	if err == doltdb.ErrWorkingSetNotFound {
+		return nil, nil, false, fmt.Errorf("%w %s", ErrBranchWorkingSetNotFound, branch)
+	} else if err != nil {
+		return nil, nil, false, err
+	}
+
+	cm, err := ddb.ResolveCommitRef(ctx, branchRef)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	return cm, ws.WorkingRoot(), true, nil
+}
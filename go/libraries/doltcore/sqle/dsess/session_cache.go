@@ -15,12 +15,14 @@
 package dsess
 
 import (
+	"fmt"
 	"strings"
 	"sync"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/store/hash"
 )
 
 // SessionCache caches various pieces of expensive to compute information to speed up future lookups in the session.
@@ -28,6 +30,13 @@ type SessionCache struct {
 	indexes map[doltdb.DataCacheKey]map[string][]sql.Index
 	tables  map[doltdb.DataCacheKey]map[string]sql.Table
 	views   map[doltdb.DataCacheKey]map[string]sql.ViewDefinition
+	// tableCreatedAt caches the commit that introduced each table, by the hash of the head commit it was found from
+	tableCreatedAt map[hash.Hash]map[string]*doltdb.Commit
+	// columnCommits caches the commits that touched each column, by the hash of the head commit they were found from
+	columnCommits map[hash.Hash]map[string][]*doltdb.Commit
+
+	// numTables is the number of tables cached across all keys in |tables|
+	numTables int
 
 	mu sync.RWMutex
 }
@@ -113,8 +122,11 @@ func (c *SessionCache) GetTableIndexesCache(key doltdb.DataCacheKey, table strin
 	return indexes, ok
 }
 
-// CacheTable caches a sql.Table implementation for the table named
-func (c *SessionCache) CacheTable(key doltdb.DataCacheKey, tableName string, table sql.Table) {
+// CacheTable caches a sql.Table implementation for the table named. If |maxTables| is positive, at most that many
+// tables are kept in the cache: when it's full, tables cached for other keys are evicted first, since they belong to
+// roots the session has moved on from, and then the rest of the cache if that isn't enough. A |maxTables| of 0 means
+// there is no limit.
+func (c *SessionCache) CacheTable(key doltdb.DataCacheKey, tableName string, table sql.Table, maxTables int) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -123,9 +135,7 @@ func (c *SessionCache) CacheTable(key doltdb.DataCacheKey, tableName string, tab
 		c.tables = make(map[doltdb.DataCacheKey]map[string]sql.Table)
 	}
 	if len(c.tables) > maxCachedKeys {
-		for k := range c.tables {
-			delete(c.tables, k)
-		}
+		c.clearTables()
 	}
 
 	tablesForKey, ok := c.tables[key]
@@ -134,6 +144,24 @@ func (c *SessionCache) CacheTable(key doltdb.DataCacheKey, tableName string, tab
 		c.tables[key] = tablesForKey
 	}
 
+	if _, ok := tablesForKey[tableName]; !ok {
+		if maxTables > 0 && c.numTables >= maxTables {
+			for k, tables := range c.tables {
+				if k != key {
+					c.numTables -= len(tables)
+					delete(c.tables, k)
+				}
+			}
+		}
+		if maxTables > 0 && c.numTables >= maxTables {
+			for name := range tablesForKey {
+				delete(tablesForKey, name)
+			}
+			c.numTables = 0
+		}
+		c.numTables++
+	}
+
 	tablesForKey[tableName] = table
 }
 
@@ -142,9 +170,22 @@ func (c *SessionCache) ClearTableCache() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.clearTables()
+}
+
+func (c *SessionCache) clearTables() {
 	for k := range c.tables {
 		delete(c.tables, k)
 	}
+	c.numTables = 0
+}
+
+// CachedTableCount returns the number of tables currently cached, across all cache keys
+func (c *SessionCache) CachedTableCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.numTables
 }
 
 // GetCachedTable returns the cached sql.Table for the table named, and whether the cache was present
@@ -192,6 +233,14 @@ func (c *SessionCache) CacheViews(key doltdb.DataCacheKey, views []sql.ViewDefin
 	}
 }
 
+// ClearViewCache removes the cached views for the cache key given, so that they're loaded again on next use
+func (c *SessionCache) ClearViewCache(key doltdb.DataCacheKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.views, key)
+}
+
 // ViewsCached returns whether this cache has been initialized with the set of views yet
 func (c *SessionCache) ViewsCached(key doltdb.DataCacheKey) bool {
 	c.mu.RLock()
@@ -224,6 +273,97 @@ func (c *SessionCache) GetCachedViewDefinition(key doltdb.DataCacheKey, viewName
 	return table, ok
 }
 
+// CacheTableCreatedAt caches the commit that introduced the table named, as found by walking history from the commit
+// with hash |head|
+func (c *SessionCache) CacheTableCreatedAt(head hash.Hash, tableName string, cm *doltdb.Commit) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tableName = strings.ToLower(tableName)
+	if c.tableCreatedAt == nil {
+		c.tableCreatedAt = make(map[hash.Hash]map[string]*doltdb.Commit)
+	}
+	if len(c.tableCreatedAt) > maxCachedKeys {
+		for k := range c.tableCreatedAt {
+			delete(c.tableCreatedAt, k)
+		}
+	}
+
+	tablesForHead, ok := c.tableCreatedAt[head]
+	if !ok {
+		tablesForHead = make(map[string]*doltdb.Commit)
+		c.tableCreatedAt[head] = tablesForHead
+	}
+
+	tablesForHead[tableName] = cm
+}
+
+// GetCachedTableCreatedAt returns the cached commit that introduced the table named, as found by walking history from
+// the commit with hash |head|, and whether the cache was present
+func (c *SessionCache) GetCachedTableCreatedAt(head hash.Hash, tableName string) (*doltdb.Commit, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.tableCreatedAt == nil {
+		return nil, false
+	}
+
+	tablesForHead, ok := c.tableCreatedAt[head]
+	if !ok {
+		return nil, false
+	}
+
+	cm, ok := tablesForHead[strings.ToLower(tableName)]
+	return cm, ok
+}
+
+// CacheColumnCommits caches the commits that touched the column of the table named, as found by walking at most
+// |depth| commits of history from the commit with hash |head|
+func (c *SessionCache) CacheColumnCommits(head hash.Hash, tableName, columnName string, depth int, commits []*doltdb.Commit) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.columnCommits == nil {
+		c.columnCommits = make(map[hash.Hash]map[string][]*doltdb.Commit)
+	}
+	if len(c.columnCommits) > maxCachedKeys {
+		for k := range c.columnCommits {
+			delete(c.columnCommits, k)
+		}
+	}
+
+	columnsForHead, ok := c.columnCommits[head]
+	if !ok {
+		columnsForHead = make(map[string][]*doltdb.Commit)
+		c.columnCommits[head] = columnsForHead
+	}
+
+	columnsForHead[columnCommitsKey(tableName, columnName, depth)] = commits
+}
+
+// GetCachedColumnCommits returns the cached commits that touched the column of the table named, as found by walking
+// at most |depth| commits of history from the commit with hash |head|, and whether the cache was present
+func (c *SessionCache) GetCachedColumnCommits(head hash.Hash, tableName, columnName string, depth int) ([]*doltdb.Commit, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.columnCommits == nil {
+		return nil, false
+	}
+
+	columnsForHead, ok := c.columnCommits[head]
+	if !ok {
+		return nil, false
+	}
+
+	commits, ok := columnsForHead[columnCommitsKey(tableName, columnName, depth)]
+	return commits, ok
+}
+
+func columnCommitsKey(tableName, columnName string, depth int) string {
+	return fmt.Sprintf("%s\x00%s\x00%d", strings.ToLower(tableName), strings.ToLower(columnName), depth)
+}
+
 // GetCachedRevisionDb returns the cached revision database named, and whether the cache was present
 func (c *DatabaseCache) GetCachedRevisionDb(revisionDbName string, requestedName string) (SqlDatabase, bool) {
 	c.mu.RLock()
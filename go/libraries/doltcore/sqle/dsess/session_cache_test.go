@@ -0,0 +1,60 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestSessionCacheTableLimit(t *testing.T) {
+	key1 := doltdb.DataCacheKey{Hash: hash.Of([]byte("root1"))}
+	key2 := doltdb.DataCacheKey{Hash: hash.Of([]byte("root2"))}
+
+	t.Run("unlimited", func(t *testing.T) {
+		c := newSessionCache()
+		c.CacheTable(key1, "a", nil, 0)
+		c.CacheTable(key1, "b", nil, 0)
+		c.CacheTable(key2, "a", nil, 0)
+		c.CacheTable(key2, "A", nil, 0)
+		assert.Equal(t, 3, c.CachedTableCount())
+	})
+
+	t.Run("evicts other keys first", func(t *testing.T) {
+		c := newSessionCache()
+		c.CacheTable(key1, "a", nil, 2)
+		c.CacheTable(key2, "a", nil, 2)
+		c.CacheTable(key2, "b", nil, 2)
+		assert.Equal(t, 2, c.CachedTableCount())
+		_, ok := c.GetCachedTable(key1, "a")
+		assert.False(t, ok)
+		_, ok = c.GetCachedTable(key2, "a")
+		assert.True(t, ok)
+		_, ok = c.GetCachedTable(key2, "b")
+		assert.True(t, ok)
+
+		c.CacheTable(key2, "c", nil, 2)
+		assert.Equal(t, 1, c.CachedTableCount())
+		_, ok = c.GetCachedTable(key2, "c")
+		assert.True(t, ok)
+
+		c.ClearTableCache()
+		assert.Equal(t, 0, c.CachedTableCount())
+	})
+}
@@ -62,6 +62,20 @@ const (
 	RevisionTypeCommit
 )
 
+// String returns the name of this revision type, or the empty string for RevisionTypeNone.
+func (r RevisionType) String() string {
+	switch r {
+	case RevisionTypeBranch:
+		return "branch"
+	case RevisionTypeTag:
+		return "tag"
+	case RevisionTypeCommit:
+		return "commit"
+	default:
+		return ""
+	}
+}
+
 // RemoteReadReplicaDatabase is a database that pulls from a connected remote when a transaction begins.
 type RemoteReadReplicaDatabase interface {
 	// ValidReplicaState returns whether this read replica is in a valid state to pull from the remote
@@ -88,6 +102,8 @@ type DoltDatabaseProvider interface {
 	// (otherwise all branches are cloned), remoteName is the name for the remote created in the new database, and
 	// remoteUrl is a URL (e.g. "file:///dbs/db1") or an <org>/<database> path indicating a database hosted on DoltHub.
 	CloneDatabaseFromRemote(ctx *sql.Context, dbName, branch, remoteName, remoteUrl string, remoteParams map[string]string) error
+	// RenameDatabase renames the database named |oldName| to |newName|, preserving its history and branches.
+	RenameDatabase(ctx *sql.Context, oldName, newName string) error
 	// SessionDatabase returns the SessionDatabase for the specified database, which may name a revision of a base
 	// database.
 	SessionDatabase(ctx *sql.Context, dbName string) (SqlDatabase, bool, error)
@@ -89,8 +89,20 @@ type dbRoot struct {
 
 type savepoint struct {
 	name string
-	// TODO: we need a root value per DB here
+	// roots are the working roots of every branch loaded in the session, keyed by revision-qualified database name
 	roots map[string]*doltdb.RootValue
+	// tables are the contents of the session's temporary tables
+	tables map[SavepointTable]*doltdb.Table
+}
+
+// SavepointTable is a table whose contents aren't stored in a working root, such as a temporary table, but which should
+// still be restored when rolling back to a savepoint.
+type SavepointTable interface {
+	sql.Table
+	// SavepointSnapshot returns the current contents of the table, to be restored by RestoreSavepointSnapshot.
+	SavepointSnapshot(ctx *sql.Context) (*doltdb.Table, error)
+	// RestoreSavepointSnapshot replaces the contents of the table with a snapshot returned by SavepointSnapshot.
+	RestoreSavepointSnapshot(ctx *sql.Context, tbl *doltdb.Table) error
 }
 
 func NewDoltTransaction(
@@ -722,14 +734,14 @@ func (tx *DoltTransaction) validateWorkingSetForCommit(ctx *sql.Context, working
 	return nil
 }
 
-// CreateSavepoint creates a new savepoint with the name and roots given. If a savepoint with the name given
-// already exists, it's overwritten.
-func (tx *DoltTransaction) CreateSavepoint(name string, roots map[string]*doltdb.RootValue) {
+// CreateSavepoint creates a new savepoint with the name, roots and table snapshots given. If a savepoint with the name
+// given already exists, it's overwritten.
+func (tx *DoltTransaction) CreateSavepoint(name string, roots map[string]*doltdb.RootValue, tables map[SavepointTable]*doltdb.Table) {
 	existing := tx.findSavepoint(name)
 	if existing >= 0 {
 		tx.savepoints = append(tx.savepoints[:existing], tx.savepoints[existing+1:]...)
 	}
-	tx.savepoints = append(tx.savepoints, savepoint{name, roots})
+	tx.savepoints = append(tx.savepoints, savepoint{name: name, roots: roots, tables: tables})
 }
 
 // findSavepoint returns the index of the savepoint with the name given, or -1 if it doesn't exist
@@ -742,16 +754,17 @@ func (tx *DoltTransaction) findSavepoint(name string) int {
 	return -1
 }
 
-// RollbackToSavepoint returns the root values for all applicable databases associated with the savepoint name given, or nil if no such savepoint can
-// be found. All savepoints created after the one being rolled back to are no longer accessible.
-func (tx *DoltTransaction) RollbackToSavepoint(name string) map[string]*doltdb.RootValue {
+// RollbackToSavepoint returns the root values for all applicable databases and the table snapshots associated with the
+// savepoint name given, or nil maps if no such savepoint can be found. All savepoints created after the one being
+// rolled back to are no longer accessible.
+func (tx *DoltTransaction) RollbackToSavepoint(name string) (map[string]*doltdb.RootValue, map[SavepointTable]*doltdb.Table) {
 	existing := tx.findSavepoint(name)
 	if existing >= 0 {
 		// Clear out any savepoints past this one
 		tx.savepoints = tx.savepoints[:existing+1]
-		return tx.savepoints[existing].roots
+		return tx.savepoints[existing].roots, tx.savepoints[existing].tables
 	}
-	return nil
+	return nil, nil
 }
 
 // ClearSavepoint removes the savepoint with the name given and returns whether a savepoint had that name
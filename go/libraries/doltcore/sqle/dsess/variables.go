@@ -50,7 +50,12 @@ const (
 	AwsCredsProfile               = "aws_credentials_profile"
 	AwsCredsRegion                = "aws_credentials_region"
 	ShowBranchDatabases           = "dolt_show_branch_databases"
+	DiffSummarizeBlobs            = "dolt_diff_summarize_blobs"
+	TableCacheSize                = "dolt_table_cache_size"
+	DisableTableCache             = "dolt_disable_table_cache"
 	DoltLogLevel                  = "dolt_log_level"
+	DropTableDependents           = "dolt_drop_table_dependents"
+	DiffNullEquivalence           = "dolt_diff_null_equivalence"
 
 	DoltClusterRoleVariable         = "dolt_cluster_role"
 	DoltClusterRoleEpochVariable    = "dolt_cluster_role_epoch"
@@ -177,6 +182,70 @@ func GetBooleanSystemVar(ctx *sql.Context, varName string) (bool, error) {
 	return i8 == int8(1), nil
 }
 
+// GetTableCacheSize returns the maximum number of tables a session caches for each branch it has loaded, as set by the
+// dolt_table_cache_size system variable. 0 means there is no limit. Caching can be turned off entirely with the
+// dolt_disable_table_cache system variable.
+func GetTableCacheSize(ctx *sql.Context) (int, error) {
+	val, err := ctx.GetSessionVariable(ctx, TableCacheSize)
+	if err != nil {
+		return 0, err
+	}
+
+	i64, isInt64 := val.(int64)
+	if !isInt64 {
+		return 0, fmt.Errorf("unexpected type for variable %s: %T", TableCacheSize, val)
+	}
+
+	return int(i64), nil
+}
+
+// Values of the dolt_drop_table_dependents system variable, which controls what dropping a table does when views or
+// triggers reference it.
+const (
+	DropTableDependentsIgnore = "ignore"
+	DropTableDependentsWarn   = "warn"
+	DropTableDependentsError  = "error"
+)
+
+// GetDropTableDependents returns the value of the dolt_drop_table_dependents system variable.
+func GetDropTableDependents(ctx *sql.Context) (string, error) {
+	val, err := ctx.GetSessionVariable(ctx, DropTableDependents)
+	if err != nil {
+		return "", err
+	}
+
+	s, isString := val.(string)
+	if !isString {
+		return "", fmt.Errorf("unexpected type for variable %s: %T", DropTableDependents, val)
+	}
+
+	return strings.ToLower(s), nil
+}
+
+// Values of the dolt_diff_null_equivalence system variable, which controls whether the dolt_diff_<table> and
+// dolt_commit_diff_<table> system tables consider a NULL value equal to an empty string or to zero.
+const (
+	DiffNullEquivalenceNone        = "none"
+	DiffNullEquivalenceEmptyString = "empty_string"
+	DiffNullEquivalenceZero        = "zero"
+	DiffNullEquivalenceBoth        = "both"
+)
+
+// GetDiffNullEquivalence returns the value of the dolt_diff_null_equivalence system variable.
+func GetDiffNullEquivalence(ctx *sql.Context) (string, error) {
+	val, err := ctx.GetSessionVariable(ctx, DiffNullEquivalence)
+	if err != nil {
+		return "", err
+	}
+
+	s, isString := val.(string)
+	if !isString {
+		return "", fmt.Errorf("unexpected type for variable %s: %T", DiffNullEquivalence, val)
+	}
+
+	return strings.ToLower(s), nil
+}
+
 // IgnoreReplicationErrors returns true if the dolt_skip_replication_errors system variable is set to true, which means
 // that errors that occur during replication should be logged and ignored.
 func IgnoreReplicationErrors() bool {
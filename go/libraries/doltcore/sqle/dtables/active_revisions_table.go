@@ -0,0 +1,78 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+var _ sql.Table = (*ActiveRevisionsTable)(nil)
+
+// ActiveRevisionsTable is a sql.Table implementation that implements a system table which shows every revision of
+// every database that the current session has loaded in its transaction, such as `mydb/branch1`. Its contents are the
+// same no matter which database it's queried from.
+type ActiveRevisionsTable struct{}
+
+// NewActiveRevisionsTable creates an ActiveRevisionsTable
+func NewActiveRevisionsTable() sql.Table {
+	return &ActiveRevisionsTable{}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ActiveRevisionsTableName
+func (art *ActiveRevisionsTable) Name() string {
+	return doltdb.ActiveRevisionsTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ActiveRevisionsTableName
+func (art *ActiveRevisionsTable) String() string {
+	return doltdb.ActiveRevisionsTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the active revisions system table. The
+// revision_type column is one of "branch", "tag" or "commit".
+func (art *ActiveRevisionsTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "database", Type: types.Text, Source: doltdb.ActiveRevisionsTableName, PrimaryKey: true},
+		{Name: "revision", Type: types.Text, Source: doltdb.ActiveRevisionsTableName, PrimaryKey: true},
+		{Name: "revision_type", Type: types.Text, Source: doltdb.ActiveRevisionsTableName, PrimaryKey: false},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (art *ActiveRevisionsTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently, the data is unpartitioned.
+func (art *ActiveRevisionsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (art *ActiveRevisionsTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	revisions := dsess.DSessFromSess(ctx.Session).ActiveRevisions()
+	rows := make([]sql.Row, len(revisions))
+	for i, r := range revisions {
+		rows[i] = sql.NewRow(r.BaseName, r.Revision, r.RevisionType.String())
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	nomstypes "github.com/dolthub/dolt/go/store/types"
 )
 
 var _ sql.Table = (*BranchesTable)(nil)
@@ -32,11 +33,19 @@ var _ sql.UpdatableTable = (*BranchesTable)(nil)
 var _ sql.DeletableTable = (*BranchesTable)(nil)
 var _ sql.InsertableTable = (*BranchesTable)(nil)
 var _ sql.ReplaceableTable = (*BranchesTable)(nil)
+var _ sql.IndexAddressableTable = (*BranchesTable)(nil)
+var _ sql.IndexedTable = (*BranchesTable)(nil)
+
+// remoteColumnName is the name of the column holding the remote of each branch
+const remoteColumnName = "remote"
 
 // BranchesTable is the system table that accesses branches
 type BranchesTable struct {
 	db     dsess.SqlDatabase
 	remote bool
+	// remoteNames limits the refs of the remote branches table to those of the remotes named, if it's non-nil. It's
+	// set by an index lookup on the remote column.
+	remoteNames []string
 }
 
 // NewBranchesTable creates a BranchesTable
@@ -46,7 +55,7 @@ func NewBranchesTable(_ *sql.Context, db dsess.SqlDatabase) sql.Table {
 
 // NewRemoteBranchesTable creates a BranchesTable with only remote refs
 func NewRemoteBranchesTable(_ *sql.Context, ddb dsess.SqlDatabase) sql.Table {
-	return &BranchesTable{ddb, true}
+	return &BranchesTable{db: ddb, remote: true}
 }
 
 // Name is a sql.Table interface function which returns the name of the table which is defined by the constant
@@ -82,8 +91,10 @@ func (bt *BranchesTable) Schema() sql.Schema {
 		{Name: "latest_commit_date", Type: types.Datetime, Source: tableName, PrimaryKey: false, Nullable: true},
 		{Name: "latest_commit_message", Type: types.Text, Source: tableName, PrimaryKey: false, Nullable: true},
 	}
-	if !bt.remote {
-		columns = append(columns, &sql.Column{Name: "remote", Type: types.Text, Source: tableName, PrimaryKey: false, Nullable: true})
+	if bt.remote {
+		columns = append(columns, &sql.Column{Name: remoteColumnName, Type: types.Text, Source: tableName, PrimaryKey: false, Nullable: false})
+	} else {
+		columns = append(columns, &sql.Column{Name: remoteColumnName, Type: types.Text, Source: tableName, PrimaryKey: false, Nullable: true})
 		columns = append(columns, &sql.Column{Name: "branch", Type: types.Text, Source: tableName, PrimaryKey: false, Nullable: true})
 	}
 	return columns
@@ -99,6 +110,36 @@ func (bt *BranchesTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
 	return index.SinglePartitionIterFromNomsMap(nil), nil
 }
 
+// GetIndexes implements sql.IndexAddressable. The remote branches table has an index on its remote column, so that
+// queries for the branches of a single remote don't need to load the refs of every remote.
+func (bt *BranchesTable) GetIndexes(ctx *sql.Context) ([]sql.Index, error) {
+	if !bt.remote {
+		return nil, nil
+	}
+	return []sql.Index{index.MockIndex(remoteColumnName, bt.Name(), nomstypes.StringKind, false)}, nil
+}
+
+// IndexedAccess implements sql.IndexAddressable
+func (bt *BranchesTable) IndexedAccess(lookup sql.IndexLookup) sql.IndexedTable {
+	nt := *bt
+	return &nt
+}
+
+// LookupPartitions implements sql.IndexedTable
+func (bt *BranchesTable) LookupPartitions(ctx *sql.Context, lookup sql.IndexLookup) (sql.PartitionIter, error) {
+	if bt.remote && lookup.Index.ID() == remoteColumnName {
+		remoteNames, ok := index.LookupToPointSelectStr(lookup)
+		if ok {
+			bt.remoteNames = remoteNames
+			if bt.remoteNames == nil {
+				bt.remoteNames = []string{}
+			}
+		}
+	}
+
+	return bt.Partitions(ctx)
+}
+
 // PartitionRows is a sql.Table interface function that gets a row iterator for a partition
 func (bt *BranchesTable) PartitionRows(sqlCtx *sql.Context, part sql.Partition) (sql.RowIter, error) {
 	return NewBranchItr(sqlCtx, bt)
@@ -108,6 +149,7 @@ func (bt *BranchesTable) PartitionRows(sqlCtx *sql.Context, part sql.Partition)
 type BranchItr struct {
 	table    *BranchesTable
 	branches []string
+	remotes  []string
 	commits  []*doltdb.Commit
 	idx      int
 }
@@ -131,6 +173,9 @@ func NewBranchItr(ctx *sql.Context, table *BranchesTable) (*BranchItr, error) {
 		if err != nil {
 			return nil, err
 		}
+		if table.remoteNames != nil {
+			branchRefs = filterRefsByRemote(branchRefs, table.remoteNames)
+		}
 	} else {
 		branchRefs, err = ddb.GetBranchesByNomsRoot(ctx, txRoot)
 		if err != nil {
@@ -139,6 +184,7 @@ func NewBranchItr(ctx *sql.Context, table *BranchesTable) (*BranchItr, error) {
 	}
 
 	branchNames := make([]string, len(branchRefs))
+	remoteNames := make([]string, len(branchRefs))
 	commits := make([]*doltdb.Commit, len(branchRefs))
 	for i, branch := range branchRefs {
 		commit, err := ddb.ResolveCommitRefAtRoot(ctx, branch, txRoot)
@@ -147,8 +193,9 @@ func NewBranchItr(ctx *sql.Context, table *BranchesTable) (*BranchItr, error) {
 			return nil, err
 		}
 
-		if branch.GetType() == ref.RemoteRefType {
+		if remoteRef, ok := branch.(ref.RemoteRef); ok {
 			branchNames[i] = "remotes/" + branch.GetPath()
+			remoteNames[i] = remoteRef.GetRemote()
 		} else {
 			branchNames[i] = branch.GetPath()
 		}
@@ -159,11 +206,30 @@ func NewBranchItr(ctx *sql.Context, table *BranchesTable) (*BranchItr, error) {
 	return &BranchItr{
 		table:    table,
 		branches: branchNames,
+		remotes:  remoteNames,
 		commits:  commits,
 		idx:      0,
 	}, nil
 }
 
+// filterRefsByRemote returns the remote refs in |refs| that belong to one of the remotes named
+func filterRefsByRemote(refs []ref.DoltRef, remoteNames []string) []ref.DoltRef {
+	filtered := make([]ref.DoltRef, 0, len(refs))
+	for _, r := range refs {
+		remoteRef, ok := r.(ref.RemoteRef)
+		if !ok {
+			continue
+		}
+		for _, name := range remoteNames {
+			if remoteRef.GetRemote() == name {
+				filtered = append(filtered, r)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
 // Next retrieves the next row. It will return io.EOF if it's the last row.
 // After retrieving the last row, Close will be automatically closed.
 func (itr *BranchItr) Next(ctx *sql.Context) (sql.Row, error) {
@@ -191,7 +257,7 @@ func (itr *BranchItr) Next(ctx *sql.Context) (sql.Row, error) {
 
 	remoteBranches := itr.table.remote
 	if remoteBranches {
-		return sql.NewRow(name, h.String(), meta.Name, meta.Email, meta.Time(), meta.Description), nil
+		return sql.NewRow(name, h.String(), meta.Name, meta.Email, meta.Time(), meta.Description, itr.remotes[itr.idx]), nil
 	} else {
 		branches, err := itr.table.db.DbData().Rsr.GetBranches()
 
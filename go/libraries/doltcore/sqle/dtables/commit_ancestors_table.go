@@ -80,9 +80,10 @@ func (dt *CommitAncestorsTable) PartitionRows(ctx *sql.Context, p sql.Partition)
 	}
 }
 
-// GetIndexes implements sql.IndexAddressable
+// GetIndexes implements sql.IndexAddressable. The commit_hash index isn't unique, since a merge commit has one row for
+// each of its parents.
 func (dt *CommitAncestorsTable) GetIndexes(ctx *sql.Context) ([]sql.Index, error) {
-	return index.DoltCommitIndexes(dt.Name(), dt.ddb, true)
+	return index.DoltCommitIndexes(dt.Name(), dt.ddb, false)
 }
 
 // IndexedAccess implements sql.IndexAddressable
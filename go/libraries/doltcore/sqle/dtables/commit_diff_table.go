@@ -26,6 +26,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/rowconv"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/store/types"
@@ -37,9 +38,15 @@ var ErrInvalidCommitDiffTableArgs = errors.New("commit_diff_<table> requires one
 
 var _ sql.Table = (*CommitDiffTable)(nil)
 
+// RefResolver resolves a ref, hash, tag or ancestor spec to the commit and root value it names. |isBranchWorking| is
+// true when the ref names the working set of a branch, such as "main/working", in which case the root is that
+// working set's root rather than the commit's.
+type RefResolver func(ctx *sql.Context, refStr string) (cm *doltdb.Commit, root *doltdb.RootValue, isBranchWorking bool, err error)
+
 type CommitDiffTable struct {
 	name        string
 	ddb         *doltdb.DoltDB
+	resolveRef  RefResolver
 	joiner      *rowconv.Joiner
 	sqlSch      sql.PrimaryKeySchema
 	workingRoot *doltdb.RootValue
@@ -51,7 +58,7 @@ type CommitDiffTable struct {
 	targetSchema      schema.Schema
 }
 
-func NewCommitDiffTable(ctx *sql.Context, tblName string, ddb *doltdb.DoltDB, root *doltdb.RootValue) (sql.Table, error) {
+func NewCommitDiffTable(ctx *sql.Context, tblName string, ddb *doltdb.DoltDB, root *doltdb.RootValue, resolveRef RefResolver) (sql.Table, error) {
 	diffTblName := doltdb.DoltCommitDiffTablePrefix + tblName
 
 	table, _, ok, err := root.GetTableInsensitive(ctx, tblName)
@@ -80,6 +87,7 @@ func NewCommitDiffTable(ctx *sql.Context, tblName string, ddb *doltdb.DoltDB, ro
 	return &CommitDiffTable{
 		name:         tblName,
 		ddb:          ddb,
+		resolveRef:   resolveRef,
 		workingRoot:  root,
 		joiner:       j,
 		sqlSch:       sqlSch,
@@ -163,16 +171,21 @@ func (dt *CommitDiffTable) LookupPartitions(ctx *sql.Context, i sql.IndexLookup)
 		return nil, err
 	}
 
-	toTable, _, _, err := toRoot.GetTableInsensitive(ctx, dt.name)
+	toTable, _, toExists, err := toRoot.GetTableInsensitive(ctx, dt.name)
 	if err != nil {
 		return nil, err
 	}
 
-	fromTable, _, _, err := fromRoot.GetTableInsensitive(ctx, dt.name)
+	fromTable, _, fromExists, err := fromRoot.GetTableInsensitive(ctx, dt.name)
 	if err != nil {
 		return nil, err
 	}
 
+	// The table doesn't exist at either commit, so there's nothing to diff
+	if !toExists && !fromExists {
+		return NewSliceOfPartitionsItr([]sql.Partition{}), nil
+	}
+
 	dp := DiffPartition{
 		to:       toTable,
 		from:     fromTable,
@@ -184,14 +197,19 @@ func (dt *CommitDiffTable) LookupPartitions(ctx *sql.Context, i sql.IndexLookup)
 		fromSch:  dt.targetSchema,
 	}
 
-	isDiffable, err := dp.isDiffablePartition(ctx)
-	if err != nil {
-		return nil, err
-	}
+	// If the table was added or dropped between the two commits, every row is reported as added or removed, with a
+	// nil table standing in for the side where it doesn't exist. Otherwise, the two versions must have compatible
+	// primary keys to be diffed.
+	if toExists && fromExists {
+		isDiffable, err := dp.isDiffablePartition(ctx)
+		if err != nil {
+			return nil, err
+		}
 
-	if !isDiffable {
-		ctx.Warn(PrimaryKeyChangeWarningCode, fmt.Sprintf(PrimaryKeyChangeWarning, dp.fromName, dp.toName))
-		return NewSliceOfPartitionsItr([]sql.Partition{}), nil
+		if !isDiffable {
+			ctx.Warn(PrimaryKeyChangeWarningCode, fmt.Sprintf(PrimaryKeyChangeWarning, dp.fromName, dp.toName))
+			return NewSliceOfPartitionsItr([]sql.Partition{}), nil
+		}
 	}
 
 	return NewSliceOfPartitionsItr([]sql.Partition{dp}), nil
@@ -229,43 +247,36 @@ func (itr *SliceOfPartitionsItr) Close(*sql.Context) error {
 }
 
 func (dt *CommitDiffTable) rootValForHash(ctx *sql.Context, hashStr string) (*doltdb.RootValue, string, *types.Timestamp, error) {
-	var root *doltdb.RootValue
-	var commitTime *types.Timestamp
-	if strings.ToLower(hashStr) == "working" {
-		root = dt.workingRoot
-	} else {
-		cs, err := doltdb.NewCommitSpec(hashStr)
-
-		if err != nil {
-			return nil, "", nil, err
-		}
-
-		cm, err := dt.ddb.Resolve(ctx, cs, nil)
-
-		if err != nil {
-			return nil, "", nil, err
-		}
-
-		root, err = cm.GetRootValue(ctx)
-
-		if err != nil {
-			return nil, "", nil, err
-		}
-
-		meta, err := cm.GetCommitMeta(ctx)
+	if strings.EqualFold(hashStr, doltdb.Working) {
+		return dt.workingRoot, hashStr, nil, nil
+	}
 
-		if err != nil {
-			return nil, "", nil, err
-		}
+	cm, root, isBranchWorking, err := dt.resolveRef(ctx, hashStr)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if isBranchWorking || strings.EqualFold(hashStr, doltdb.Staged) {
+		return root, hashStr, nil, nil
+	}
 
-		t := meta.Time()
-		commitTime = (*types.Timestamp)(&t)
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return nil, "", nil, err
 	}
 
-	return root, hashStr, commitTime, nil
+	t := meta.Time()
+	return root, hashStr, (*types.Timestamp)(&t), nil
 }
 
 func (dt *CommitDiffTable) PartitionRows(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
 	dp := part.(DiffPartition)
-	return dp.GetRowIter(ctx, dt.ddb, dt.joiner, sql.IndexLookup{})
+	nullEquivalence, err := dsess.GetDiffNullEquivalence(ctx)
+	if err != nil {
+		return nil, err
+	}
+	iter, err := dp.WithNullEquivalence(nullEquivalence).GetRowIter(ctx, dt.ddb, dt.joiner, sql.IndexLookup{})
+	if err != nil {
+		return nil, err
+	}
+	return summarizeDiffBlobs(ctx, dt.sqlSch.Schema, iter)
 }
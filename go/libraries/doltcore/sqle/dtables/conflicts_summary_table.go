@@ -0,0 +1,133 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+)
+
+var _ sql.Table = (*ConflictsSummaryTable)(nil)
+
+// ConflictsSummaryTable is a sql.Table implementation that implements a system table which shows, for each table in
+// the working set with unresolved merge artifacts, its number of data conflicts, whether it has a schema conflict,
+// and its number of constraint violations. It combines the contents of dolt_conflicts, dolt_schema_conflicts and
+// dolt_constraint_violations.
+type ConflictsSummaryTable struct {
+	dbName string
+}
+
+// NewConflictsSummaryTable creates a ConflictsSummaryTable
+func NewConflictsSummaryTable(_ *sql.Context, dbName string) sql.Table {
+	return &ConflictsSummaryTable{dbName: dbName}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ConflictsSummaryTableName
+func (cst *ConflictsSummaryTable) Name() string {
+	return doltdb.ConflictsSummaryTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ConflictsSummaryTableName
+func (cst *ConflictsSummaryTable) String() string {
+	return doltdb.ConflictsSummaryTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the conflicts summary system table. The
+// schema_conflicts column is 1 if the table has a schema conflict and 0 otherwise.
+func (cst *ConflictsSummaryTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "table", Type: types.Text, Source: doltdb.ConflictsSummaryTableName, PrimaryKey: true},
+		{Name: "data_conflicts", Type: types.Uint64, Source: doltdb.ConflictsSummaryTableName, PrimaryKey: false},
+		{Name: "schema_conflicts", Type: types.Uint64, Source: doltdb.ConflictsSummaryTableName, PrimaryKey: false},
+		{Name: "constraint_violations", Type: types.Uint64, Source: doltdb.ConflictsSummaryTableName, PrimaryKey: false},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (cst *ConflictsSummaryTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently, the data is unpartitioned.
+func (cst *ConflictsSummaryTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (cst *ConflictsSummaryTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	sess := dsess.DSessFromSess(ctx.Session)
+	ws, err := sess.WorkingSet(ctx, cst.dbName)
+	if err != nil {
+		return nil, err
+	}
+	root := ws.WorkingRoot()
+
+	withDataConflicts, err := root.TablesWithDataConflicts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	withViolations, err := root.TablesWithConstraintViolations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	withSchemaConflicts := set.NewStrSet(nil)
+	if ws.MergeActive() {
+		withSchemaConflicts.Add(ws.MergeState().TablesWithSchemaConflicts()...)
+	}
+
+	tblNames := set.NewStrSet(withDataConflicts)
+	tblNames.Add(withViolations...)
+	tblNames.Add(withSchemaConflicts.AsSlice()...)
+	names := tblNames.AsSlice()
+	sort.Strings(names)
+
+	rows := make([]sql.Row, 0, len(names))
+	for _, name := range names {
+		var dataConflicts, schemaConflicts, violations uint64
+		if withSchemaConflicts.Contains(name) {
+			schemaConflicts = 1
+		}
+
+		tbl, ok, err := root.GetTable(ctx, name)
+		if err != nil {
+			return nil, err
+		} else if !ok && schemaConflicts == 0 {
+			return nil, fmt.Errorf("table %s has merge artifacts but cannot be found", name)
+		} else if ok {
+			dataConflicts, err = tbl.NumRowsInConflict(ctx)
+			if err != nil {
+				return nil, err
+			}
+			violations, err = tbl.NumConstraintViolations(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		rows = append(rows, sql.NewRow(name, dataConflicts, schemaConflicts, violations))
+	}
+
+	return sql.RowsToRowIter(rows...), nil
+}
@@ -197,37 +197,9 @@ func (itr prollyCVIter) Next(ctx *sql.Context) (sql.Row, error) {
 		o += itr.vd.Count() - 1
 	}
 
-	switch art.ArtType {
-	case prolly.ArtifactTypeForeignKeyViol:
-		var m merge.FkCVMeta
-		err = json.Unmarshal(meta.VInfo, &m)
-		if err != nil {
-			return nil, err
-		}
-		r[o] = m
-	case prolly.ArtifactTypeUniqueKeyViol:
-		var m merge.UniqCVMeta
-		err = json.Unmarshal(meta.VInfo, &m)
-		if err != nil {
-			return nil, err
-		}
-		r[o] = m
-	case prolly.ArtifactTypeNullViol:
-		var m merge.NullViolationMeta
-		err = json.Unmarshal(meta.VInfo, &m)
-		if err != nil {
-			return nil, err
-		}
-		r[o] = m
-	case prolly.ArtifactTypeChkConsViol:
-		var m merge.CheckCVMeta
-		err = json.Unmarshal(meta.VInfo, &m)
-		if err != nil {
-			return nil, err
-		}
-		r[o] = m
-	default:
-		panic("json not implemented for artifact type")
+	r[o], err = merge.UnmarshalViolationInfo(art.ArtType, meta.VInfo)
+	if err != nil {
+		return nil, err
 	}
 
 	return r, nil
@@ -308,19 +280,7 @@ func (d *prollyCVDeleter) Close(ctx *sql.Context) error {
 }
 
 func mapCVType(artifactType prolly.ArtifactType) (outType uint64) {
-	switch artifactType {
-	case prolly.ArtifactTypeForeignKeyViol:
-		outType = uint64(merge.CvType_ForeignKey)
-	case prolly.ArtifactTypeUniqueKeyViol:
-		outType = uint64(merge.CvType_UniqueIndex)
-	case prolly.ArtifactTypeChkConsViol:
-		outType = uint64(merge.CvType_CheckConstraint)
-	case prolly.ArtifactTypeNullViol:
-		outType = uint64(merge.CvType_NotNull)
-	default:
-		panic("unhandled cv type")
-	}
-	return
+	return uint64(merge.CvTypeFromArtifactType(artifactType))
 }
 
 func unmapCVType(in merge.CvType) (out prolly.ArtifactType) {
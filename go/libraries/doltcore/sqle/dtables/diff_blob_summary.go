@@ -0,0 +1,126 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dustin/go-humanize"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// summarizeDiffBlobs wraps |iter|, which returns rows of a diff table with the schema given, so that the values of
+// its TEXT and BLOB columns are replaced by a summary of their size when the dolt_diff_summarize_blobs system
+// variable is set. A to_ value that differs from its from_ value is rendered as e.g. "<changed: 1.2 MB>", and any
+// other value as e.g. "<1.2 MB>". NULL values are left alone. If the variable isn't set, |iter| is returned as is.
+func summarizeDiffBlobs(ctx *sql.Context, sch sql.Schema, iter sql.RowIter) (sql.RowIter, error) {
+	summarize, err := dsess.GetBooleanSystemVar(ctx, dsess.DiffSummarizeBlobs)
+	if err != nil || !summarize {
+		return iter, err
+	}
+
+	toPrefix := diff.To + "_"
+	var pairs []blobColumnPair
+	for toIdx, col := range sch {
+		if !strings.HasPrefix(col.Name, toPrefix) || col.Name == toCommit || !types.IsTextBlob(col.Type) {
+			continue
+		}
+		fromIdx := sch.IndexOfColName(diff.FromColNamer(strings.TrimPrefix(col.Name, toPrefix)))
+		pairs = append(pairs, blobColumnPair{toIdx: toIdx, fromIdx: fromIdx})
+	}
+	if len(pairs) == 0 {
+		return iter, nil
+	}
+
+	return &blobSummaryIter{iter: iter, pairs: pairs}, nil
+}
+
+// blobColumnPair is the index of a to_ column of a diff table and the index of its from_ column, which is -1 if the
+// diff table doesn't have one.
+type blobColumnPair struct {
+	toIdx, fromIdx int
+}
+
+// blobSummaryIter is the sql.RowIter returned by summarizeDiffBlobs.
+type blobSummaryIter struct {
+	iter  sql.RowIter
+	pairs []blobColumnPair
+}
+
+var _ sql.RowIter = (*blobSummaryIter)(nil)
+
+// Next implements sql.RowIter.
+func (itr *blobSummaryIter) Next(ctx *sql.Context) (sql.Row, error) {
+	r, err := itr.iter.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r = r.Copy()
+	for _, p := range itr.pairs {
+		to := r[p.toIdx]
+		var from interface{}
+		if p.fromIdx >= 0 {
+			from = r[p.fromIdx]
+			r[p.fromIdx] = summarizeBlob(from, false)
+		}
+		r[p.toIdx] = summarizeBlob(to, from != nil && !blobsEqual(to, from))
+	}
+
+	return r, nil
+}
+
+// Close implements sql.RowIter.
+func (itr *blobSummaryIter) Close(ctx *sql.Context) error {
+	return itr.iter.Close(ctx)
+}
+
+// summarizeBlob returns a summary of the size of the TEXT or BLOB value given, noting whether it changed. NULL values
+// and values of unexpected types are returned as is.
+func summarizeBlob(v interface{}, changed bool) interface{} {
+	var size int
+	switch v := v.(type) {
+	case string:
+		size = len(v)
+	case []byte:
+		size = len(v)
+	default:
+		return v
+	}
+
+	if changed {
+		return fmt.Sprintf("<changed: %s>", humanize.Bytes(uint64(size)))
+	}
+	return fmt.Sprintf("<%s>", humanize.Bytes(uint64(size)))
+}
+
+func blobsEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case string:
+		b, ok := b.(string)
+		return ok && a == b
+	case []byte:
+		b, ok := b.([]byte)
+		return ok && bytes.Equal(a, b)
+	default:
+		return false
+	}
+}
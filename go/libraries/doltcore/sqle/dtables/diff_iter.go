@@ -20,12 +20,14 @@ import (
 	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	sqltypes "github.com/dolthub/go-mysql-server/sql/types"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
 	"github.com/dolthub/dolt/go/libraries/doltcore/rowconv"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/store/prolly"
@@ -465,6 +467,134 @@ func (r *repeatingRowIter) Next(ctx context.Context) (sql.Row, error) {
 	return c, nil
 }
 
+// modifiedColumnsIter wraps a diff row iterator and skips modified rows in which none of a set of columns changed.
+type modifiedColumnsIter struct {
+	iter sql.RowIter
+	cols []modifiedColumn
+	// nullEquivalence is one of the dsess.DiffNullEquivalence values, and controls whether a change between NULL and
+	// an empty string or zero counts as a change.
+	nullEquivalence string
+}
+
+// modifiedColumn is the index of a column's to_ and from_ values in a diff row, or -1 if the column doesn't exist on
+// that side of the diff, along with the type used to compare the two values.
+type modifiedColumn struct {
+	toIdx   int
+	fromIdx int
+	typ     sql.Type
+}
+
+var _ sql.RowIter = (*modifiedColumnsIter)(nil)
+
+// newModifiedColumnsIter returns an iterator over the rows of |iter|, which have the schema |diffSch|, that only
+// returns the modified rows in which at least one of the columns named in |colNames| changed. |nullEquivalence| is
+// one of the dsess.DiffNullEquivalence values.
+func newModifiedColumnsIter(iter sql.RowIter, diffSch schema.Schema, colNames []string, nullEquivalence string) *modifiedColumnsIter {
+	allCols := diffSch.GetAllCols()
+	cols := make([]modifiedColumn, 0, len(colNames))
+	for _, name := range colNames {
+		mc := modifiedColumn{toIdx: -1, fromIdx: -1}
+		if col, ok := allCols.GetByNameCaseInsensitive(diff.ToColNamer(name)); ok {
+			mc.toIdx = allCols.IndexOf(col.Name)
+			mc.typ = col.TypeInfo.ToSqlType()
+		}
+		if col, ok := allCols.GetByNameCaseInsensitive(diff.FromColNamer(name)); ok {
+			mc.fromIdx = allCols.IndexOf(col.Name)
+			if mc.typ == nil {
+				mc.typ = col.TypeInfo.ToSqlType()
+			}
+		}
+		if mc.typ != nil {
+			cols = append(cols, mc)
+		}
+	}
+	return &modifiedColumnsIter{iter: iter, cols: cols, nullEquivalence: nullEquivalence}
+}
+
+func (itr *modifiedColumnsIter) Next(ctx *sql.Context) (sql.Row, error) {
+	for {
+		r, err := itr.iter.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if r[len(r)-1] != diffTypeModified {
+			return r, nil
+		}
+
+		changed, err := itr.anyChanged(r)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			return r, nil
+		}
+	}
+}
+
+func (itr *modifiedColumnsIter) anyChanged(r sql.Row) (bool, error) {
+	for _, mc := range itr.cols {
+		var from, to interface{}
+		if mc.toIdx >= 0 {
+			to = r[mc.toIdx]
+		}
+		if mc.fromIdx >= 0 {
+			from = r[mc.fromIdx]
+		}
+
+		if from == nil || to == nil {
+			if from == to {
+				continue
+			}
+			other := from
+			if other == nil {
+				other = to
+			}
+			equivalent, err := itr.equivalentToNull(mc.typ, other)
+			if err != nil {
+				return false, err
+			}
+			if !equivalent {
+				return true, nil
+			}
+			continue
+		}
+
+		cmp, err := mc.typ.Compare(from, to)
+		if err != nil {
+			return false, err
+		}
+		if cmp != 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// equivalentToNull returns whether the non-NULL value |v| of type |typ| is considered the same as NULL, which is the
+// case for an empty string or for zero depending on this iterator's null equivalence.
+func (itr *modifiedColumnsIter) equivalentToNull(typ sql.Type, v interface{}) (bool, error) {
+	var zero interface{}
+	switch {
+	case sqltypes.IsText(typ) && (itr.nullEquivalence == dsess.DiffNullEquivalenceEmptyString || itr.nullEquivalence == dsess.DiffNullEquivalenceBoth):
+		zero = ""
+	case sqltypes.IsNumber(typ) && (itr.nullEquivalence == dsess.DiffNullEquivalenceZero || itr.nullEquivalence == dsess.DiffNullEquivalenceBoth):
+		zero = 0
+	default:
+		return false, nil
+	}
+
+	cmp, err := typ.Compare(v, zero)
+	if err != nil {
+		return false, err
+	}
+	return cmp == 0, nil
+}
+
+func (itr *modifiedColumnsIter) Close(ctx *sql.Context) error {
+	return itr.iter.Close(ctx)
+}
+
 func schemaSize(sch schema.Schema) int {
 	if sch == nil {
 		return 0
@@ -28,6 +28,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/rowconv"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/expreval"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
@@ -226,7 +227,15 @@ func (dt *DiffTable) HeadHash() (hash.Hash, error) {
 
 func (dt *DiffTable) PartitionRows(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
 	dp := part.(DiffPartition)
-	return dp.GetRowIter(ctx, dt.ddb, dt.joiner, dt.lookup)
+	nullEquivalence, err := dsess.GetDiffNullEquivalence(ctx)
+	if err != nil {
+		return nil, err
+	}
+	iter, err := dp.WithNullEquivalence(nullEquivalence).GetRowIter(ctx, dt.ddb, dt.joiner, dt.lookup)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeDiffBlobs(ctx, dt.sqlSch.Schema, iter)
 }
 
 func (dt *DiffTable) LookupPartitions(ctx *sql.Context, lookup sql.IndexLookup) (sql.PartitionIter, error) {
@@ -597,6 +606,11 @@ type DiffPartition struct {
 	// fromSch and toSch are usually identical. It is the schema of the table at head.
 	toSch   schema.Schema
 	fromSch schema.Schema
+	// modifiedCols, when non-empty, limits modified rows to those in which one of these columns changed.
+	modifiedCols []string
+	// nullEquivalence, when set to something other than dsess.DiffNullEquivalenceNone, skips modified rows whose only
+	// changes are between NULL and an empty string or zero.
+	nullEquivalence string
 }
 
 func NewDiffPartition(to, from *doltdb.Table, toName, fromName string, toDate, fromDate *types.Timestamp, toSch, fromSch schema.Schema) *DiffPartition {
@@ -616,7 +630,27 @@ func (dp DiffPartition) Key() []byte {
 	return []byte(dp.toName + dp.fromName)
 }
 
+// WithModifiedColumns returns a copy of this partition whose rows are filtered so that modified rows are only
+// returned if the value of at least one of the columns named in |cols| changed. Added and removed rows are always
+// returned. Columns are matched by name, case-insensitively.
+func (dp DiffPartition) WithModifiedColumns(cols []string) DiffPartition {
+	dp.modifiedCols = cols
+	return dp
+}
+
+// WithNullEquivalence returns a copy of this partition whose modified rows are skipped when every change in them is
+// between NULL and a value that |mode|, one of the dsess.DiffNullEquivalence values, considers the same as NULL: an
+// empty string, zero, or either of them. Added and removed rows are always returned.
+func (dp DiffPartition) WithNullEquivalence(mode string) DiffPartition {
+	dp.nullEquivalence = mode
+	return dp
+}
+
 func (dp DiffPartition) GetRowIter(ctx *sql.Context, ddb *doltdb.DoltDB, joiner *rowconv.Joiner, lookup sql.IndexLookup) (sql.RowIter, error) {
+	if len(dp.modifiedCols) > 0 || dp.hasNullEquivalence() {
+		return dp.getModifiedColumnsRowIter(ctx, ddb, joiner, lookup)
+	}
+
 	if types.IsFormat_DOLT(ddb.Format()) {
 		return newProllyDiffIter(ctx, dp, dp.fromSch, dp.toSch)
 	} else {
@@ -624,6 +658,51 @@ func (dp DiffPartition) GetRowIter(ctx *sql.Context, ddb *doltdb.DoltDB, joiner
 	}
 }
 
+func (dp DiffPartition) getModifiedColumnsRowIter(ctx *sql.Context, ddb *doltdb.DoltDB, joiner *rowconv.Joiner, lookup sql.IndexLookup) (sql.RowIter, error) {
+	diffSch, _, err := GetDiffTableSchemaAndJoiner(ddb.Format(), dp.fromSch, dp.toSch)
+	if err != nil {
+		return nil, err
+	}
+
+	cols := dp.modifiedCols
+	if len(cols) == 0 {
+		// Without a column filter, a modified row is kept if any of its columns changed
+		cols = diffColumnNames(dp.fromSch, dp.toSch)
+	}
+
+	unfiltered := dp
+	unfiltered.modifiedCols = nil
+	unfiltered.nullEquivalence = ""
+	iter, err := unfiltered.GetRowIter(ctx, ddb, joiner, lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	return newModifiedColumnsIter(iter, diffSch, cols, dp.nullEquivalence), nil
+}
+
+func (dp DiffPartition) hasNullEquivalence() bool {
+	return dp.nullEquivalence != "" && dp.nullEquivalence != dsess.DiffNullEquivalenceNone
+}
+
+// diffColumnNames returns the names of the columns of |fromSch| and |toSch|, each name only once.
+func diffColumnNames(fromSch, toSch schema.Schema) []string {
+	var names []string
+	seen := set.NewStrSet(nil)
+	for _, sch := range []schema.Schema{fromSch, toSch} {
+		if sch == nil {
+			continue
+		}
+		for _, name := range sch.GetAllCols().GetColumnNames() {
+			if !seen.Contains(name) {
+				seen.Add(name)
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
 // isDiffablePartition checks if the commit pair for this partition is "diffable".
 // If the primary key sets changed between the two commits, it may not be
 // possible to diff them.
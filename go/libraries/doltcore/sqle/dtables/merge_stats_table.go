@@ -0,0 +1,87 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+var _ sql.Table = (*MergeStatsTable)(nil)
+
+// MergeStatsTable is a sql.Table implementation that implements a system table which shows the row counts of the last
+// call to dolt_merge on a database in the current session, with a row for each table the merge changed rows of.
+type MergeStatsTable struct {
+	dbName string
+}
+
+// NewMergeStatsTable creates a MergeStatsTable
+func NewMergeStatsTable(dbName string) sql.Table {
+	return &MergeStatsTable{dbName: dbName}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// MergeStatsTableName
+func (mst *MergeStatsTable) Name() string {
+	return doltdb.MergeStatsTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// MergeStatsTableName
+func (mst *MergeStatsTable) String() string {
+	return doltdb.MergeStatsTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the merge stats system table.
+func (mst *MergeStatsTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "table_name", Type: types.Text, Source: doltdb.MergeStatsTableName, PrimaryKey: true},
+		{Name: "auto_merged_rows", Type: types.Int64, Source: doltdb.MergeStatsTableName, PrimaryKey: false},
+		{Name: "rows_removed_for_violations", Type: types.Int64, Source: doltdb.MergeStatsTableName, PrimaryKey: false},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (mst *MergeStatsTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently, the data is unpartitioned.
+func (mst *MergeStatsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (mst *MergeStatsTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	counts := dsess.DSessFromSess(ctx.Session).MergeRowCounts(mst.dbName)
+	tblNames := make([]string, 0, len(counts))
+	for tblName := range counts {
+		tblNames = append(tblNames, tblName)
+	}
+	sort.Strings(tblNames)
+
+	rows := make([]sql.Row, len(tblNames))
+	for i, tblName := range tblNames {
+		c := counts[tblName]
+		rows[i] = sql.NewRow(tblName, int64(c.AutoMerged), int64(c.RemovedForViolations))
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
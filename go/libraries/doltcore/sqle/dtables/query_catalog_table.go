@@ -17,6 +17,7 @@ package dtables
 import (
 	"context"
 	"io"
+	"sort"
 
 	"github.com/google/uuid"
 	"gopkg.in/src-d/go-errors.v1"
@@ -350,6 +351,162 @@ func retrieveFromQueryCatalogNoms(ctx context.Context, tbl *doltdb.Table, id str
 	return savedQueryFromKVNoms(id, val.(types.Tuple))
 }
 
+// RetrieveAllFromQueryCatalog returns all the entries in the query catalog table, in display order. Returns no
+// entries if the table doesn't exist.
+func RetrieveAllFromQueryCatalog(ctx context.Context, root *doltdb.RootValue) ([]SavedQuery, error) {
+	tbl, ok, err := root.GetTable(ctx, doltdb.DoltQueryCatalogTableName)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, nil
+	}
+
+	var queries []SavedQuery
+	if types.IsFormat_DOLT(tbl.Format()) {
+		queries, err = retrieveAllFromQueryCatalogProlly(ctx, tbl)
+	} else {
+		queries, err = retrieveAllFromQueryCatalogNoms(ctx, tbl)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(queries, func(i, j int) bool {
+		return queries[i].Order < queries[j].Order
+	})
+	return queries, nil
+}
+
+func retrieveAllFromQueryCatalogProlly(ctx context.Context, tbl *doltdb.Table) ([]SavedQuery, error) {
+	idx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	itr, err := durable.ProllyMapFromIndex(idx).IterAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var queries []SavedQuery
+	for {
+		k, v, err := itr.Next(ctx)
+		if err == io.EOF {
+			return queries, nil
+		} else if err != nil {
+			return nil, err
+		}
+		id, _ := catalogKd.GetString(0, k)
+		sq, err := savedQueryFromKVProlly(id, v)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, sq)
+	}
+}
+
+func retrieveAllFromQueryCatalogNoms(ctx context.Context, tbl *doltdb.Table) ([]SavedQuery, error) {
+	m, err := tbl.GetNomsRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var queries []SavedQuery
+	err = m.IterAll(ctx, func(key, value types.Value) error {
+		r, err := row.FromNoms(DoltQueryCatalogSchema, key.(types.Tuple), value.(types.Tuple))
+		if err != nil {
+			return err
+		}
+		idVal, _ := r.GetColVal(schema.QueryCatalogIdTag)
+		sq, err := savedQueryFromKVNoms(string(idVal.(types.String)), value.(types.Tuple))
+		if err != nil {
+			return err
+		}
+		queries = append(queries, sq)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return queries, nil
+}
+
+// RemoveFromQueryCatalog deletes the entry with the ID given from the query catalog table and returns the new root
+// value. Returns ErrQueryNotFound if there's no such entry.
+func RemoveFromQueryCatalog(ctx context.Context, root *doltdb.RootValue, id string) (*doltdb.RootValue, error) {
+	tbl, ok, err := root.GetTable(ctx, doltdb.DoltQueryCatalogTableName)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrQueryNotFound.New(id)
+	}
+
+	if types.IsFormat_DOLT(tbl.Format()) {
+		tbl, err = removeFromQueryCatalogProlly(ctx, tbl, id)
+	} else {
+		tbl, err = removeFromQueryCatalogNoms(ctx, tbl, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return root.PutTable(ctx, doltdb.DoltQueryCatalogTableName, tbl)
+}
+
+func removeFromQueryCatalogProlly(ctx context.Context, tbl *doltdb.Table, id string) (*doltdb.Table, error) {
+	if _, err := retrieveFromQueryCatalogProlly(ctx, tbl, id); err != nil {
+		return nil, err
+	}
+
+	idx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m := durable.ProllyMapFromIndex(idx)
+
+	kb := val.NewTupleBuilder(catalogKd)
+	kb.PutString(0, id)
+	k := kb.Build(m.Pool())
+
+	mut := m.Mutate()
+	err = mut.Delete(ctx, k)
+	if err != nil {
+		return nil, err
+	}
+	m, err = mut.Map(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return tbl.UpdateRows(ctx, durable.IndexFromProllyMap(m))
+}
+
+func removeFromQueryCatalogNoms(ctx context.Context, tbl *doltdb.Table, id string) (*doltdb.Table, error) {
+	if _, err := retrieveFromQueryCatalogNoms(ctx, tbl, id); err != nil {
+		return nil, err
+	}
+
+	data, err := tbl.GetNomsRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := types.NewTuple(tbl.Format(), types.Uint(schema.QueryCatalogIdTag), types.String(id))
+	if err != nil {
+		return nil, err
+	}
+
+	me := data.Edit()
+	me.Remove(k)
+	updated, err := me.Map(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return tbl.UpdateNomsRows(ctx, updated)
+}
+
 // Returns the largest order entry in the catalog
 func getMaxQueryOrderNoms(data types.Map, ctx context.Context) uint64 {
 	maxOrder := uint64(0)
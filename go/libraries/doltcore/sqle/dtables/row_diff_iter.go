@@ -0,0 +1,110 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ErrPrimaryKeySetChanged is returned by NewRowDiffIter when the primary key of a table changed between the two
+// revisions being diffed, so that their rows can't be matched up. Its message is the same as PrimaryKeyChangeWarning.
+var ErrPrimaryKeySetChanged = errors.NewKind(PrimaryKeyChangeWarning)
+
+// RowDiffIter iterates over the rows that changed in a table between two revisions, without going through the
+// SQL engine. Rows are produced in the same form as the rows of the dolt_commit_diff_$table system table, converted
+// to the schema of the table at the to revision, or at the from revision if the table was dropped.
+type RowDiffIter struct {
+	iter    sql.RowIter
+	toLen   int
+	fromLen int
+}
+
+// NewRowDiffIter returns a RowDiffIter over the changes to a table between |fromTbl| and |toTbl|, which are named
+// |fromName| and |toName| and were committed at |fromDate| and |toDate|. Either table may be nil if it didn't exist
+// at that revision, but not both. Returns ErrPrimaryKeySetChanged if the tables' primary keys are incompatible.
+func NewRowDiffIter(ctx *sql.Context, ddb *doltdb.DoltDB, toTbl, fromTbl *doltdb.Table, toName, fromName string, toDate, fromDate *types.Timestamp) (*RowDiffIter, error) {
+	tbl := toTbl
+	if tbl == nil {
+		tbl = fromTbl
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dp := NewDiffPartition(toTbl, fromTbl, toName, fromName, toDate, fromDate, sch, sch)
+	if toTbl != nil && fromTbl != nil {
+		isDiffable, err := dp.isDiffablePartition(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !isDiffable {
+			return nil, ErrPrimaryKeySetChanged.New(fromName, toName)
+		}
+	}
+
+	_, j, err := GetDiffTableSchemaAndJoiner(ddb.Format(), sch, sch)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := dp.GetRowIter(ctx, ddb, j, sql.IndexLookup{})
+	if err != nil {
+		return nil, err
+	}
+
+	n := schemaSize(sch)
+	return &RowDiffIter{iter: iter, toLen: n, fromLen: n}, nil
+}
+
+// EmptyRowDiffIter returns a RowDiffIter with no changes, such as for a table that doesn't exist at either revision.
+func EmptyRowDiffIter() *RowDiffIter {
+	return &RowDiffIter{iter: sql.RowsToRowIter()}
+}
+
+// Next returns the next changed row. |diffType| is one of "added", "modified" or "removed", and |from| and |to| are
+// the row before and after the change. |from| is nil for added rows and |to| is nil for removed rows. Returns io.EOF
+// when there are no more changes, and the context's error if it's canceled.
+func (itr *RowDiffIter) Next(ctx *sql.Context) (diffType string, from, to sql.Row, err error) {
+	if err = ctx.Err(); err != nil {
+		return "", nil, nil, err
+	}
+
+	r, err := itr.iter.Next(ctx)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	// to columns, to_commit, to_commit_date, from columns, from_commit, from_commit_date, diff_type
+	fromStart := itr.toLen + 2
+	diffType = r[fromStart+itr.fromLen+2].(string)
+	if diffType != diffTypeRemoved {
+		to = r[:itr.toLen]
+	}
+	if diffType != diffTypeAdded {
+		from = r[fromStart : fromStart+itr.fromLen]
+	}
+
+	return diffType, from, to, nil
+}
+
+// Close releases the resources held by this iterator. It must be called even if Next returned an error.
+func (itr *RowDiffIter) Close(ctx *sql.Context) error {
+	return itr.iter.Close(ctx)
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+var _ sql.Table = (*StorageStatsTable)(nil)
+
+// StorageStatsTable is a sql.Table implementation that implements a system table which shows statistics about the
+// storage used by a database. It always has exactly one row.
+type StorageStatsTable struct {
+	ddb *doltdb.DoltDB
+}
+
+// NewStorageStatsTable creates a StorageStatsTable
+func NewStorageStatsTable(_ *sql.Context, ddb *doltdb.DoltDB) sql.Table {
+	return &StorageStatsTable{ddb: ddb}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// StorageStatsTableName
+func (st *StorageStatsTable) Name() string {
+	return doltdb.StorageStatsTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// StorageStatsTableName
+func (st *StorageStatsTable) String() string {
+	return doltdb.StorageStatsTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the storage stats system table. The
+// new_gen_bytes column is the size of the chunks written since the last garbage collection, see
+// doltdb.StorageStats.NewGenBytes.
+func (st *StorageStatsTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "chunk_count", Type: types.Uint64, Source: doltdb.StorageStatsTableName, PrimaryKey: false},
+		{Name: "total_bytes", Type: types.Uint64, Source: doltdb.StorageStatsTableName, PrimaryKey: false},
+		{Name: "new_gen_bytes", Type: types.Uint64, Source: doltdb.StorageStatsTableName, PrimaryKey: false},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (st *StorageStatsTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently, the data is unpartitioned.
+func (st *StorageStatsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (st *StorageStatsTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	stats, err := st.ddb.StorageStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sql.RowsToRowIter(sql.NewRow(stats.ChunkCount, stats.TotalBytes, stats.NewGenBytes)), nil
+}
@@ -0,0 +1,577 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enginetest
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/enginetest"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestDatabaseDiffSchemas(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table parent (id int primary key);",
+		"create table t (pk int primary key, a int, b int, c int, pid int, index idx_a (a), constraint chk_b check (b > 0));",
+		"call dolt_commit('-Am', 'creating tables');",
+		"alter table t rename column a to a2;",
+		"alter table t drop column c;",
+		"alter table t add column d varchar(10);",
+		"alter table t modify column b bigint;",
+		"alter table t add index idx_d (d);",
+		"alter table t drop check chk_b;",
+		"alter table t add constraint chk_pk check (pk > 0);",
+		"alter table t add constraint fk_parent foreign key (pid) references parent (id);",
+	)
+	defer engine.Close()
+
+	sd, err := db.DiffSchemas(ctx, "T", "HEAD", "WORKING")
+	require.NoError(t, err)
+
+	type colChange struct {
+		diffType diff.SchemaChangeType
+		from, to string
+	}
+	var cols []colChange
+	for _, d := range sd.Columns {
+		var c colChange
+		c.diffType = d.DiffType
+		if d.Old != nil {
+			c.from = d.Old.Name
+		}
+		if d.New != nil {
+			c.to = d.New.Name
+		}
+		cols = append(cols, c)
+	}
+	assert.Equal(t, []colChange{
+		{diff.SchDiffModified, "a", "a2"},
+		{diff.SchDiffModified, "b", "b"},
+		{diff.SchDiffRemoved, "c", ""},
+		{diff.SchDiffAdded, "", "d"},
+	}, cols)
+
+	// adding the foreign key also adds an index on pid
+	addedIndexes := make(map[string]bool)
+	for _, d := range sd.Indexes {
+		if d.DiffType == diff.SchDiffAdded {
+			addedIndexes[d.To.Name()] = true
+		}
+	}
+	assert.True(t, addedIndexes["idx_d"])
+	assert.Len(t, addedIndexes, 2)
+
+	require.Len(t, sd.ForeignKeys, 1)
+	assert.Equal(t, diff.SchDiffAdded, sd.ForeignKeys[0].DiffType)
+	assert.Equal(t, "fk_parent", sd.ForeignKeys[0].To.Name)
+
+	require.Len(t, sd.Checks, 2)
+	assert.Equal(t, diff.SchDiffRemoved, sd.Checks[0].DiffType)
+	assert.Equal(t, "chk_b", sd.Checks[0].From.Name())
+	assert.Equal(t, diff.SchDiffAdded, sd.Checks[1].DiffType)
+	assert.Equal(t, "chk_pk", sd.Checks[1].To.Name())
+
+	sd, err = db.DiffSchemas(ctx, "parent", "HEAD", "WORKING")
+	require.NoError(t, err)
+	assert.True(t, sd.IsEmpty())
+
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "create table new_t (pk int primary key)")
+	sd, err = db.DiffSchemas(ctx, "new_t", "HEAD", "WORKING")
+	require.NoError(t, err)
+	require.Len(t, sd.Columns, 1)
+	assert.Equal(t, diff.SchDiffAdded, sd.Columns[0].DiffType)
+
+	_, err = db.DiffSchemas(ctx, "missing", "HEAD", "WORKING")
+	require.Error(t, err)
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+}
+
+func TestDatabaseWorkingDiff(t *testing.T) {
+	skipOldFormat(t)
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key, c int);",
+		"create table dropped (pk int primary key);",
+		"create table clean (pk int primary key);",
+		"insert into t values (1, 1), (2, 2);",
+		"insert into dropped values (1);",
+		"call dolt_commit('-Am', 'creating tables');",
+		"update t set c = 10 where pk = 1;",
+		"insert into t values (3, 3);",
+		"drop table dropped;",
+		"create table created (pk int primary key);",
+		"insert into created values (1);",
+	)
+	defer engine.Close()
+
+	type rowDiff struct {
+		diffType string
+		from, to sql.Row
+	}
+	workingDiff := func(tableName string) []rowDiff {
+		iter, err := db.WorkingDiff(ctx, tableName)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, iter.Close(ctx))
+		}()
+
+		var diffs []rowDiff
+		for {
+			diffType, from, to, err := iter.Next(ctx)
+			if err == io.EOF {
+				return diffs
+			}
+			require.NoError(t, err)
+			diffs = append(diffs, rowDiff{diffType, from, to})
+		}
+	}
+
+	assert.Equal(t, []rowDiff{
+		{"modified", sql.Row{int32(1), int32(1)}, sql.Row{int32(1), int32(10)}},
+		{"added", nil, sql.Row{int32(3), int32(3)}},
+	}, workingDiff("T"))
+	assert.Equal(t, []rowDiff{{"added", nil, sql.Row{int32(1)}}}, workingDiff("created"))
+	assert.Equal(t, []rowDiff{{"removed", sql.Row{int32(1)}, nil}}, workingDiff("dropped"))
+	assert.Empty(t, workingDiff("clean"))
+
+	_, err := db.WorkingDiff(ctx, "missing")
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+}
+
+func TestDatabaseChangesSince(t *testing.T) {
+	skipOldFormat(t)
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key, c int);",
+		"insert into t values (1, 1), (2, 2);",
+	)
+	defer engine.Close()
+
+	type rowDiff struct {
+		diffType string
+		from, to sql.Row
+	}
+	changesSince := func(tableName string, since hash.Hash) ([]rowDiff, hash.Hash) {
+		iter, next, err := db.ChangesSince(ctx, tableName, since)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, iter.Close(ctx))
+		}()
+
+		var diffs []rowDiff
+		for {
+			diffType, from, to, err := iter.Next(ctx)
+			if err == io.EOF {
+				return diffs, next
+			}
+			require.NoError(t, err)
+			diffs = append(diffs, rowDiff{diffType, from, to})
+		}
+	}
+
+	diffs, cursor := changesSince("t", hash.Hash{})
+	assert.Equal(t, []rowDiff{
+		{"added", nil, sql.Row{int32(1), int32(1)}},
+		{"added", nil, sql.Row{int32(2), int32(2)}},
+	}, diffs)
+
+	diffs, next := changesSince("t", cursor)
+	assert.Empty(t, diffs)
+	assert.Equal(t, cursor, next)
+
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "update t set c = 10 where pk = 1")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into t values (3, 3)")
+	diffs, next = changesSince("T", cursor)
+	assert.Equal(t, []rowDiff{
+		{"modified", sql.Row{int32(1), int32(1)}, sql.Row{int32(1), int32(10)}},
+		{"added", nil, sql.Row{int32(3), int32(3)}},
+	}, diffs)
+	assert.NotEqual(t, cursor, next)
+	cursor = next
+
+	// tables missing at the cursor or now
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "create table t2 (pk int primary key)")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into t2 values (1)")
+	diffs, _ = changesSince("t2", cursor)
+	assert.Equal(t, []rowDiff{{"added", nil, sql.Row{int32(1)}}}, diffs)
+	diffs, _ = changesSince("t", cursor)
+	assert.Empty(t, diffs)
+	diffs, _ = changesSince("missing", cursor)
+	assert.Empty(t, diffs)
+
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "drop table t")
+	diffs, _ = changesSince("t", cursor)
+	assert.Len(t, diffs, 3)
+	for _, d := range diffs {
+		assert.Equal(t, "removed", d.diffType)
+	}
+
+	_, _, err := db.ChangesSince(ctx, "t2", hash.Of([]byte("not a root")))
+	require.Error(t, err)
+	assert.True(t, sqle.ErrNotRootHash.Is(err))
+}
+
+func TestDatabaseDiffAsSQL(t *testing.T) {
+	skipOldFormat(t)
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key, c varchar(10));",
+		"create table k (a int, b int);",
+		"insert into t values (1, 'one'), (2, 'two'), (3, 'three');",
+		"insert into k values (1, 1), (1, 1), (2, null);",
+		"call dolt_commit('-Am', 'creating tables');",
+		"call dolt_branch('other');",
+		"update t set c = 'TWO' where pk = 2;",
+		"delete from t where pk = 3;",
+		"insert into t values (4, 'four');",
+		"delete from k where a = 1 limit 1;",
+		"delete from k where a = 2;",
+		"insert into k values (3, 3);",
+		"call dolt_commit('-am', 'changing tables');",
+	)
+	defer engine.Close()
+
+	var buf bytes.Buffer
+	require.NoError(t, db.DiffAsSQL(ctx, "t", "HEAD~1", "HEAD", &buf))
+	assert.Equal(t, "UPDATE `t` SET `c`='TWO' WHERE `pk`=2;\n"+
+		"DELETE FROM `t` WHERE `pk`=3;\n"+
+		"INSERT INTO `t` (`pk`,`c`) VALUES (4,'four');\n", buf.String())
+
+	var keyless bytes.Buffer
+	require.NoError(t, db.DiffAsSQL(ctx, "k", "HEAD~1", "HEAD", &keyless))
+	assert.NotContains(t, keyless.String(), "UPDATE")
+
+	// applying the statements to the old revision gives the new one
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_checkout('other');")
+	for _, stmt := range strings.Split(strings.TrimSpace(buf.String()+keyless.String()), "\n") {
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, stmt)
+	}
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from t order by pk;",
+		[]sql.Row{{1, "one"}, {2, "TWO"}, {4, "four"}}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from k order by a, b;",
+		[]sql.Row{{1, 1}, {3, 3}}, nil, nil)
+
+	buf.Reset()
+	require.NoError(t, db.DiffAsSQL(ctx, "t", "HEAD", "HEAD", &buf))
+	assert.Empty(t, buf.String())
+
+	err := db.DiffAsSQL(ctx, "missing", "HEAD~1", "HEAD", &buf)
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+
+	// HEAD is resolved after a commit made by the session, so a table added by the commit isn't written as one INSERT
+	// per row from a stale HEAD
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_checkout('main');")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "create table n (pk int primary key);")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into n values (1), (2);")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_commit('-Am', 'creating table n');")
+	buf.Reset()
+	require.NoError(t, db.DiffAsSQL(ctx, "n", "HEAD", "WORKING", &buf))
+	assert.Empty(t, buf.String())
+	require.NoError(t, inTransaction(t, ctx, func() error {
+		return db.DiffAsSQL(ctx, "n", "HEAD", "WORKING", &buf)
+	}))
+	assert.Empty(t, buf.String())
+	require.NoError(t, db.DiffAsSQL(ctx, "n", "HEAD~1", "HEAD", &buf))
+	assert.Equal(t, "INSERT INTO `n` (`pk`) VALUES (1);\n"+
+		"INSERT INTO `n` (`pk`) VALUES (2);\n", buf.String())
+}
+
+func TestDatabaseDiffRows(t *testing.T) {
+	skipOldFormat(t)
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key, c int);",
+		"insert into t values (1, 1), (2, 2);",
+		"call dolt_commit('-Am', 'creating table t');",
+		"insert into t values (3, 3);",
+		"update t set c = 20 where pk = 2;",
+		"delete from t where pk = 1;",
+		"call dolt_commit('-am', 'changing rows');",
+		"insert into t values (4, 4);",
+	)
+	defer engine.Close()
+
+	type rowDiff struct {
+		diffType string
+		from, to sql.Row
+	}
+	collect := func(fromRef, toRef string) []rowDiff {
+		iter, err := db.DiffRows(ctx, "T", fromRef, toRef)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, iter.Close(ctx))
+		}()
+
+		var diffs []rowDiff
+		for {
+			diffType, from, to, err := iter.Next(ctx)
+			if err == io.EOF {
+				return diffs
+			}
+			require.NoError(t, err)
+			diffs = append(diffs, rowDiff{diffType, from, to})
+		}
+	}
+
+	assert.Equal(t, []rowDiff{
+		{"removed", sql.Row{int32(1), int32(1)}, nil},
+		{"modified", sql.Row{int32(2), int32(2)}, sql.Row{int32(2), int32(20)}},
+		{"added", nil, sql.Row{int32(3), int32(3)}},
+	}, collect("HEAD~1", "HEAD"))
+
+	assert.Equal(t, []rowDiff{
+		{"added", nil, sql.Row{int32(4), int32(4)}},
+	}, collect("HEAD", "WORKING"))
+
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "drop table t;")
+	assert.Equal(t, []rowDiff{
+		{"removed", sql.Row{int32(2), int32(20)}, nil},
+		{"removed", sql.Row{int32(3), int32(3)}, nil},
+	}, collect("HEAD", "WORKING"))
+
+	_, err := db.DiffRows(ctx, "missing", "HEAD", "WORKING")
+	require.Error(t, err)
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "create table t (pk int primary key, c int);")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into t values (1, 1);")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "alter table t drop primary key;")
+	_, err = db.DiffRows(ctx, "t", "HEAD", "WORKING")
+	require.Error(t, err)
+	assert.True(t, dtables.ErrPrimaryKeySetChanged.Is(err))
+}
+
+func TestDatabaseApplyDiff(t *testing.T) {
+	skipOldFormat(t)
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key, c int);",
+		"insert into t values (1, 1), (2, 2);",
+		"call dolt_commit('-Am', 'creating table t');",
+		"insert into t values (3, 3);",
+		"update t set c = 20 where pk = 2;",
+		"delete from t where pk = 1;",
+		"call dolt_commit('-am', 'changing rows');",
+		"create table k (c int);",
+	)
+	defer engine.Close()
+
+	// Applying the reverse of the last commit's changes restores the table to its previous state
+	iter, err := db.DiffRows(ctx, "t", "HEAD", "HEAD~1")
+	require.NoError(t, err)
+	require.NoError(t, inTransaction(t, ctx, func() error { return db.ApplyDiff(ctx, "t", iter) }))
+	require.NoError(t, iter.Close(ctx))
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from t order by pk",
+		[]sql.Row{{1, 1}, {2, 2}}, nil, nil)
+
+	// The same changes no longer apply, and nothing is written
+	iter, err = db.DiffRows(ctx, "t", "HEAD", "HEAD~1")
+	require.NoError(t, err)
+	err = inTransaction(t, ctx, func() error { return db.ApplyDiff(ctx, "t", iter) })
+	require.NoError(t, iter.Close(ctx))
+	require.Error(t, err)
+	assert.True(t, sqle.ErrApplyDiffConflict.Is(err))
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from t order by pk",
+		[]sql.Row{{1, 1}, {2, 2}}, nil, nil)
+
+	iter, err = db.DiffRows(ctx, "t", "HEAD~1", "HEAD")
+	require.NoError(t, err)
+	require.NoError(t, inTransaction(t, ctx, func() error { return db.ApplyDiff(ctx, "t", iter) }))
+	require.NoError(t, iter.Close(ctx))
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from t order by pk",
+		[]sql.Row{{2, 20}, {3, 3}}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from dolt_status where table_name = 't'",
+		[]sql.Row{}, nil, nil)
+
+	// Changes to the same row are checked against the row as left by the changes before them
+	changes := &rowDiffList{
+		{"added", nil, sql.Row{int32(4), int32(4)}},
+		{"modified", sql.Row{int32(4), int32(4)}, sql.Row{int32(4), int32(40)}},
+		{"removed", sql.Row{int32(3), int32(3)}, nil},
+		{"added", nil, sql.Row{int32(3), int32(30)}},
+	}
+	require.NoError(t, inTransaction(t, ctx, func() error { return db.ApplyDiff(ctx, "t", changes) }))
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from t order by pk",
+		[]sql.Row{{2, 20}, {3, 30}, {4, 40}}, nil, nil)
+
+	changes = &rowDiffList{
+		{"removed", sql.Row{int32(2), int32(20)}, nil},
+		{"modified", sql.Row{int32(2), int32(20)}, sql.Row{int32(2), int32(200)}},
+	}
+	err = inTransaction(t, ctx, func() error { return db.ApplyDiff(ctx, "t", changes) })
+	require.Error(t, err)
+	assert.True(t, sqle.ErrApplyDiffConflict.Is(err))
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from t order by pk",
+		[]sql.Row{{2, 20}, {3, 30}, {4, 40}}, nil, nil)
+
+	iter, err = db.DiffRows(ctx, "t", "HEAD~1", "HEAD")
+	require.NoError(t, err)
+	err = inTransaction(t, ctx, func() error { return db.ApplyDiff(ctx, "k", iter) })
+	require.NoError(t, iter.Close(ctx))
+	require.Error(t, err)
+	assert.True(t, sqle.ErrApplyDiffKeyless.Is(err))
+}
+
+// rowDiffList is a sqle.RowDiffIter over a fixed list of row changes.
+type rowDiffList []struct {
+	diffType string
+	from, to sql.Row
+}
+
+func (l *rowDiffList) Next(*sql.Context) (string, sql.Row, sql.Row, error) {
+	if len(*l) == 0 {
+		return "", nil, nil, io.EOF
+	}
+	c := (*l)[0]
+	*l = (*l)[1:]
+	return c.diffType, c.from, c.to, nil
+}
+
+func TestDatabaseDiff3(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key, c int);",
+		"insert into t values (1, 1), (2, 2), (3, 3), (4, 4);",
+		"call dolt_commit('-Am', 'base');",
+		"call dolt_tag('base');",
+		"call dolt_checkout('-b', 'theirs');",
+		"update t set c = 20 where pk = 2;",
+		"update t set c = 30 where pk = 3;",
+		"insert into t values (5, 5);",
+		"call dolt_commit('-am', 'theirs');",
+		"call dolt_checkout('main');",
+		"update t set c = 10 where pk = 1;",
+		"update t set c = 30 where pk = 3;",
+		"delete from t where pk = 4;",
+		"call dolt_commit('-am', 'ours');",
+		"create table k (c int);",
+		"call dolt_commit('-Am', 'keyless');",
+	)
+	defer engine.Close()
+
+	rows, err := db.Diff3(ctx, "t", "base", "main", "theirs", true)
+	require.NoError(t, err)
+	assert.Equal(t, []sqle.Diff3Row{
+		{Key: sql.Row{int32(1)}, Base: sql.Row{int32(1), int32(1)}, Ours: sql.Row{int32(1), int32(10)}, Theirs: sql.Row{int32(1), int32(1)}},
+		{Key: sql.Row{int32(2)}, Base: sql.Row{int32(2), int32(2)}, Ours: sql.Row{int32(2), int32(2)}, Theirs: sql.Row{int32(2), int32(20)}},
+		{Key: sql.Row{int32(3)}, Base: sql.Row{int32(3), int32(3)}, Ours: sql.Row{int32(3), int32(30)}, Theirs: sql.Row{int32(3), int32(30)}},
+		{Key: sql.Row{int32(4)}, Base: sql.Row{int32(4), int32(4)}, Ours: nil, Theirs: sql.Row{int32(4), int32(4)}},
+		{Key: sql.Row{int32(5)}, Base: nil, Ours: nil, Theirs: sql.Row{int32(5), int32(5)}},
+	}, rows)
+
+	rows, err = db.Diff3(ctx, "T", "base", "main", "theirs", false)
+	require.NoError(t, err)
+	require.Len(t, rows, 5)
+
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into t values (6, 6);")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_commit('-am', 'insert 6');")
+
+	rows, err = db.Diff3(ctx, "t", "main", "main", "main", false)
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	assert.Equal(t, rows[0].Base, rows[0].Theirs)
+
+	rows, err = db.Diff3(ctx, "t", "main", "main", "main", true)
+	require.NoError(t, err)
+	assert.Empty(t, rows)
+
+	_, err = db.Diff3(ctx, "k", "HEAD", "HEAD", "HEAD", true)
+	require.Error(t, err)
+	assert.True(t, sqle.ErrDiff3Keyless.Is(err))
+
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "alter table t add column d int;")
+	_, err = db.Diff3(ctx, "t", "base", "HEAD", "WORKING", true)
+	require.Error(t, err)
+	assert.True(t, sqle.ErrDiff3SchemaChanged.Is(err))
+
+	_, err = db.Diff3(ctx, "missing", "base", "main", "theirs", true)
+	require.Error(t, err)
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+}
+
+func TestDatabaseDiff3DecimalKey(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk decimal(10,2) primary key, c int);",
+		"insert into t values (1.50, 1), (2.50, 2);",
+		"call dolt_commit('-Am', 'base');",
+		"call dolt_tag('base');",
+		"call dolt_checkout('-b', 'theirs');",
+		"update t set c = 20 where pk = 2.50;",
+		"call dolt_commit('-am', 'theirs');",
+		"call dolt_checkout('main');",
+		"update t set c = 10 where pk = 2.50;",
+		"call dolt_commit('-am', 'ours');",
+	)
+	defer engine.Close()
+
+	// the row changed on both sides is matched up between the two diffs
+	rows, err := db.Diff3(ctx, "t", "base", "main", "theirs", true)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "2.50", rows[0].Key[0].(decimal.Decimal).StringFixed(2))
+	assert.Equal(t, int32(2), rows[0].Base[1])
+	assert.Equal(t, int32(10), rows[0].Ours[1])
+	assert.Equal(t, int32(20), rows[0].Theirs[1])
+
+	rows, err = db.Diff3(ctx, "t", "base", "main", "theirs", false)
+	require.NoError(t, err)
+	assert.Len(t, rows, 2)
+}
+
+func TestDatabaseStreamExport(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key, c varchar(20));",
+		"insert into t values (1, 'one'), (2, 'two'), (3, 'three');",
+	)
+	defer engine.Close()
+
+	var buf bytes.Buffer
+	require.NoError(t, db.StreamExport(ctx, "T", &buf, sqle.ExportFormatCSV, sqle.StreamExportOpts{}))
+	assert.Equal(t, "pk,c\n1,one\n2,two\n3,three\n", buf.String())
+
+	buf.Reset()
+	filter := expression.NewGreaterThan(expression.NewGetField(0, types.Int32, "pk", false), expression.NewLiteral(int32(1), types.Int32))
+	require.NoError(t, db.StreamExport(ctx, "t", &buf, sqle.ExportFormatJSON, sqle.StreamExportOpts{Filter: filter}))
+	assert.Equal(t, `{"rows": [{"c":"two","pk":2},{"c":"three","pk":3}]}`, buf.String())
+
+	err := db.StreamExport(ctx, "missing", &buf, sqle.ExportFormatCSV, sqle.StreamExportOpts{})
+	require.Error(t, err)
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+}
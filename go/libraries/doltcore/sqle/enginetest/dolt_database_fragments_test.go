@@ -0,0 +1,298 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enginetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/enginetest"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+)
+
+func TestDatabaseValidateSchemaFragments(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+		"create table other (pk int primary key);",
+		"create view v1 as select * from t;",
+		"create view v2 as select * from v1;",
+		"create view v3 as with cte as (select pk from other) select o.pk from cte join other o on cte.pk = o.pk;",
+		"create trigger trg before insert on other for each row insert into t values (new.pk);",
+	)
+	defer engine.Close()
+
+	fragErrs, err := db.ValidateSchemaFragments(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, fragErrs)
+
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "drop table t;")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into dolt_schemas (type, name, fragment) values ('view', 'broken', 'create view broken as selec 1');")
+
+	fragErrs, err = db.ValidateSchemaFragments(ctx)
+	require.NoError(t, err)
+	require.Len(t, fragErrs, 3)
+
+	assert.Equal(t, "view", fragErrs[0].Type)
+	assert.Equal(t, "broken", fragErrs[0].Name)
+	assert.False(t, sql.ErrTableNotFound.Is(fragErrs[0].Err))
+
+	assert.Equal(t, "view", fragErrs[1].Type)
+	assert.Equal(t, "v1", fragErrs[1].Name)
+	assert.True(t, sql.ErrTableNotFound.Is(fragErrs[1].Err))
+
+	assert.Equal(t, "trigger", fragErrs[2].Type)
+	assert.Equal(t, "trg", fragErrs[2].Name)
+	assert.True(t, sql.ErrTableNotFound.Is(fragErrs[2].Err))
+}
+
+func TestDatabaseGetStoredProcedure(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create procedure other() select 0;",
+		"insert into dolt_procedures values ('p1', 'create definer=`alice`@`localhost` procedure p1() select 1', now(), now(), null);",
+		"insert into dolt_procedures values ('P1', 'create definer=`bob`@`%` procedure P1() select 2', now(), now(), null);",
+	)
+	defer engine.Close()
+
+	spd, ok, err := db.GetStoredProcedure(ctx, "OTHER")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "other", spd.Name)
+
+	_, _, err = db.GetStoredProcedure(ctx, "p1")
+	require.Error(t, err)
+	assert.True(t, sqle.ErrAmbiguousStoredProcedure.Is(err))
+
+	spd, ok, err = db.GetStoredProcedureForDefiner(ctx, "P1", "alice@localhost")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "p1", spd.Name)
+
+	spd, ok, err = db.GetStoredProcedureForDefiner(ctx, "p1", "`bob`@`%`")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "P1", spd.Name)
+
+	_, ok, err = db.GetStoredProcedureForDefiner(ctx, "p1", "carol@localhost")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = db.GetStoredProcedure(ctx, "missing")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestDatabaseGetEventsWithStatus(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key auto_increment);",
+		"create event daily on schedule every 1 day starts '2020-01-01 00:00:00' do insert into t values ();",
+		"create event disabled_hourly on schedule every 1 hour disable do insert into t values ();",
+		"create event once on schedule at '2999-01-01 00:00:00' comment 'far off' do insert into t values ();",
+		"create event ended on schedule every 1 day starts '2020-01-01 00:00:00' ends '2020-02-01 00:00:00' do insert into t values ();",
+	)
+	defer engine.Close()
+
+	events, err := db.GetEventsWithStatus(ctx)
+	require.NoError(t, err)
+	byName := make(map[string]sqle.EventWithStatus)
+	for _, e := range events {
+		byName[e.Name] = e
+	}
+	require.Len(t, byName, 4)
+
+	now := ctx.QueryTime()
+	daily := byName["daily"]
+	assert.True(t, daily.Enabled)
+	assert.Equal(t, "1 DAY", daily.Details.ExecuteEvery)
+	assert.False(t, daily.NextExecution.Before(now))
+	assert.Less(t, daily.NextExecution.Sub(now), 24*time.Hour)
+
+	disabled := byName["disabled_hourly"]
+	assert.False(t, disabled.Enabled)
+	assert.Equal(t, "DISABLE", disabled.Details.Status)
+	assert.True(t, disabled.NextExecution.IsZero())
+
+	once := byName["once"]
+	assert.True(t, once.Enabled)
+	assert.True(t, once.Details.HasExecuteAt)
+	assert.Equal(t, "far off", once.Details.Comment)
+	assert.Equal(t, once.Details.ExecuteAt, once.NextExecution)
+
+	ended := byName["ended"]
+	assert.True(t, ended.Enabled)
+	assert.True(t, ended.NextExecution.IsZero())
+}
+
+func TestDatabaseSavedQueries(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness)
+	defer engine.Close()
+
+	saved, err := db.ListSavedQueries(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, saved)
+
+	require.NoError(t, inTransaction(t, ctx, func() error {
+		return db.SaveQuery(ctx, "one", "select 1", "the first query")
+	}))
+	require.NoError(t, inTransaction(t, ctx, func() error { return db.SaveQuery(ctx, "two", "select 2", "") }))
+
+	err = inTransaction(t, ctx, func() error { return db.SaveQuery(ctx, "one", "select 11", "") })
+	assert.True(t, sqle.ErrSavedQueryExists.Is(err))
+	err = inTransaction(t, ctx, func() error { return db.SaveQuery(ctx, " ", "select 3", "") })
+	assert.True(t, sqle.ErrInvalidSavedQuery.Is(err))
+	err = inTransaction(t, ctx, func() error { return db.SaveQuery(ctx, "three", "", "") })
+	assert.True(t, sqle.ErrInvalidSavedQuery.Is(err))
+
+	saved, err = db.ListSavedQueries(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []dtables.SavedQuery{
+		{ID: "one", Name: "one", Query: "select 1", Description: "the first query", Order: 1},
+		{ID: "two", Name: "two", Query: "select 2", Description: "", Order: 2},
+	}, saved)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select name, query from dolt_query_catalog order by display_order",
+		[]sql.Row{{"one", "select 1"}, {"two", "select 2"}}, nil, nil)
+
+	require.NoError(t, inTransaction(t, ctx, func() error { return db.DeleteSavedQuery(ctx, "one") }))
+	err = inTransaction(t, ctx, func() error { return db.DeleteSavedQuery(ctx, "one") })
+	assert.True(t, dtables.ErrQueryNotFound.Is(err))
+
+	saved, err = db.ListSavedQueries(ctx)
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+	assert.Equal(t, "two", saved[0].Name)
+}
+
+func TestDatabaseDropSchemaObjects(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+		"create view v1 as select 1;",
+		"create view v2 as select 2;",
+		"create trigger trig before insert on t for each row set new.pk = new.pk + 1;",
+	)
+	defer engine.Close()
+
+	err := inTransaction(t, ctx, func() error {
+		return db.DropSchemaObjects(ctx, []sqle.FragSpec{{Type: "view", Name: "v1"}, {Type: "view", Name: "nope"}})
+	})
+	require.Error(t, err)
+	assert.True(t, sql.ErrViewDoesNotExist.Is(err))
+	err = inTransaction(t, ctx, func() error {
+		return db.DropSchemaObjects(ctx, []sqle.FragSpec{{Type: "trigger", Name: "v1"}})
+	})
+	require.Error(t, err)
+	assert.True(t, sql.ErrTriggerDoesNotExist.Is(err))
+	err = inTransaction(t, ctx, func() error {
+		return db.DropSchemaObjects(ctx, []sqle.FragSpec{{Type: "table", Name: "t"}})
+	})
+	require.Error(t, err)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select name from dolt_schemas order by name",
+		[]sql.Row{{"trig"}, {"v1"}, {"v2"}}, nil, nil)
+
+	require.NoError(t, inTransaction(t, ctx, func() error {
+		return db.DropSchemaObjects(ctx, []sqle.FragSpec{{Type: "VIEW", Name: "V1"}, {Type: "view", Name: "v1"}, {Type: "trigger", Name: "trig"}})
+	}))
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select name from dolt_schemas", []sql.Row{{"v2"}}, nil, nil)
+
+	require.NoError(t, inTransaction(t, ctx, func() error {
+		return db.DropSchemaObjects(ctx, []sqle.FragSpec{{Type: "view", Name: "v2"}})
+	}))
+	_, ok, err := db.GetTableInsensitive(ctx, doltdb.SchemasTableName)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestDatabaseCreateViewWithSqlMode(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+		"insert into t values (1), (2);",
+	)
+	defer engine.Close()
+
+	require.NoError(t, inTransaction(t, ctx, func() error {
+		return db.CreateViewWithSqlMode(ctx, "v", `SELECT "pk" FROM t`, `CREATE VIEW v AS SELECT "pk" FROM t`, "ANSI_QUOTES")
+	}))
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from v order by pk", []sql.Row{{1}, {2}}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select sql_mode from dolt_schemas where name = 'v'", []sql.Row{{"ANSI_QUOTES"}}, nil, nil)
+
+	err := inTransaction(t, ctx, func() error {
+		return db.CreateViewWithSqlMode(ctx, "v2", `SELECT "pk" FROM "t"`, `CREATE VIEW v2 AS SELECT "pk" FROM "t"`, "")
+	})
+	require.Error(t, err)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select count(*) from dolt_schemas where name = 'v2'", []sql.Row{{0}}, nil, nil)
+
+	err = inTransaction(t, ctx, func() error {
+		return db.CreateViewWithSqlMode(ctx, "V", "SELECT 1", "CREATE VIEW V AS SELECT 1", "")
+	})
+	require.Error(t, err)
+	assert.True(t, sql.ErrExistingView.Is(err))
+}
+
+func TestDatabaseImportSchemaFragments(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+		"insert into t values (1), (2);",
+		"create view existing as select 1;",
+	)
+	defer engine.Close()
+
+	created := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	importFragments := func(frags []sqle.SchemaFragmentDef) error {
+		return inTransaction(t, ctx, func() error { return db.ImportSchemaFragments(ctx, frags) })
+	}
+
+	err := importFragments([]sqle.SchemaFragmentDef{
+		{Type: "view", Name: "v", CreateStatement: `CREATE VIEW v AS SELECT "pk" FROM t`, SqlMode: "ANSI_QUOTES"},
+		{Type: "trigger", Name: "trig", CreateStatement: "CREATE TRIGGER trig BEFORE INSERT ON t FOR EACH ROW SET new.pk = new.pk * 10", CreatedAt: created},
+		{Type: "event", Name: "ev", CreateStatement: "CREATE EVENT ev ON SCHEDULE EVERY 1 DAY DISABLE DO INSERT INTO t VALUES (100)", CreatedAt: created},
+	})
+	require.NoError(t, err)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select type, name, sql_mode from dolt_schemas where name <> 'existing' order by name", []sql.Row{
+		{"event", "ev", sql.LoadSqlMode(ctx).String()},
+		{"trigger", "trig", sql.LoadSqlMode(ctx).String()},
+		{"view", "v", "ANSI_QUOTES"},
+	}, nil, nil)
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into t values (3)")
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from v order by pk", []sql.Row{{1}, {2}, {30}}, nil, nil)
+
+	// nothing is imported when any fragment is rejected
+	for _, frags := range [][]sqle.SchemaFragmentDef{
+		{{Type: "view", Name: "v2", CreateStatement: "CREATE VIEW v2 AS SELECT 1"}, {Type: "view", Name: "V2", CreateStatement: "CREATE VIEW V2 AS SELECT 2"}},
+		{{Type: "view", Name: "v2", CreateStatement: "CREATE VIEW v2 AS SELECT 1"}, {Type: "view", Name: "EXISTING", CreateStatement: "CREATE VIEW EXISTING AS SELECT 2"}},
+		{{Type: "view", Name: "v2", CreateStatement: "CREATE VIEW v2 AS SELECT 1"}, {Type: "procedure", Name: "p", CreateStatement: "CREATE PROCEDURE p() SELECT 1"}},
+		{{Type: "view", Name: "v2", CreateStatement: "CREATE VIEW v2 AS SELECT 1"}, {Type: "view", Name: "v3", CreateStatement: "CREATE VIEW v3 AS SELEC 1"}},
+	} {
+		require.Error(t, importFragments(frags))
+		enginetest.TestQueryWithContext(t, ctx, engine, harness, "select count(*) from dolt_schemas where name in ('v2', 'v3')", []sql.Row{{0}}, nil, nil)
+	}
+}
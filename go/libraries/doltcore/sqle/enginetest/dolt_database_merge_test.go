@@ -0,0 +1,603 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enginetest
+
+import (
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/enginetest"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestDatabaseConstraintViolations(t *testing.T) {
+	skipOldFormat(t)
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table parent (pk int primary key);",
+		"create table child (pk int primary key, parent_id int, foreign key (parent_id) references parent(pk));",
+		"call dolt_commit('-Am', 'create tables');",
+		"set foreign_key_checks = 0;",
+		"insert into child values (1, 10);",
+		"set foreign_key_checks = 1;",
+		"set @@dolt_force_transaction_commit = 1;",
+		"call dolt_verify_constraints('--all', 'child');",
+	)
+	defer engine.Close()
+
+	violations, err := db.ConstraintViolations(ctx, "child")
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+
+	cv := violations[0]
+	assert.Equal(t, merge.CvType_ForeignKey, cv.Type)
+	assert.Equal(t, sql.Row{int32(1)}, cv.Key)
+	assert.Equal(t, sql.Row{int32(10)}, cv.Value)
+	fk, ok := cv.Info.(merge.FkCVMeta)
+	require.True(t, ok, "unexpected violation info type %T", cv.Info)
+	assert.Equal(t, "parent", fk.ReferencedTable)
+	assert.Equal(t, []string{"parent_id"}, fk.Columns)
+
+	violations, err = db.ConstraintViolations(ctx, "parent")
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+
+	_, err = db.ConstraintViolations(ctx, "missing")
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+}
+
+func TestDatabaseMergeBaseTable(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key, c int);",
+		"insert into t values (1, 1), (2, 2);",
+		"call dolt_commit('-Am', 'creating table t');",
+		"call dolt_branch('other');",
+		"update t set c = 10 where pk = 1;",
+		"create table later (pk int primary key);",
+		"call dolt_commit('-Am', 'changing main');",
+		"call dolt_checkout('other');",
+		"update t set c = 20 where pk = 2;",
+		"call dolt_commit('-am', 'changing other');",
+		"call dolt_checkout('main');",
+	)
+	defer engine.Close()
+
+	tbl, ok, err := db.MergeBaseTable(ctx, "T", "main", "other")
+	require.NoError(t, err)
+	require.True(t, ok)
+	partitions, err := tbl.Partitions(ctx)
+	require.NoError(t, err)
+	rows, err := sql.RowIterToRows(ctx, nil, sql.NewTableRowIter(ctx, tbl, partitions))
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{int32(1), int32(1)}, {int32(2), int32(2)}}, rows)
+
+	_, ok, err = db.MergeBaseTable(ctx, "later", "HEAD", "other")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = db.MergeBaseTable(ctx, "t", "main", "missing")
+	require.Error(t, err)
+}
+
+func TestDatabaseResolveConflicts(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key, c int);",
+		"create table t2 (pk int primary key, c int);",
+		"insert into t values (1, 1), (2, 2), (3, 3);",
+		"insert into t2 values (1, 1);",
+		"call dolt_commit('-Am', 'creating tables');",
+		"call dolt_branch('other');",
+		"update t set c = 10 where pk in (1, 2);",
+		"update t2 set c = 10;",
+		"call dolt_commit('-am', 'main');",
+		"call dolt_checkout('other');",
+		"update t set c = 100 where pk in (1, 2);",
+		"update t2 set c = 100;",
+		"call dolt_commit('-am', 'other');",
+		"call dolt_checkout('main');",
+		"set autocommit = 0;",
+		"call dolt_merge('other');",
+	)
+	defer engine.Close()
+
+	n, err := db.ResolveConflicts(ctx, "T", sqle.ConflictStrategyTheirs)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), n)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from t order by pk;",
+		[]sql.Row{{1, 100}, {2, 100}, {3, 3}}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select count(*) from dolt_conflicts_t;",
+		[]sql.Row{{0}}, nil, nil)
+
+	n, err = db.ResolveConflicts(ctx, "t2", sqle.ConflictStrategyOurs)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), n)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from t2;",
+		[]sql.Row{{1, 10}}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select count(*) from dolt_conflicts;",
+		[]sql.Row{{0}}, nil, nil)
+
+	// nothing left to resolve
+	n, err = db.ResolveConflicts(ctx, "t", sqle.ConflictStrategyOurs)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), n)
+
+	_, err = db.ResolveConflicts(ctx, "missing", sqle.ConflictStrategyOurs)
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+}
+
+func TestDatabaseMergeStatus(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key, c int);",
+		"create table u (pk int primary key);",
+		"call dolt_commit('-Am', 'creating tables');",
+		"call dolt_branch('other');",
+		"insert into t values (1, 1);",
+		"insert into u values (1);",
+		"call dolt_commit('-am', 'main changes');",
+		"call dolt_checkout('other');",
+		"insert into t values (1, 2);",
+		"insert into u values (2);",
+		"call dolt_commit('-am', 'other changes');",
+		"call dolt_checkout('main');",
+	)
+	defer engine.Close()
+
+	status, err := db.MergeStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, sqle.MergeStatus{}, status)
+
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "set autocommit = 0;")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_merge('other');")
+
+	other, _, err := db.ResolveRef(ctx, "other")
+	require.NoError(t, err)
+	status, err = db.MergeStatus(ctx)
+	require.NoError(t, err)
+	assert.True(t, status.Active)
+	assert.Equal(t, "other", status.Source)
+	assert.Equal(t, commitHash(t, other), status.SourceCommit.String())
+	assert.Equal(t, "refs/heads/main", status.Target)
+	assert.Equal(t, []string{"t"}, status.UnmergedTables)
+
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_merge('--abort');")
+	status, err = db.MergeStatus(ctx)
+	require.NoError(t, err)
+	assert.False(t, status.Active)
+}
+
+func TestDatabaseConflictedTables(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key, c int);",
+		"create table u (pk int primary key, c int unique);",
+		"create table v (pk int primary key);",
+		"call dolt_commit('-Am', 'creating tables');",
+		"call dolt_branch('other');",
+		"insert into t values (1, 1);",
+		"insert into u values (1, 1);",
+		"insert into v values (1);",
+		"call dolt_commit('-am', 'main changes');",
+		"call dolt_checkout('other');",
+		"insert into t values (1, 2);",
+		"insert into u values (2, 1);",
+		"insert into v values (2);",
+		"call dolt_commit('-am', 'other changes');",
+		"call dolt_checkout('main');",
+	)
+	defer engine.Close()
+
+	tables, err := db.ConflictedTables(ctx)
+	require.NoError(t, err)
+	assert.True(t, tables.IsEmpty())
+
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "set autocommit = 0;")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_merge('other');")
+
+	tables, err = db.ConflictedTables(ctx)
+	require.NoError(t, err)
+	assert.False(t, tables.IsEmpty())
+	assert.Equal(t, []string{"t"}, tables.DataConflicts)
+	assert.Empty(t, tables.SchemaConflicts)
+	assert.Equal(t, []string{"u"}, tables.ConstraintViolations)
+
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_merge('--abort');")
+	tables, err = db.ConflictedTables(ctx)
+	require.NoError(t, err)
+	assert.True(t, tables.IsEmpty())
+}
+
+func TestDatabasePreviewMerge(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key, c int);",
+		"create table u (pk int primary key);",
+		"call dolt_commit('-Am', 'creating tables');",
+		"call dolt_branch('other');",
+		"insert into t values (1, 1);",
+		"call dolt_commit('-am', 'main changes');",
+		"call dolt_checkout('other');",
+		"insert into t values (1, 2);",
+		"insert into u values (1), (2);",
+		"call dolt_commit('-am', 'other changes');",
+		"call dolt_checkout('main');",
+	)
+	defer engine.Close()
+
+	root, stats, err := db.PreviewMerge(ctx, "other")
+	require.NoError(t, err)
+	require.Contains(t, stats, "t")
+	assert.Equal(t, 1, stats["t"].DataConflicts)
+	require.Contains(t, stats, "u")
+	assert.Equal(t, 2, stats["u"].Adds)
+
+	tbl, ok, err := root.GetTable(ctx, "t")
+	require.NoError(t, err)
+	require.True(t, ok)
+	hasConflicts, err := tbl.HasConflicts(ctx)
+	require.NoError(t, err)
+	assert.True(t, hasConflicts)
+
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from dolt_status", []sql.Row{}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from u", []sql.Row{}, nil, nil)
+	status, err := db.MergeStatus(ctx)
+	require.NoError(t, err)
+	assert.False(t, status.Active)
+
+	_, _, err = db.PreviewMerge(ctx, "nosuchbranch")
+	require.Error(t, err)
+}
+
+func TestDatabaseMergeConflictKeys(t *testing.T) {
+	skipOldFormat(t)
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk1 int, pk2 varchar(10), c int, primary key (pk1, pk2));",
+		"create table u (pk int primary key, c int);",
+		"insert into t values (1, 'a', 0), (2, 'b', 0), (3, 'c', 0);",
+		"call dolt_commit('-Am', 'creating tables');",
+		"call dolt_branch('other');",
+		"update t set c = 1;",
+		"insert into u values (1, 1);",
+		"call dolt_commit('-am', 'main changes');",
+		"call dolt_checkout('other');",
+		"update t set c = 2 where pk1 < 3;",
+		"insert into u values (2, 2);",
+		"call dolt_commit('-am', 'other changes');",
+		"call dolt_checkout('main');",
+	)
+	defer engine.Close()
+
+	conflicts, err := db.MergeConflictKeys(ctx, "other", 0)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]sqle.ConflictKeys{
+		"t": {Keys: []sqle.RowKey{{int32(1), "a"}, {int32(2), "b"}}},
+	}, conflicts)
+
+	conflicts, err = db.MergeConflictKeys(ctx, "other", 1)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]sqle.ConflictKeys{
+		"t": {Keys: []sqle.RowKey{{int32(1), "a"}}, Truncated: true},
+	}, conflicts)
+
+	conflicts, err = db.MergeConflictKeys(ctx, "main", 0)
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from dolt_status", []sql.Row{}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from u", []sql.Row{{1, 1}}, nil, nil)
+}
+
+// rebase runs db.Rebase in its own transaction.
+func rebase(t *testing.T, ctx *sql.Context, db sqle.Database, upstream string, opts sqle.RebaseOpts) (res sqle.RebaseResult, err error) {
+	err = inTransaction(t, ctx, func() error {
+		res, err = db.Rebase(ctx, upstream, opts)
+		return err
+	})
+	return res, err
+}
+
+func TestDatabaseRebase(t *testing.T) {
+	setup := []string{
+		"create table t (pk int primary key, c varchar(20));",
+		"call dolt_commit('-Am', 'creating table t');",
+		"call dolt_branch('feature');",
+		"insert into t values (10, 'ten');",
+		"call dolt_commit('-am', 'main change');",
+		"call dolt_checkout('feature');",
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		harness := newDoltHarness(t)
+		defer harness.Close()
+		engine, ctx, db := newDatabaseTestEngine(t, harness, append(setup,
+			"insert into t values (1, 'one');",
+			"call dolt_commit('-am', 'feature one', '--author', 'Someone Else <someone@example.com>');",
+			"insert into t values (2, 'two');",
+			"call dolt_commit('-am', 'feature two');",
+		)...)
+		defer engine.Close()
+
+		mainHead, err := db.BranchHead(ctx, "main")
+		require.NoError(t, err)
+
+		res, err := rebase(t, ctx, db, "main", sqle.RebaseOpts{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, res.Replayed)
+		assert.Equal(t, 0, res.Skipped)
+		assert.True(t, res.ConflictCommit.IsEmpty())
+
+		featureHead, err := db.BranchHead(ctx, "feature")
+		require.NoError(t, err)
+		assert.Equal(t, featureHead, res.Head)
+
+		enginetest.TestQueryWithContext(t, ctx, engine, harness, "select message from dolt_log limit 3;", []sql.Row{
+			{"feature two"},
+			{"feature one"},
+			{"main change"},
+		}, nil, nil)
+		enginetest.TestQueryWithContext(t, ctx, engine, harness, "select committer, email from dolt_log where message = 'feature one';", []sql.Row{
+			{"Someone Else", "someone@example.com"},
+		}, nil, nil)
+		enginetest.TestQueryWithContext(t, ctx, engine, harness, "select hashof('HEAD~2');", []sql.Row{{mainHead.String()}}, nil, nil)
+		enginetest.TestQueryWithContext(t, ctx, engine, harness, "select pk from t order by pk;", []sql.Row{{1}, {2}, {10}}, nil, nil)
+		enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from dolt_status;", []sql.Row{}, nil, nil)
+
+		// rebasing again does nothing, since the branch now contains main
+		res, err = rebase(t, ctx, db, "main", sqle.RebaseOpts{})
+		require.NoError(t, err)
+		assert.Equal(t, 0, res.Replayed)
+		assert.Equal(t, featureHead, res.Head)
+	})
+
+	t.Run("squash", func(t *testing.T) {
+		harness := newDoltHarness(t)
+		defer harness.Close()
+		engine, ctx, db := newDatabaseTestEngine(t, harness, append(setup,
+			"insert into t values (10, 'ten');",
+			"call dolt_commit('-am', 'same change');",
+			"insert into t values (1, 'one');",
+			"call dolt_commit('-am', 'feature one');",
+			"insert into t values (2, 'two');",
+			"call dolt_commit('-am', 'feature two');",
+		)...)
+		defer engine.Close()
+
+		mainHead, err := db.BranchHead(ctx, "main")
+		require.NoError(t, err)
+
+		res, err := rebase(t, ctx, db, "main", sqle.RebaseOpts{Squash: true})
+		require.NoError(t, err)
+		assert.Equal(t, 2, res.Replayed)
+		assert.Equal(t, 1, res.Skipped)
+
+		enginetest.TestQueryWithContext(t, ctx, engine, harness, "select message from dolt_log limit 2;", []sql.Row{
+			{"feature one\n\nfeature two"},
+			{"main change"},
+		}, nil, nil)
+		enginetest.TestQueryWithContext(t, ctx, engine, harness, "select hashof('HEAD~1');", []sql.Row{{mainHead.String()}}, nil, nil)
+		enginetest.TestQueryWithContext(t, ctx, engine, harness, "select pk from t order by pk;", []sql.Row{{1}, {2}, {10}}, nil, nil)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		harness := newDoltHarness(t)
+		defer harness.Close()
+		engine, ctx, db := newDatabaseTestEngine(t, harness, append(setup,
+			"insert into t values (1, 'one');",
+			"call dolt_commit('-am', 'feature one');",
+			"insert into t values (10, 'TEN');",
+			"call dolt_commit('-am', 'conflicting change');",
+		)...)
+		defer engine.Close()
+
+		head, _, err := db.ResolveRef(ctx, "HEAD")
+		require.NoError(t, err)
+
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into t values (3, 'three');")
+		_, err = rebase(t, ctx, db, "main", sqle.RebaseOpts{})
+		assert.True(t, sqle.ErrRebaseUncommittedChanges.Is(err))
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_reset('--hard');")
+
+		res, err := rebase(t, ctx, db, "main", sqle.RebaseOpts{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, res.Replayed)
+		assert.Equal(t, commitHash(t, head), res.ConflictCommit.String())
+		assert.Equal(t, []string{"t"}, res.ConflictedTables)
+		assert.Equal(t, commitHash(t, head), res.Head.String())
+
+		featureHead, err := db.BranchHead(ctx, "feature")
+		require.NoError(t, err)
+		assert.Equal(t, commitHash(t, head), featureHead.String())
+		enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from dolt_status;", []sql.Row{}, nil, nil)
+	})
+
+	t.Run("branch moved concurrently", func(t *testing.T) {
+		harness := newDoltHarness(t)
+		defer harness.Close()
+		engine, ctx, db := newDatabaseTestEngine(t, harness, append(setup,
+			"insert into t values (1, 'one');",
+			"call dolt_commit('-am', 'feature one');",
+		)...)
+		defer engine.Close()
+
+		ddb := db.DbData().Ddb
+		featureRef := ref.NewBranchRef("feature")
+		var concurrent *doltdb.Commit
+		err := inTransaction(t, ctx, func() error {
+			// another writer commits to the branch after this transaction read its head
+			head, err := ddb.ResolveCommitRef(ctx, featureRef)
+			require.NoError(t, err)
+			root, err := head.GetRootValue(ctx)
+			require.NoError(t, err)
+			_, rootHash, err := ddb.WriteRootValue(ctx, root)
+			require.NoError(t, err)
+			meta, err := datas.NewCommitMeta("Someone Else", "someone@example.com", "concurrent change")
+			require.NoError(t, err)
+			concurrent, err = ddb.Commit(ctx, rootHash, featureRef, meta)
+			require.NoError(t, err)
+
+			_, err = db.Rebase(ctx, "main", sqle.RebaseOpts{})
+			return err
+		})
+		assert.True(t, sqle.ErrRebaseBranchMoved.Is(err))
+
+		featureHead, err := db.BranchHead(ctx, "feature")
+		require.NoError(t, err)
+		assert.Equal(t, commitHash(t, concurrent), featureHead.String())
+		enginetest.TestQueryWithContext(t, ctx, engine, harness, "select message from dolt_log limit 2;", []sql.Row{
+			{"concurrent change"},
+			{"feature one"},
+		}, nil, nil)
+	})
+}
+
+// cherryPick runs db.CherryPick in its own transaction.
+func cherryPick(t *testing.T, ctx *sql.Context, db sqle.Database, commitRef string) (res sqle.CherryPickResult, err error) {
+	err = inTransaction(t, ctx, func() error {
+		res, err = db.CherryPick(ctx, commitRef)
+		return err
+	})
+	return res, err
+}
+
+func TestDatabaseCherryPick(t *testing.T) {
+	setup := []string{
+		"create table t (pk int primary key, c varchar(20));",
+		"insert into t values (1, 'one'), (2, 'two');",
+		"call dolt_commit('-Am', 'creating table t');",
+		"call dolt_checkout('-b', 'feature');",
+		"insert into t values (3, 'three');",
+		"update t set c = 'ONE' where pk = 1;",
+		"delete from t where pk = 2;",
+		"call dolt_commit('-am', 'fix');",
+		"call dolt_checkout('main');",
+	}
+
+	t.Run("apply", func(t *testing.T) {
+		harness := newDoltHarness(t)
+		defer harness.Close()
+		engine, ctx, db := newDatabaseTestEngine(t, harness, setup...)
+		defer engine.Close()
+
+		res, err := cherryPick(t, ctx, db, "feature")
+		require.NoError(t, err)
+		assert.Equal(t, sqle.CherryPickResult{Added: 1, Modified: 1, Deleted: 1}, res)
+
+		enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from t order by pk;", []sql.Row{{1, "ONE"}, {3, "three"}}, nil, nil)
+		enginetest.TestQueryWithContext(t, ctx, engine, harness, "select table_name, staged from dolt_status;", []sql.Row{{"t", false}}, nil, nil)
+		enginetest.TestQueryWithContext(t, ctx, engine, harness, "select message from dolt_log limit 1;", []sql.Row{{"creating table t"}}, nil, nil)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		harness := newDoltHarness(t)
+		defer harness.Close()
+		engine, ctx, db := newDatabaseTestEngine(t, harness, append(setup,
+			"update t set c = 'uno' where pk = 1;",
+			"set @@dolt_allow_commit_conflicts = 1;",
+		)...)
+		defer engine.Close()
+
+		res, err := cherryPick(t, ctx, db, "feature")
+		require.NoError(t, err)
+		assert.Equal(t, 1, res.DataConflicts)
+		assert.Equal(t, []string{"t"}, res.ConflictedTables)
+
+		enginetest.TestQueryWithContext(t, ctx, engine, harness, "select our_c, their_c from dolt_conflicts_t;", []sql.Row{{"uno", "ONE"}}, nil, nil)
+		enginetest.TestQueryWithContext(t, ctx, engine, harness, "select is_merging from dolt_merge_status;", []sql.Row{{true}}, nil, nil)
+
+		_, err = cherryPick(t, ctx, db, "feature")
+		assert.ErrorIs(t, err, doltdb.ErrMergeActive)
+	})
+}
+
+func TestDatabaseRetainMergeInputs(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+		"call dolt_commit('-Am', 'create table');",
+		"call dolt_branch('b1');",
+		"call dolt_branch('b2');",
+		"call dolt_branch('b3');",
+		"call dolt_checkout('b1');",
+		"insert into t values (1);",
+		"call dolt_commit('-am', 'b1');",
+		"call dolt_checkout('b2');",
+		"insert into t values (2);",
+		"call dolt_commit('-am', 'b2');",
+		"call dolt_checkout('b3');",
+		"insert into t values (3);",
+		"call dolt_commit('-am', 'b3');",
+		"call dolt_checkout('main');",
+		"insert into t values (0);",
+		"call dolt_commit('-am', 'main');",
+	)
+	defer engine.Close()
+	ddb := db.DbData().Ddb
+
+	defer func(max int) { merge.MaxRetainedMerges = max }(merge.MaxRetainedMerges)
+	merge.MaxRetainedMerges = 1
+
+	hashOf := func(branch string) hash.Hash {
+		cm, err := ddb.ResolveCommitRef(ctx, ref.NewBranchRef(branch))
+		require.NoError(t, err)
+		h, err := cm.HashOf()
+		require.NoError(t, err)
+		return h
+	}
+	retained := func() []merge.RetainedMerge {
+		merges, err := merge.RetainedMerges(ctx, ddb)
+		require.NoError(t, err)
+		return merges
+	}
+
+	ours1, theirs1 := hashOf("main"), hashOf("b1")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_merge('--no-gc-hint', 'b1');")
+	assert.Equal(t, []merge.RetainedMerge{{Ours: ours1, Theirs: theirs1}}, retained())
+
+	// retaining the inputs of another merge releases the oldest ones beyond MaxRetainedMerges
+	ours2, theirs2 := hashOf("main"), hashOf("b2")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_merge('--no-gc-hint', 'b2');")
+	assert.Equal(t, []merge.RetainedMerge{{Ours: ours2, Theirs: theirs2}}, retained())
+	_, err := ddb.ResolveCommitRef(ctx, ref.NewInternalRef("merge/"+ours1.String()+"/"+theirs1.String()+"/base"))
+	assert.Error(t, err)
+
+	// a merge that isn't committed could be rolled back, so its inputs aren't retained
+	ctx.ClearWarnings()
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_merge('--no-gc-hint', '--no-commit', 'b3');")
+	assert.Len(t, ctx.Warnings(), 1)
+	assert.Equal(t, []merge.RetainedMerge{{Ours: ours2, Theirs: theirs2}}, retained())
+
+	require.NoError(t, merge.ReleaseMergeInputs(ctx, ddb, ours2, theirs2))
+	assert.Empty(t, retained())
+	require.NoError(t, merge.ReleaseMergeInputs(ctx, ddb, ours2, theirs2))
+}
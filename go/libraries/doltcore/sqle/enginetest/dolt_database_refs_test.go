@@ -0,0 +1,791 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enginetest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/enginetest"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestDatabaseResolveRef(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+		"call dolt_add('-A');",
+		"call dolt_commit('-m', 'creating table t');",
+		"call dolt_tag('v1');",
+		"call dolt_branch('feature');",
+		"insert into t values (1);",
+		"call dolt_commit('-am', 'added a row');",
+		"insert into t values (2);",
+	)
+	defer engine.Close()
+
+	head, headRoot, err := db.ResolveRef(ctx, "HEAD")
+	require.NoError(t, err)
+	branch, _, err := db.ResolveRef(ctx, "main")
+	require.NoError(t, err)
+	assert.Equal(t, commitHash(t, head), commitHash(t, branch))
+
+	parent, _, err := db.ResolveRef(ctx, "HEAD~1")
+	require.NoError(t, err)
+	tagged, taggedRoot, err := db.ResolveRef(ctx, "v1")
+	require.NoError(t, err)
+	assert.Equal(t, commitHash(t, parent), commitHash(t, tagged))
+
+	byHash, _, err := db.ResolveRef(ctx, commitHash(t, parent))
+	require.NoError(t, err)
+	assert.Equal(t, commitHash(t, parent), commitHash(t, byHash))
+
+	has, err := taggedRoot.HasTable(ctx, "t")
+	require.NoError(t, err)
+	assert.True(t, has)
+
+	working, workingRoot, err := db.ResolveRef(ctx, "WORKING")
+	require.NoError(t, err)
+	assert.Equal(t, commitHash(t, head), commitHash(t, working))
+	workingHash, err := workingRoot.HashOf()
+	require.NoError(t, err)
+	headRootHash, err := headRoot.HashOf()
+	require.NoError(t, err)
+	assert.NotEqual(t, headRootHash, workingHash)
+
+	_, _, err = db.ResolveRef(ctx, "doesnotexist")
+	assert.Error(t, err)
+
+	// ancestor specs apply to the commit a tag points at
+	beforeHead, _, err := db.ResolveRef(ctx, "HEAD~2")
+	require.NoError(t, err)
+	beforeTag, _, err := db.ResolveRef(ctx, "v1~1")
+	require.NoError(t, err)
+	assert.Equal(t, commitHash(t, beforeHead), commitHash(t, beforeTag))
+	_, _, err = db.ResolveRef(ctx, "v2~1")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, doltdb.ErrBranchNotFound))
+
+	_, _, err = db.ResolveRef(ctx, "HEAD@{1}")
+	require.Error(t, err)
+	assert.True(t, sqle.ErrReflogNotSupported.Is(err))
+
+	// a remote branch named working is resolved as a remote ref, unless there's also a branch named after the remote
+	ddb := db.DbData().Ddb
+	require.NoError(t, ddb.SetHeadToCommit(ctx, ref.NewRemoteRef("origin", "working"), tagged))
+	remote, _, err := db.ResolveRef(ctx, "origin/working")
+	require.NoError(t, err)
+	assert.Equal(t, commitHash(t, tagged), commitHash(t, remote))
+	_, featureWorking, err := db.ResolveRef(ctx, "feature/working")
+	require.NoError(t, err)
+	assert.Equal(t, rootHash(t, taggedRoot), rootHash(t, featureWorking))
+	require.NoError(t, ddb.SetHeadToCommit(ctx, ref.NewRemoteRef("feature", "working"), tagged))
+	_, _, err = db.ResolveRef(ctx, "feature/working")
+	assert.True(t, errors.Is(err, dsess.ErrAmbiguousBranchWorkingRef))
+}
+
+func TestHashOfRemoteRef(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+		"call dolt_commit('-Am', 'creating table t');",
+		"insert into t values (1);",
+		"call dolt_commit('-am', 'added a row');",
+	)
+	defer engine.Close()
+
+	parent, _, err := db.ResolveRef(ctx, "HEAD~1")
+	require.NoError(t, err)
+	grandparent, _, err := db.ResolveRef(ctx, "HEAD~2")
+	require.NoError(t, err)
+	require.NoError(t, db.DbData().Ddb.SetHeadToCommit(ctx, ref.NewRemoteRef("origin", "main"), parent))
+
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select hashof('remotes/origin/main');",
+		[]sql.Row{{commitHash(t, parent)}}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select hashof('remotes/origin/main~1');",
+		[]sql.Row{{commitHash(t, grandparent)}}, nil, nil)
+	enginetest.AssertErrWithCtx(t, engine, harness, ctx, "select hashof('remotes/origin/missing');", nil)
+}
+
+func TestDatabaseRootForCommit(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+		"call dolt_commit('-Am', 'creating table t');",
+		"call dolt_tag('v1');",
+		"insert into t values (1);",
+		"call dolt_commit('-am', 'added a row');",
+		"insert into t values (2);",
+		"call dolt_add('t');",
+		"insert into t values (3);",
+	)
+	defer engine.Close()
+
+	rootHash := func(ref string) hash.Hash {
+		root, err := db.RootForCommit(ctx, ref)
+		require.NoError(t, err)
+		h, err := root.HashOf()
+		require.NoError(t, err)
+		return h
+	}
+
+	_, headRoot, err := db.ResolveRef(ctx, "HEAD")
+	require.NoError(t, err)
+	headRootHash, err := headRoot.HashOf()
+	require.NoError(t, err)
+	assert.Equal(t, headRootHash, rootHash("HEAD"))
+	assert.Equal(t, headRootHash, rootHash("main"))
+	assert.Equal(t, rootHash("v1"), rootHash("HEAD~1"))
+	assert.Equal(t, rootHash("v1"), rootHash("main^"))
+
+	parent, _, err := db.ResolveRef(ctx, "HEAD~1")
+	require.NoError(t, err)
+	assert.Equal(t, rootHash("v1"), rootHash(commitHash(t, parent)))
+
+	stagedHash := rootHash("STAGED")
+	assert.NotEqual(t, headRootHash, stagedHash)
+	assert.NotEqual(t, stagedHash, rootHash("WORKING"))
+
+	_, err = db.RootForCommit(ctx, "not a ref")
+	assert.True(t, sqle.ErrInvalidCommitRef.Is(err), "unexpected error %v", err)
+	_, err = db.RootForCommit(ctx, "HEAD~x")
+	assert.True(t, sqle.ErrInvalidCommitRef.Is(err), "unexpected error %v", err)
+	_, err = db.RootForCommit(ctx, "")
+	assert.True(t, sqle.ErrInvalidCommitRef.Is(err), "unexpected error %v", err)
+	_, err = db.RootForCommit(ctx, "doesnotexist")
+	assert.True(t, sqle.ErrCommitRefNotFound.Is(err), "unexpected error %v", err)
+	_, err = db.RootForCommit(ctx, "u8s83gapv7ghnbmrtpm8q5es0dbl7lpd")
+	assert.True(t, sqle.ErrCommitRefNotFound.Is(err), "unexpected error %v", err)
+	_, err = db.RootForCommit(ctx, "HEAD~5")
+	assert.True(t, sqle.ErrCommitAncestorNotFound.Is(err), "unexpected error %v", err)
+	_, err = db.RootForCommit(ctx, "HEAD@{1}")
+	assert.True(t, sqle.ErrReflogNotSupported.Is(err), "unexpected error %v", err)
+}
+
+func TestDatabaseBranchHead(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+		"call dolt_commit('-Am', 'creating table t');",
+		"call dolt_branch('Other');",
+		"insert into t values (1);",
+		"call dolt_commit('-am', 'inserting a row');",
+	)
+	defer engine.Close()
+
+	mainHead, _, err := db.ResolveRef(ctx, "main")
+	require.NoError(t, err)
+	otherHead, _, err := db.ResolveRef(ctx, "Other")
+	require.NoError(t, err)
+
+	h, err := db.BranchHead(ctx, "main")
+	require.NoError(t, err)
+	assert.Equal(t, commitHash(t, mainHead), h.String())
+
+	h, err = db.BranchHead(ctx, "other")
+	require.NoError(t, err)
+	assert.Equal(t, commitHash(t, otherHead), h.String())
+	assert.NotEqual(t, commitHash(t, mainHead), h.String())
+
+	_, err = db.BranchHead(ctx, "missing")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, doltdb.ErrBranchNotFound))
+}
+
+func TestDatabaseCreateBranch(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+		"call dolt_commit('-Am', 'creating table t');",
+		"insert into t values (1);",
+		"call dolt_commit('-am', 'inserting a row');",
+	)
+	defer engine.Close()
+
+	exists, err := db.BranchExists(ctx, "MAIN")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = db.BranchExists(ctx, "feature")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, db.CreateBranch(ctx, "feature", "HEAD~1"))
+	exists, err = db.BranchExists(ctx, "feature")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	parent, _, err := db.ResolveRef(ctx, "HEAD~1")
+	require.NoError(t, err)
+	h, err := db.BranchHead(ctx, "feature")
+	require.NoError(t, err)
+	assert.Equal(t, commitHash(t, parent), h.String())
+
+	// the start point defaults to HEAD
+	require.NoError(t, db.CreateBranch(ctx, "other", ""))
+	head, _, err := db.ResolveRef(ctx, "HEAD")
+	require.NoError(t, err)
+	h, err = db.BranchHead(ctx, "other")
+	require.NoError(t, err)
+	assert.Equal(t, commitHash(t, head), h.String())
+
+	err = db.CreateBranch(ctx, "Feature", "HEAD")
+	assert.True(t, sqle.ErrBranchAlreadyExists.Is(err))
+	err = db.CreateBranch(ctx, "bad..name", "HEAD")
+	assert.Equal(t, doltdb.ErrInvBranchName, err)
+	err = db.CreateBranch(ctx, "new", "missing")
+	assert.Error(t, err)
+	exists, err = db.BranchExists(ctx, "new")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestDatabaseCommitGraph(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+		"call dolt_commit('-Am', 'creating table t');",
+		"call dolt_branch('other');",
+		"insert into t values (1);",
+		"call dolt_commit('-am', 'main');",
+		"call dolt_checkout('other');",
+		"insert into t values (2);",
+		"call dolt_commit('-am', 'other');",
+		"call dolt_checkout('main');",
+		"call dolt_merge('other', '--no-ff', '-m', 'merge other');",
+	)
+	defer engine.Close()
+
+	hashOf := func(ref string) hash.Hash {
+		cm, _, err := db.ResolveRef(ctx, ref)
+		require.NoError(t, err)
+		h, err := cm.HashOf()
+		require.NoError(t, err)
+		return h
+	}
+	mergeCm, mainCm, otherCm, createCm := hashOf("HEAD"), hashOf("HEAD~1"), hashOf("other"), hashOf("HEAD~2")
+
+	collect := func(heads []string, maxDepth int, includeMeta bool) []sqle.CommitGraphNode {
+		itr, err := db.CommitGraph(ctx, heads, maxDepth, includeMeta)
+		require.NoError(t, err)
+		var nodes []sqle.CommitGraphNode
+		for {
+			node, err := itr.Next(ctx)
+			if err == io.EOF {
+				return nodes
+			}
+			require.NoError(t, err)
+			nodes = append(nodes, node)
+		}
+	}
+
+	nodes := collect([]string{"HEAD"}, 1, true)
+	require.Len(t, nodes, 3)
+	assert.Equal(t, mergeCm, nodes[0].Commit)
+	assert.Equal(t, []hash.Hash{mainCm, otherCm}, nodes[0].Parents)
+	assert.Equal(t, 0, nodes[0].Depth)
+	assert.Equal(t, "merge other", nodes[0].Meta.Description)
+	assert.ElementsMatch(t, []hash.Hash{mainCm, otherCm}, []hash.Hash{nodes[1].Commit, nodes[2].Commit})
+	assert.Equal(t, 1, nodes[1].Depth)
+	assert.Equal(t, 1, nodes[2].Depth)
+	assert.Equal(t, []hash.Hash{createCm}, nodes[1].Parents)
+
+	// the whole history, down to the commit that created the database, after the harness's checkpoint commit
+	nodes = collect([]string{"HEAD"}, -1, false)
+	require.Len(t, nodes, 6)
+	assert.Nil(t, nodes[0].Meta)
+	assert.Equal(t, createCm, nodes[3].Commit)
+	assert.Equal(t, 2, nodes[3].Depth)
+	assert.Equal(t, 4, nodes[5].Depth)
+	assert.Empty(t, nodes[5].Parents)
+
+	// depths are measured from the closest head
+	nodes = collect([]string{"HEAD", "other"}, 0, false)
+	require.Len(t, nodes, 2)
+	assert.Equal(t, mergeCm, nodes[0].Commit)
+	assert.Equal(t, otherCm, nodes[1].Commit)
+	assert.Equal(t, 0, nodes[1].Depth)
+
+	_, err := db.CommitGraph(ctx, []string{"missing"}, -1, false)
+	assert.Error(t, err)
+}
+
+func TestDatabaseWalkHistory(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+		"call dolt_commit('-Am', 'creating table t');",
+		"insert into t values (1);",
+		"call dolt_commit('-am', 'inserting 1');",
+		"insert into t values (2);",
+		"call dolt_commit('-am', 'inserting 2');",
+	)
+	defer engine.Close()
+
+	var messages []string
+	collect := func(cm *doltdb.Commit) (bool, error) {
+		meta, err := cm.GetCommitMeta(ctx)
+		if err != nil {
+			return true, err
+		}
+		messages = append(messages, meta.Description)
+		return false, nil
+	}
+
+	require.NoError(t, db.WalkHistory(ctx, "HEAD~1", collect))
+	assert.Equal(t, []string{"inserting 1", "creating table t", "checkpoint enginetest database mydb", "Initialize data repository"}, messages)
+
+	// stopping early
+	messages = nil
+	err := db.WalkHistory(ctx, "main", func(cm *doltdb.Commit) (bool, error) {
+		_, err := collect(cm)
+		return len(messages) == 2, err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"inserting 2", "inserting 1"}, messages)
+
+	visitErr := errors.New("visit failed")
+	err = db.WalkHistory(ctx, "HEAD", func(cm *doltdb.Commit) (bool, error) {
+		return false, visitErr
+	})
+	assert.Equal(t, visitErr, err)
+
+	subCtx, cancel := ctx.NewSubContext()
+	visited := 0
+	err = db.WalkHistory(subCtx, "HEAD", func(cm *doltdb.Commit) (bool, error) {
+		visited++
+		cancel()
+		return false, nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, visited)
+
+	err = db.WalkHistory(ctx, "missing", collect)
+	assert.Error(t, err)
+}
+
+func TestDatabaseDetachHead(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+		"insert into t values (1);",
+		"call dolt_commit('-Am', 'first');",
+		"insert into t values (2);",
+		"call dolt_commit('-am', 'second');",
+	)
+	defer engine.Close()
+
+	detached, err := db.IsDetachedHead(ctx)
+	require.NoError(t, err)
+	assert.False(t, detached)
+
+	first, _, err := db.ResolveRef(ctx, "HEAD~1")
+	require.NoError(t, err)
+	sqlDb, err := db.DetachHead(ctx, "HEAD~1")
+	require.NoError(t, err)
+	assert.Equal(t, commitHash(t, first), sqlDb.Revision())
+	ro, ok := sqlDb.(sqle.ReadOnlyDatabase)
+	require.True(t, ok, "unexpected database type %T", sqlDb)
+	assert.True(t, ro.IsReadOnly())
+
+	detached, err = ro.IsDetachedHead(ctx)
+	require.NoError(t, err)
+	assert.True(t, detached)
+	_, err = ro.GetWorkingSet(ctx)
+	assert.Equal(t, doltdb.ErrOperationNotSupportedInDetachedHead, err)
+
+	tbl, ok, err := ro.GetTableInsensitive(ctx, "t")
+	require.NoError(t, err)
+	require.True(t, ok)
+	partitions, err := tbl.Partitions(ctx)
+	require.NoError(t, err)
+	rows, err := sql.RowIterToRows(ctx, nil, sql.NewTableRowIter(ctx, tbl, partitions))
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{int32(1)}}, rows)
+
+	sqlDb, err = ro.ReattachHead(ctx, "main")
+	require.NoError(t, err)
+	reattached, ok := sqlDb.(sqle.Database)
+	require.True(t, ok, "unexpected database type %T", sqlDb)
+	detached, err = reattached.IsDetachedHead(ctx)
+	require.NoError(t, err)
+	assert.False(t, detached)
+
+	_, err = db.ReattachHead(ctx, "missing")
+	assert.Equal(t, doltdb.ErrBranchNotFound, err)
+	_, err = db.DetachHead(ctx, "missing")
+	assert.Error(t, err)
+}
+
+func TestDatabaseTags(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+		"call dolt_commit('-Am', 'creating table t');",
+		"call dolt_tag('-m', 'first release', 'v1.0');",
+		"insert into t values (1);",
+		"call dolt_commit('-am', 'inserting a row');",
+		"call dolt_tag('-m', 'second release', 'v1.1');",
+		"call dolt_tag('-m', 'nightly', 'nightly/1');",
+	)
+	defer engine.Close()
+
+	first, _, err := db.ResolveRef(ctx, "HEAD~1")
+	require.NoError(t, err)
+	second, _, err := db.ResolveRef(ctx, "HEAD")
+	require.NoError(t, err)
+
+	tags, err := db.Tags(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, tags, 3)
+	for i := 1; i < len(tags); i++ {
+		assert.False(t, tags[i].Date.Before(tags[i-1].Date))
+	}
+
+	tags, err = db.Tags(ctx, "v1.*")
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+	assert.Equal(t, "v1.0", tags[0].Name)
+	assert.Equal(t, commitHash(t, first), tags[0].Hash.String())
+	assert.Equal(t, "first release", tags[0].Message)
+	assert.NotEmpty(t, tags[0].Tagger)
+	assert.Equal(t, "v1.1", tags[1].Name)
+	assert.Equal(t, commitHash(t, second), tags[1].Hash.String())
+	assert.Equal(t, "second release", tags[1].Message)
+
+	tags, err = db.Tags(ctx, "nightly/*")
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, "nightly/1", tags[0].Name)
+
+	tags, err = db.Tags(ctx, "v2*")
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+
+	_, err = db.Tags(ctx, "[")
+	require.Error(t, err)
+}
+
+func TestDatabaseAvailableRevisions(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+		"call dolt_commit('-Am', 'creating table t');",
+		"call dolt_tag('v1');",
+		"call dolt_branch('feature');",
+		"insert into t values (1);",
+		"call dolt_commit('-am', 'inserting a row');",
+	)
+	defer engine.Close()
+
+	first, _, err := db.ResolveRef(ctx, "HEAD~1")
+	require.NoError(t, err)
+	second, _, err := db.ResolveRef(ctx, "HEAD")
+	require.NoError(t, err)
+	firstHash, secondHash := commitHash(t, first), commitHash(t, second)
+
+	revisions, err := db.AvailableRevisions(ctx)
+	require.NoError(t, err)
+
+	type revision struct {
+		name string
+		typ  dsess.RevisionType
+		hash string
+	}
+	var got []revision
+	for _, r := range revisions {
+		got = append(got, revision{name: r.Name, typ: r.Type, hash: r.Hash.String()})
+	}
+
+	commits := []revision{
+		{name: firstHash, typ: dsess.RevisionTypeCommit, hash: firstHash},
+		{name: secondHash, typ: dsess.RevisionTypeCommit, hash: secondHash},
+	}
+	if secondHash < firstHash {
+		commits[0], commits[1] = commits[1], commits[0]
+	}
+	expected := append([]revision{
+		{name: "feature", typ: dsess.RevisionTypeBranch, hash: firstHash},
+		{name: "main", typ: dsess.RevisionTypeBranch, hash: secondHash},
+		{name: "v1", typ: dsess.RevisionTypeTag, hash: firstHash},
+	}, commits...)
+	assert.Equal(t, expected, got)
+}
+
+func TestDatabaseCommitsTouchingColumn(t *testing.T) {
+	skipOldFormat(t)
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key, salary int, name varchar(20));",
+		"call dolt_commit('-Am', 'create');",
+		"insert into t values (1, 100, 'one');",
+		"call dolt_commit('-am', 'insert');",
+		"update t set name = 'uno' where pk = 1;",
+		"call dolt_commit('-am', 'rename');",
+		"update t set salary = 200 where pk = 1;",
+		"call dolt_commit('-am', 'raise');",
+		"alter table t modify column salary bigint;",
+		"call dolt_commit('-am', 'widen');",
+		"create table other (pk int primary key);",
+		"call dolt_commit('-Am', 'other');",
+	)
+	defer engine.Close()
+
+	messages := func(commits []*doltdb.Commit) []string {
+		var msgs []string
+		for _, cm := range commits {
+			meta, err := cm.GetCommitMeta(ctx)
+			require.NoError(t, err)
+			msgs = append(msgs, meta.Description)
+		}
+		return msgs
+	}
+
+	// the walk starts at the head commit seen by the current transaction, so each call runs in a new one, the way the
+	// engine runs each query
+	touching := func(columnName string, maxDepth int) (commits []*doltdb.Commit, err error) {
+		err = inTransaction(t, ctx, func() error {
+			commits, err = db.CommitsTouchingColumn(ctx, "t", columnName, maxDepth)
+			return err
+		})
+		return commits, err
+	}
+
+	commits, err := touching("SALARY", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"widen", "raise", "insert", "create"}, messages(commits))
+
+	commits, err = touching("name", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rename", "insert", "create"}, messages(commits))
+
+	commits, err = touching("salary", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"widen"}, messages(commits))
+
+	_, err = touching("bonus", 0)
+	assert.True(t, sql.ErrTableColumnNotFound.Is(err))
+}
+
+func TestDatabaseGetRootHash(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+	)
+	defer engine.Close()
+
+	h1, err := db.GetRootHash(ctx)
+	require.NoError(t, err)
+	root, err := db.GetRoot(ctx)
+	require.NoError(t, err)
+	expected, err := root.HashOf()
+	require.NoError(t, err)
+	assert.Equal(t, expected, h1)
+
+	h2, err := db.GetRootHash(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, h1, h2)
+
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into t values (1);")
+	h3, err := db.GetRootHash(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, h1, h3)
+}
+
+func TestDatabaseRowHistory(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key, c varchar(20));",
+		"call dolt_commit('-Am', 'creating t');",
+		"insert into t values (1, 'one'), (2, 'two');",
+		"call dolt_commit('-am', 'adding rows');",
+		"update t set c = 'uno' where pk = 1;",
+		"call dolt_commit('-am', 'changing row 1');",
+		"update t set c = 'dos' where pk = 2;",
+		"call dolt_commit('-am', 'changing row 2');",
+		"delete from t where pk = 1;",
+		"call dolt_commit('-am', 'removing row 1');",
+		"create table k (c int);",
+	)
+	defer engine.Close()
+
+	versions, err := db.RowHistory(ctx, "T", []interface{}{1})
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	assert.Equal(t, "added", versions[0].DiffType)
+	assert.Equal(t, sql.Row{int32(1), "one"}, versions[0].Row)
+	assert.Equal(t, "modified", versions[1].DiffType)
+	assert.Equal(t, sql.Row{int32(1), "uno"}, versions[1].Row)
+	assert.Equal(t, "removed", versions[2].DiffType)
+	assert.Nil(t, versions[2].Row)
+
+	versions, err = db.RowHistory(ctx, "t", []interface{}{2})
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, "added", versions[0].DiffType)
+	assert.Equal(t, "modified", versions[1].DiffType)
+	assert.Equal(t, sql.Row{int32(2), "dos"}, versions[1].Row)
+
+	head, err := dsess.DSessFromSess(ctx.Session).GetHeadCommit(ctx, "mydb")
+	require.NoError(t, err)
+	parent, err := head.GetParent(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, commitHash(t, parent), versions[1].CommitHash.String())
+
+	versions, err = db.RowHistory(ctx, "t", []interface{}{3})
+	require.NoError(t, err)
+	assert.Empty(t, versions)
+
+	_, err = db.RowHistory(ctx, "t", []interface{}{1, 2})
+	require.Error(t, err)
+	assert.True(t, sqle.ErrRowHistoryKeyLength.Is(err))
+
+	_, err = db.RowHistory(ctx, "k", []interface{}{1})
+	require.Error(t, err)
+	assert.True(t, sqle.ErrRowHistoryKeyless.Is(err))
+
+	_, err = db.RowHistory(ctx, "missing", []interface{}{1})
+	require.Error(t, err)
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+}
+
+func TestDatabaseFsck(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key, c int, index c_idx (c));",
+		"insert into t values (1, 10), (2, 20), (3, 30), (4, 40);",
+		"create table u (pk int primary key, c varchar(20), unique key (c));",
+		"insert into u values (1, 'one');",
+		"create view v as select * from u;",
+	)
+	defer engine.Close()
+
+	// t, u and dolt_schemas
+	report, err := db.Fsck(ctx, sqle.FsckOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.TablesChecked)
+	assert.Empty(t, report.Problems)
+
+	report, err = db.Fsck(ctx, sqle.FsckOpts{Tables: []string{"T"}, SampleRows: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.TablesChecked)
+	assert.Empty(t, report.Problems)
+
+	_, err = db.Fsck(ctx, sqle.FsckOpts{Tables: []string{"missing"}})
+	require.Error(t, err)
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from dolt_fsck()", []sql.Row{}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from dolt_fsck('t', 'u')", []sql.Row{}, nil, nil)
+	enginetest.AssertErrWithCtx(t, engine, harness, ctx, "select * from dolt_fsck('missing')", sql.ErrTableNotFound)
+
+	// break the view on u, then replace the secondary index of t with an empty one. The engine validates indexes after
+	// each query, so no queries can be run once the index is broken.
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "drop table u;")
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select table_name, index_name from dolt_fsck()",
+		[]sql.Row{{doltdb.SchemasTableName, nil}}, nil, nil)
+	err = inTransaction(t, ctx, func() error {
+		root, err := db.GetRoot(ctx)
+		require.NoError(t, err)
+		tbl, ok, err := root.GetTable(ctx, "t")
+		require.NoError(t, err)
+		require.True(t, ok)
+		sch, err := tbl.GetSchema(ctx)
+		require.NoError(t, err)
+		empty, err := durable.NewEmptyIndex(ctx, tbl.ValueReadWriter(), tbl.NodeStore(), sch.Indexes().GetByName("c_idx").Schema())
+		require.NoError(t, err)
+		tbl, err = tbl.SetIndexRows(ctx, "c_idx", empty)
+		require.NoError(t, err)
+		root, err = root.PutTable(ctx, "t", tbl)
+		require.NoError(t, err)
+		return db.SetRoot(ctx, root)
+	})
+	require.NoError(t, err)
+
+	report, err = db.Fsck(ctx, sqle.FsckOpts{})
+	require.NoError(t, err)
+	require.Len(t, report.Problems, 2)
+	assert.Equal(t, "t", report.Problems[0].Table)
+	assert.Equal(t, "c_idx", report.Problems[0].Index)
+	assert.Equal(t, doltdb.SchemasTableName, report.Problems[1].Table)
+	assert.Empty(t, report.Problems[1].Index)
+
+	report, err = db.Fsck(ctx, sqle.FsckOpts{Tables: []string{"t"}, SampleRows: 1})
+	require.NoError(t, err)
+	require.Len(t, report.Problems, 1)
+	assert.Equal(t, "c_idx", report.Problems[0].Index)
+}
+
+func TestDatabaseRootForWorkingSetHash(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+		"call dolt_commit('-Am', 'creating table');",
+		"insert into t values (1);",
+	)
+	defer engine.Close()
+
+	wsRef, err := ref.WorkingSetRefForHead(ref.NewBranchRef("main"))
+	require.NoError(t, err)
+	ws, err := db.GetDoltDB().ResolveWorkingSet(ctx, wsRef)
+	require.NoError(t, err)
+	wsHash, err := ws.HashOf()
+	require.NoError(t, err)
+
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into t values (2);")
+
+	root, err := db.RootForWorkingSetHash(ctx, wsHash)
+	require.NoError(t, err)
+	tbl, ok, err := root.GetTable(ctx, "t")
+	require.NoError(t, err)
+	require.True(t, ok)
+	rows, err := tbl.GetRowData(ctx)
+	require.NoError(t, err)
+	count, err := rows.Count()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+
+	head, err := dsess.DSessFromSess(ctx.Session).GetHeadCommit(ctx, "mydb")
+	require.NoError(t, err)
+	headHash, err := head.HashOf()
+	require.NoError(t, err)
+	_, err = db.RootForWorkingSetHash(ctx, headHash)
+	require.Error(t, err)
+	assert.True(t, sqle.ErrNotWorkingSetHash.Is(err))
+}
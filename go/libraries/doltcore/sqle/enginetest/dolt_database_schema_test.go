@@ -0,0 +1,774 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enginetest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/enginetest"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	storetypes "github.com/dolthub/dolt/go/store/types"
+)
+
+func TestDatabaseTableSchemaJSON(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table parent (pk int primary key, v varchar(20) collate utf8mb4_0900_ai_ci default 'x');",
+		"create table child (pk int primary key auto_increment, parent_id int not null, c1 int, index c1_idx (c1), constraint fk_parent foreign key (parent_id) references parent(pk), check (c1 > 0));",
+	)
+	defer engine.Close()
+
+	exported, err := db.TableSchemaJSON(ctx, "CHILD")
+	require.NoError(t, err)
+
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "drop table child;")
+	require.NoError(t, inTransaction(t, ctx, func() error { return db.CreateTableFromSchemaJSON(ctx, exported) }))
+
+	reexported, err := db.TableSchemaJSON(ctx, "child")
+	require.NoError(t, err)
+	assert.JSONEq(t, string(exported), string(reexported))
+
+	enginetest.TestQueryWithContext(t, ctx, engine, harness,
+		"select constraint_name, referenced_table_name from information_schema.referential_constraints where table_name = 'child'",
+		[]sql.Row{{"fk_parent", "parent"}}, nil, nil)
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into parent (pk) values (1);")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into child (parent_id, c1) values (1, 1);")
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from child", []sql.Row{{1, 1, 1}}, nil, nil)
+
+	err = inTransaction(t, ctx, func() error { return db.CreateTableFromSchemaJSON(ctx, exported) })
+	assert.True(t, sql.ErrTableAlreadyExists.Is(err))
+
+	err = inTransaction(t, ctx, func() error {
+		return db.CreateTableFromSchemaJSON(ctx, []byte(`{"version": 100, "name": "future", "schema": {}}`))
+	})
+	assert.True(t, sqle.ErrUnsupportedSchemaJSONVersion.Is(err))
+
+	// foreign keys are validated before the table is written
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "drop table child;")
+	mismatched := strings.Replace(string(exported), `"referenced_columns":["pk"]`, `"referenced_columns":["pk","v"]`, 1)
+	err = inTransaction(t, ctx, func() error { return db.CreateTableFromSchemaJSON(ctx, []byte(mismatched)) })
+	assert.True(t, sqle.ErrInvalidSchemaJSONForeignKey.Is(err))
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "drop table parent;")
+	err = inTransaction(t, ctx, func() error { return db.CreateTableFromSchemaJSON(ctx, exported) })
+	require.Error(t, err)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness,
+		"select count(*) from information_schema.tables where table_schema = 'mydb' and table_name = 'child'", []sql.Row{{0}}, nil, nil)
+}
+
+func TestDatabaseRootValidator(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table required (pk int primary key);",
+		"create table other (pk int primary key);",
+	)
+	defer engine.Close()
+
+	errMissingTable := errors.New("table required is missing")
+	var calls int
+	validated := db.WithRootValidator(func(ctx *sql.Context, oldRoot, newRoot *doltdb.RootValue) error {
+		calls++
+		had, err := oldRoot.HasTable(ctx, "required")
+		if err != nil {
+			return err
+		}
+		has, err := newRoot.HasTable(ctx, "required")
+		if err != nil {
+			return err
+		}
+		if had && !has {
+			return errMissingTable
+		}
+		return nil
+	})
+
+	// the setup data also has a view
+	err := inTransaction(t, ctx, func() error { return validated.RenameTable(ctx, "required", "renamed") })
+	require.ErrorIs(t, err, errMissingTable)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select table_name from information_schema.tables where table_schema = 'mydb' and table_type = 'BASE TABLE' order by 1", []sql.Row{{"other"}, {"required"}}, nil, nil)
+
+	require.NoError(t, inTransaction(t, ctx, func() error { return validated.RenameTable(ctx, "other", "other2") }))
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select table_name from information_schema.tables where table_schema = 'mydb' and table_type = 'BASE TABLE' order by 1", []sql.Row{{"other2"}, {"required"}}, nil, nil)
+	assert.Equal(t, 2, calls)
+
+	// the database the validator was added to is unchanged
+	require.NoError(t, inTransaction(t, ctx, func() error { return db.RenameTable(ctx, "required", "renamed") }))
+	assert.Equal(t, 2, calls)
+}
+
+func TestDatabaseForeignKeysReferencing(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table parent (id int primary key, parent_id int, constraint fk_self foreign key (parent_id) references parent (id));",
+		"create table child1 (id int primary key, pid int, constraint fk_b foreign key (pid) references parent (id));",
+		"create table child2 (id int primary key, pid int, c1 int, constraint fk_a foreign key (pid) references parent (id), constraint fk_c foreign key (c1) references child1 (id));",
+		"create table unrelated (id int primary key);",
+	)
+	defer engine.Close()
+
+	names := func(fks []sql.ForeignKeyConstraint) []string {
+		var n []string
+		for _, fk := range fks {
+			n = append(n, fk.Table+"."+fk.Name+"->"+fk.ParentTable)
+		}
+		return n
+	}
+
+	fks, err := db.ForeignKeysReferencing(ctx, "Parent")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"child2.fk_a->parent", "child1.fk_b->parent"}, names(fks))
+	assert.Equal(t, []string{"pid"}, fks[0].Columns)
+	assert.Equal(t, []string{"id"}, fks[0].ParentColumns)
+
+	fks, err = db.ForeignKeysDeclaredBy(ctx, "child2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"child2.fk_a->parent", "child2.fk_c->child1"}, names(fks))
+
+	fks, err = db.ForeignKeysDeclaredBy(ctx, "parent")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"parent.fk_self->parent"}, names(fks))
+
+	fks, err = db.ForeignKeysReferencing(ctx, "unrelated")
+	require.NoError(t, err)
+	assert.Empty(t, fks)
+
+	_, err = db.ForeignKeysReferencing(ctx, "missing")
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+	_, err = db.ForeignKeysDeclaredBy(ctx, "missing")
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+}
+
+func TestDatabaseGetTableCollation(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t1 (pk int primary key) collate utf8mb4_general_ci;",
+		"create table t2 (pk int primary key) collate utf8mb4_0900_bin;",
+	)
+	defer engine.Close()
+
+	collation, err := db.GetTableCollation(ctx, "T1")
+	require.NoError(t, err)
+	assert.Equal(t, sql.Collation_utf8mb4_general_ci, collation)
+
+	collation, err = db.GetTableCollation(ctx, "t2")
+	require.NoError(t, err)
+	assert.Equal(t, sql.Collation_utf8mb4_0900_bin, collation)
+
+	_, err = db.GetTableCollation(ctx, "missing")
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+}
+
+func TestDatabaseGetPrimaryKeyColumns(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (a int, b int, c int, primary key (c, a));",
+		"create table keyless (a int, b int);",
+	)
+	defer engine.Close()
+
+	cols, err := db.GetPrimaryKeyColumns(ctx, "T")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "a"}, cols)
+
+	cols, err = db.GetPrimaryKeyColumns(ctx, "keyless")
+	require.NoError(t, err)
+	assert.NotNil(t, cols)
+	assert.Empty(t, cols)
+
+	_, err = db.GetPrimaryKeyColumns(ctx, "missing")
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+}
+
+func TestDatabaseExportDDL(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table parent (pk int primary key);",
+		"create table child (pk int primary key, parent_id int, foreign key (parent_id) references parent(pk));",
+		"create table a (pk int primary key, b_id int, key (b_id));",
+		"create table b (pk int primary key, a_id int, foreign key (a_id) references a(pk));",
+		"alter table a add constraint a_to_b foreign key (b_id) references b(pk);",
+		"create view parents as select pk from parent;",
+		"create trigger child_ins before insert on child for each row begin set new.pk = new.pk * 10; end;",
+		"create procedure p() begin select 1; end;",
+	)
+	defer engine.Close()
+
+	var buf bytes.Buffer
+	require.NoError(t, db.ExportDDL(ctx, &buf))
+	ddl := buf.String()
+
+	// tables are created in the order given by TableDependencyOrder: tables whose references all exist already come
+	// first, and the cycle between a and b is broken by adding a's foreign key once both exist
+	var positions []int
+	for _, stmt := range []string{
+		"CREATE TABLE `parent`",
+		"CREATE TABLE `child`",
+		"CREATE TABLE `a`",
+		"CREATE TABLE `b`",
+		"ALTER TABLE `a` ADD CONSTRAINT `a_to_b` FOREIGN KEY",
+		"create view parents",
+		"DELIMITER ;;\ncreate trigger child_ins",
+		"DELIMITER ;;\ncreate procedure p()",
+	} {
+		pos := strings.Index(ddl, stmt)
+		require.True(t, pos >= 0, "missing %q in:\n%s", stmt, ddl)
+		positions = append(positions, pos)
+	}
+	assert.IsIncreasing(t, positions, ddl)
+	assert.Equal(t, 1, strings.Count(ddl, "a_to_b"), ddl)
+	assert.NotContains(t, ddl, "dolt_schemas")
+}
+
+func TestDatabaseTableDependencyOrder(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table parent (pk int primary key);",
+		"create table child (pk int primary key, parent_id int, foreign key (parent_id) references parent(pk));",
+		"create table aa_grandchild (pk int primary key, child_id int, foreign key (child_id) references child(pk));",
+		"create table tree (pk int primary key, parent_pk int, foreign key (parent_pk) references tree(pk));",
+		"create table x (pk int primary key, z_id int, key (z_id));",
+		"create table y (pk int primary key, x_id int, foreign key (x_id) references x(pk));",
+		"create table z (pk int primary key, y_id int, foreign key (y_id) references y(pk));",
+		"alter table x add constraint x_to_z foreign key (z_id) references z(pk);",
+	)
+	defer engine.Close()
+
+	deps, err := db.TableDependencyOrder(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"parent", "child", "aa_grandchild", "tree", "x", "y", "z"}, deps.Order)
+	assert.Equal(t, [][]string{{"x", "y", "z"}}, deps.Cycles)
+	require.Len(t, deps.Deferred, 1)
+	assert.Equal(t, "x_to_z", deps.Deferred[0].Name)
+}
+
+func TestDatabaseWithSchemaLock(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness)
+	defer engine.Close()
+
+	errFn := errors.New("fn failed")
+	assert.Equal(t, errFn, db.WithSchemaLock(ctx, func() error { return errFn }))
+
+	otherCtx := harness.NewContextWithClient(sql.Client{Address: "localhost", User: "root"})
+	err := db.WithSchemaLock(ctx, func() error {
+		// creating a table takes the lock again, which the session holding it can do
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, "create table t (pk int primary key);")
+
+		timeoutCtx, cancel := context.WithTimeout(otherCtx, 50*time.Millisecond)
+		defer cancel()
+		called := false
+		err := db.WithSchemaLock(otherCtx.WithContext(timeoutCtx), func() error {
+			called = true
+			return nil
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.False(t, called)
+		return nil
+	})
+	require.NoError(t, err)
+
+	called := false
+	require.NoError(t, db.WithSchemaLock(otherCtx, func() error {
+		called = true
+		return nil
+	}))
+	assert.True(t, called)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select table_name from information_schema.tables where table_schema = 'mydb' and table_type = 'BASE TABLE'", []sql.Row{{"t"}}, nil, nil)
+
+	// the lock is released before the transaction commits, so tables created concurrently are checked for colliding
+	// tags when the second transaction commits and its working set is merged
+	sch := sql.NewPrimaryKeySchema(sql.Schema{{Name: "pk", Type: types.Int32, PrimaryKey: true}})
+	otherCtx.SetCurrentDatabase("mydb")
+	otherSess := dsess.DSessFromSess(otherCtx.Session)
+	tx, err := otherSess.StartTransaction(otherCtx, sql.ReadWrite)
+	require.NoError(t, err)
+	otherCtx.SetTransaction(tx)
+	require.NoError(t, db.CreateTableWithTags(otherCtx, "b", sch, sql.Collation_Default, map[string]uint64{"pk": 4321}))
+	require.NoError(t, inTransaction(t, ctx, func() error {
+		return db.CreateTableWithTags(ctx, "a", sch, sql.Collation_Default, map[string]uint64{"pk": 4321})
+	}))
+	err = otherSess.CommitTransaction(otherCtx, tx)
+	require.Error(t, err)
+	assert.Equal(t, schema.ErrTagPrevUsed(4321, "pk", "a").Error(), err.Error())
+	require.NoError(t, otherSess.Rollback(otherCtx, tx))
+	otherCtx.SetTransaction(nil)
+}
+
+func TestDatabaseInvalidateSchemaCache(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create view v as select 1 as a;",
+	)
+	defer engine.Close()
+
+	view, ok, err := db.GetViewDefinition(ctx, "v")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "select 1 as a", view.TextDefinition)
+
+	// replace the cached view, as if the fragment had been changed without this session noticing
+	root, err := db.GetRoot(ctx)
+	require.NoError(t, err)
+	key, err := doltdb.NewDataCacheKey(root)
+	require.NoError(t, err)
+	dbState, _, err := dsess.DSessFromSess(ctx.Session).LookupDbState(ctx, db.RevisionQualifiedName())
+	require.NoError(t, err)
+	dbState.SessionCache().CacheViews(key, []sql.ViewDefinition{{Name: "v", TextDefinition: "select 2 as a"}})
+
+	view, ok, err = db.GetViewDefinition(ctx, "v")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "select 2 as a", view.TextDefinition)
+
+	require.NoError(t, inTransaction(t, ctx, func() error { return db.InvalidateSchemaCache(ctx) }))
+	assert.False(t, dbState.SessionCache().ViewsCached(key))
+
+	view, ok, err = db.GetViewDefinition(ctx, "v")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "select 1 as a", view.TextDefinition)
+}
+
+func TestDatabaseWouldDropTable(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table parent (id int primary key auto_increment);",
+		"create table child (id int primary key, parent_id int, foreign key (parent_id) references parent (id));",
+		"create table other (id int primary key);",
+		"create view parent_ids as select id from parent;",
+		"create view other_ids as select id from other;",
+		"create trigger trig before insert on child for each row insert into parent values ();",
+		"call dolt_commit('-Am', 'creating tables');",
+		"call dolt_branch('b1');",
+		"call dolt_branch('b2');",
+		"call dolt_branch('b3', 'HEAD~1');",
+		"insert into `mydb/b1`.other values (1);",
+		"insert into parent values (1);",
+	)
+	defer engine.Close()
+
+	head, _, err := db.ResolveRef(ctx, "HEAD")
+	require.NoError(t, err)
+	require.NoError(t, db.DbData().Ddb.SetHeadToCommit(ctx, ref.NewRemoteRef("origin", "main"), head))
+
+	impact, err := db.WouldDropTable(ctx, "PARENT")
+	require.NoError(t, err)
+	assert.Equal(t, "parent", impact.TableName)
+	assert.Empty(t, impact.DeclaredForeignKeys)
+	require.Len(t, impact.ReferencingForeignKeys, 1)
+	assert.Equal(t, "child", impact.ReferencingForeignKeys[0].TableName)
+	assert.ElementsMatch(t, []sqle.FragSpec{{Type: "view", Name: "parent_ids"}, {Type: "trigger", Name: "trig"}}, impact.DependentFragments)
+	assert.True(t, impact.HasAutoIncrement)
+	// b3 doesn't have the table, and remote refs don't affect the auto increment sequence
+	assert.Equal(t, []string{"b1", "b2"}, impact.AutoIncrementBranches)
+
+	impact, err = db.WouldDropTable(ctx, "child")
+	require.NoError(t, err)
+	require.Len(t, impact.DeclaredForeignKeys, 1)
+	assert.Equal(t, "parent", impact.DeclaredForeignKeys[0].ReferencedTableName)
+	assert.Empty(t, impact.ReferencingForeignKeys)
+	// the trigger is defined on child, so it would be dropped along with it
+	assert.Empty(t, impact.DependentFragments)
+	assert.False(t, impact.HasAutoIncrement)
+	assert.Empty(t, impact.AutoIncrementBranches)
+
+	// nothing was changed
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from parent", []sql.Row{{1}}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select table_name from dolt_status", []sql.Row{{"parent"}}, nil, nil)
+
+	_, err = db.WouldDropTable(ctx, "nope")
+	require.Error(t, err)
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+	_, err = db.WouldDropTable(ctx, "dolt_log")
+	require.Error(t, err)
+	assert.True(t, sqle.ErrSystemTableAlter.Is(err))
+}
+
+func TestDatabaseWouldDropTableAutoIncrement(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (id int primary key auto_increment);",
+		"create table u (id int primary key auto_increment);",
+		"call dolt_commit('-Am', 'creating tables');",
+		"call dolt_branch('b1');",
+		"insert into `mydb/b1`.t values (5);",
+		"insert into t values (1), (2), (3), (4), (5), (6), (7), (8);",
+		"insert into u values (1), (2), (3);",
+		"call dolt_commit('-am', 'inserting rows');",
+		"call dolt_branch('-d', 'b1');",
+	)
+	defer engine.Close()
+
+	// b1 was deleted, so only main has u, and its sequence starts over once it's dropped
+	impact, err := db.WouldDropTable(ctx, "u")
+	require.NoError(t, err)
+	assert.Empty(t, impact.AutoIncrementBranches)
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "drop table u;")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "create table u (id int primary key auto_increment);")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into u values ();")
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select id from u", []sql.Row{{1}}, nil, nil)
+
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_branch('b2', 'HEAD~1');")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into `mydb/b2`.t values (20);")
+
+	// the sequence of t continues from b2's working set once it's dropped on main
+	impact, err = db.WouldDropTable(ctx, "t")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b2"}, impact.AutoIncrementBranches)
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "drop table t;")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "create table t (id int primary key auto_increment);")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into t values ();")
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select id from t", []sql.Row{{21}}, nil, nil)
+}
+
+func TestDatabaseDropTableDependents(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t1 (id int primary key);",
+		"create table t2 (id int primary key);",
+		"create table t3 (id int primary key);",
+		"create view v1 as select id from t1;",
+		"create trigger trig before insert on t2 for each row insert into t1 values (new.id);",
+	)
+	defer engine.Close()
+
+	deps, err := db.FindDependentSchemaObjects(ctx, "T1")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []sqle.FragSpec{{Type: "view", Name: "v1"}, {Type: "trigger", Name: "trig"}}, deps)
+	deps, err = db.FindDependentSchemaObjects(ctx, "t3")
+	require.NoError(t, err)
+	assert.Empty(t, deps)
+	// the trigger is defined on t2, so it's dropped along with it
+	deps, err = db.FindDependentSchemaObjects(ctx, "t2")
+	require.NoError(t, err)
+	assert.Empty(t, deps)
+
+	require.NoError(t, ctx.SetSessionVariable(ctx, "dolt_drop_table_dependents", "error"))
+	err = inTransaction(t, ctx, func() error { return db.DropTable(ctx, "t1") })
+	require.Error(t, err)
+	assert.True(t, sqle.ErrDropTableHasDependents.Is(err))
+	require.NoError(t, inTransaction(t, ctx, func() error { return db.DropTable(ctx, "t3") }))
+
+	require.NoError(t, ctx.SetSessionVariable(ctx, "dolt_drop_table_dependents", "warn"))
+	require.NoError(t, inTransaction(t, ctx, func() error { return db.DropTable(ctx, "t1") }))
+	require.Len(t, ctx.Session.Warnings(), 1)
+	assert.Equal(t, sqle.DropTableDependentsWarningCode, ctx.Session.Warnings()[0].Code)
+	require.NoError(t, inTransaction(t, ctx, func() error { return db.DropTable(ctx, "t2") }))
+	assert.Len(t, ctx.Session.Warnings(), 1)
+
+	// dependents are left in place
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select name from dolt_schemas order by name", []sql.Row{{"trig"}, {"v1"}}, nil, nil)
+}
+
+func TestDatabaseRenameValidation(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create database otherdb;",
+	)
+	defer engine.Close()
+
+	err := db.Rename(ctx, "OtherDB")
+	require.Error(t, err)
+	assert.True(t, sql.ErrDatabaseExists.Is(err))
+
+	err = db.Rename(ctx, "newdb/main")
+	require.Error(t, err)
+	err = db.Rename(ctx, "")
+	require.Error(t, err)
+
+	pro := dsess.DSessFromSess(ctx.Session).Provider()
+	err = pro.RenameDatabase(ctx, "mydb/main", "newdb")
+	require.Error(t, err)
+	err = pro.RenameDatabase(ctx, "nosuchdb", "newdb")
+	require.Error(t, err)
+	assert.True(t, sql.ErrDatabaseNotFound.Is(err))
+
+	// nothing was renamed
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select schema_name from information_schema.schemata where schema_name in ('mydb', 'otherdb', 'newdb') order by 1",
+		[]sql.Row{{"mydb"}, {"otherdb"}}, nil, nil)
+}
+
+func TestDatabaseCreateTableWithTags(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness)
+	defer engine.Close()
+
+	sch := sql.NewPrimaryKeySchema(sql.Schema{
+		{Name: "pk", Type: types.Int32, PrimaryKey: true},
+		{Name: "c1", Type: types.Int32, Nullable: true},
+		{Name: "c2", Type: types.Int32, Nullable: true},
+	})
+	require.NoError(t, inTransaction(t, ctx, func() error {
+		return db.CreateTableWithTags(ctx, "t", sch, sql.Collation_Default, map[string]uint64{"PK": 1234, "c1": 5678})
+	}))
+
+	root, err := db.GetRoot(ctx)
+	require.NoError(t, err)
+	tbl, ok, err := root.GetTable(ctx, "t")
+	require.NoError(t, err)
+	require.True(t, ok)
+	doltSch, err := tbl.GetSchema(ctx)
+	require.NoError(t, err)
+	col, ok := doltSch.GetAllCols().GetByName("pk")
+	require.True(t, ok)
+	assert.Equal(t, uint64(1234), col.Tag)
+	col, ok = doltSch.GetAllCols().GetByName("c1")
+	require.True(t, ok)
+	assert.Equal(t, uint64(5678), col.Tag)
+
+	err = inTransaction(t, ctx, func() error {
+		return db.CreateTableWithTags(ctx, "u", sch, sql.Collation_Default, map[string]uint64{"c2": 5678})
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "5678")
+	err = inTransaction(t, ctx, func() error {
+		return db.CreateTableWithTags(ctx, "u", sch, sql.Collation_Default, map[string]uint64{"nope": 1})
+	})
+	require.Error(t, err)
+	err = inTransaction(t, ctx, func() error {
+		return db.CreateTableWithTags(ctx, "u", sch, sql.Collation_Default, map[string]uint64{"c1": schema.ReservedTagMin})
+	})
+	require.Error(t, err)
+	err = inTransaction(t, ctx, func() error {
+		return db.CreateTableWithTags(ctx, "u", sch, sql.Collation_Default, map[string]uint64{"c1": 4321, "c2": 4321})
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "4321")
+	err = inTransaction(t, ctx, func() error {
+		return db.CreateTableWithTags(ctx, "u", sch, sql.Collation_Default, map[string]uint64{"c1": 4321, "C1": 8765})
+	})
+	require.Error(t, err)
+	_, ok, err = db.GetTableInsensitive(ctx, "u")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// a column without a tag given never gets one of the tags given for other columns
+	root, err = db.GetRoot(ctx)
+	require.NoError(t, err)
+	generated, err := root.GenerateTagsForNewColumns(ctx, "u", []string{"pk", "c1", "c2"}, []storetypes.NomsKind{storetypes.IntKind, storetypes.IntKind, storetypes.IntKind}, nil)
+	require.NoError(t, err)
+	require.NoError(t, inTransaction(t, ctx, func() error {
+		return db.CreateTableWithTags(ctx, "u", sch, sql.Collation_Default, map[string]uint64{"c1": generated[2]})
+	}))
+	root, err = db.GetRoot(ctx)
+	require.NoError(t, err)
+	tbl, ok, err = root.GetTable(ctx, "u")
+	require.NoError(t, err)
+	require.True(t, ok)
+	doltSch, err = tbl.GetSchema(ctx)
+	require.NoError(t, err)
+	col, ok = doltSch.GetAllCols().GetByName("c1")
+	require.True(t, ok)
+	assert.Equal(t, generated[2], col.Tag)
+	col, ok = doltSch.GetAllCols().GetByName("c2")
+	require.True(t, ok)
+	assert.NotEqual(t, generated[2], col.Tag)
+}
+
+func TestDatabaseIsKeylessTable(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key, c int);",
+		"create table k (c int);",
+	)
+	defer engine.Close()
+
+	keyless, err := db.IsKeylessTable(ctx, "t")
+	require.NoError(t, err)
+	assert.False(t, keyless)
+
+	keyless, err = db.IsKeylessTable(ctx, "K")
+	require.NoError(t, err)
+	assert.True(t, keyless)
+
+	_, err = db.IsKeylessTable(ctx, "missing")
+	require.Error(t, err)
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+}
+
+func TestDatabaseCreateTableDefaultCollation(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness)
+	defer engine.Close()
+
+	require.NoError(t, inTransaction(t, ctx, func() error {
+		return db.SetCollation(ctx, sql.Collation_utf8mb4_0900_ai_ci)
+	}))
+
+	sch := sql.NewPrimaryKeySchema(sql.Schema{
+		{Name: "pk", Type: types.Int32, PrimaryKey: true},
+		{Name: "c", Type: types.Int32, Nullable: true},
+	})
+	require.NoError(t, inTransaction(t, ctx, func() error {
+		return db.CreateTable(ctx, "t", sch, sql.Collation_Unspecified)
+	}))
+	require.NoError(t, inTransaction(t, ctx, func() error {
+		return db.CreateTable(ctx, "u", sch, sql.Collation_utf8mb4_0900_bin)
+	}))
+
+	root, err := db.GetRoot(ctx)
+	require.NoError(t, err)
+	tableCollation := func(tableName string) schema.Collation {
+		tbl, ok, err := root.GetTable(ctx, tableName)
+		require.NoError(t, err)
+		require.True(t, ok)
+		doltSch, err := tbl.GetSchema(ctx)
+		require.NoError(t, err)
+		return doltSch.GetCollation()
+	}
+	assert.Equal(t, schema.Collation(sql.Collation_utf8mb4_0900_ai_ci), tableCollation("t"))
+	assert.Equal(t, schema.Collation(sql.Collation_utf8mb4_0900_bin), tableCollation("u"))
+}
+
+func TestDatabaseCreateTableIfNotExists(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+		"insert into t values (1);",
+	)
+	defer engine.Close()
+
+	sch := sql.NewPrimaryKeySchema(sql.Schema{
+		{Name: "pk", Type: types.Int32, PrimaryKey: true},
+		{Name: "c", Type: types.Int32, Nullable: true},
+	})
+
+	createIfNotExists := func(tableName string) (created bool, err error) {
+		err = inTransaction(t, ctx, func() error {
+			created, err = db.CreateTableIfNotExists(ctx, tableName, sch, sql.Collation_Unspecified)
+			return err
+		})
+		return created, err
+	}
+
+	// an existing table is left alone, even though its schema is different
+	created, err := createIfNotExists("T")
+	require.NoError(t, err)
+	assert.False(t, created)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from t;", []sql.Row{{1}}, nil, nil)
+
+	created, err = createIfNotExists("u")
+	require.NoError(t, err)
+	assert.True(t, created)
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into u values (1, 2);")
+
+	created, err = createIfNotExists("u")
+	require.NoError(t, err)
+	assert.False(t, created)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from u;", []sql.Row{{1, 2}}, nil, nil)
+
+	_, err = createIfNotExists("dolt_reserved")
+	assert.True(t, sqle.ErrReservedTableName.Is(err))
+}
+
+func TestDatabaseCreateTableComplete(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table parent (id int primary key, code int);",
+		"insert into parent values (1, 10);",
+	)
+	defer engine.Close()
+
+	sch := sql.NewPrimaryKeySchema(sql.Schema{
+		{Name: "id", Type: types.Int32, PrimaryKey: true, Source: "child"},
+		{Name: "pid", Type: types.Int32, Nullable: true, Source: "child"},
+		{Name: "up", Type: types.Int32, Nullable: true, Source: "child"},
+	})
+	indexes := []sql.IndexDef{
+		{Name: "pid_idx", Columns: []sql.IndexColumn{{Name: "pid"}}},
+		{Name: "up_idx", Columns: []sql.IndexColumn{{Name: "up"}}},
+	}
+	fk := func(name, col, parent, parentCol string) sql.ForeignKeyConstraint {
+		return sql.ForeignKeyConstraint{
+			Name:          name,
+			Table:         "child",
+			Columns:       []string{col},
+			ParentTable:   parent,
+			ParentColumns: []string{parentCol},
+			OnUpdate:      sql.ForeignKeyReferentialAction_DefaultAction,
+			OnDelete:      sql.ForeignKeyReferentialAction_DefaultAction,
+		}
+	}
+
+	createChild := func(indexes []sql.IndexDef, fks ...sql.ForeignKeyConstraint) error {
+		return inTransaction(t, ctx, func() error {
+			return db.CreateTableComplete(ctx, "child", sch, sql.Collation_Unspecified, indexes, fks)
+		})
+	}
+
+	// the referenced table must exist
+	err := createChild(indexes, fk("fk_missing", "pid", "missing", "id"))
+	require.Error(t, err)
+	// the referenced columns must be indexed
+	err = createChild(indexes, fk("fk_code", "pid", "parent", "code"))
+	assert.True(t, sql.ErrForeignKeyMissingReferenceIndex.Is(err))
+	// and so must the columns of the foreign key
+	err = createChild(indexes[:1], fk("fk_up", "up", "child", "id"))
+	assert.True(t, sqle.ErrForeignKeyMissingIndex.Is(err))
+
+	names, err := db.GetTableNames(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"parent"}, names)
+
+	err = createChild(indexes, fk("fk_parent", "pid", "parent", "id"), fk("fk_up", "up", "child", "id"))
+	require.NoError(t, err)
+
+	fks, err := db.ForeignKeysDeclaredBy(ctx, "child")
+	require.NoError(t, err)
+	require.Len(t, fks, 2)
+	assert.Equal(t, "fk_parent", fks[0].Name)
+	assert.Equal(t, "fk_up", fks[1].Name)
+
+	enginetest.TestQueryWithContext(t, ctx, engine, harness,
+		"select index_name from information_schema.statistics where table_name = 'child' order by index_name;",
+		[]sql.Row{{"pid_idx"}, {"PRIMARY"}, {"up_idx"}}, nil, nil)
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into child values (1, 1, null), (2, 1, 1);")
+	enginetest.AssertErrWithCtx(t, engine, harness, ctx, "insert into child values (3, 2, null);", sql.ErrForeignKeyChildViolation)
+	enginetest.AssertErrWithCtx(t, engine, harness, ctx, "insert into child values (3, 1, 4);", sql.ErrForeignKeyChildViolation)
+
+	err = createChild(nil)
+	assert.True(t, sql.ErrTableAlreadyExists.Is(err))
+}
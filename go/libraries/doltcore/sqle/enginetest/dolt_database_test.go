@@ -0,0 +1,499 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enginetest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/enginetest"
+	"github.com/dolthub/go-mysql-server/enginetest/scriptgen/setup"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// newDatabaseTestEngine returns an engine for the mydb database after running the setup queries given, along with a
+// context to run further queries with and the sqle.Database for mydb.
+func newDatabaseTestEngine(t *testing.T, harness *DoltHarness, setupQueries ...string) (enginetest.QueryEngine, *sql.Context, sqle.Database) {
+	harness.Setup(setup.MydbData)
+	engine, err := harness.NewEngine(t)
+	require.NoError(t, err)
+
+	ctx := enginetest.NewContext(harness)
+	for _, q := range setupQueries {
+		enginetest.RunQueryWithContext(t, engine, harness, ctx, q)
+	}
+
+	sqlDb, err := dsess.DSessFromSess(ctx.Session).Provider().Database(ctx, "mydb")
+	require.NoError(t, err)
+	db, ok := sqlDb.(sqle.Database)
+	require.True(t, ok, "unexpected database type %T", sqlDb)
+
+	return engine, ctx, db
+}
+
+// inTransaction runs |fn| in a new transaction on |ctx| and commits it, the same way the engine runs each query, so
+// that changes made through the sqle.Database API are seen by the queries that follow. If |fn| returns an error, the
+// transaction is rolled back and the error is returned.
+func inTransaction(t *testing.T, ctx *sql.Context, fn func() error) error {
+	sess := dsess.DSessFromSess(ctx.Session)
+	tx, err := sess.StartTransaction(ctx, sql.ReadWrite)
+	require.NoError(t, err)
+	ctx.SetTransaction(tx)
+	defer ctx.SetTransaction(nil)
+
+	if err := fn(); err != nil {
+		require.NoError(t, sess.Rollback(ctx, tx))
+		return err
+	}
+	require.NoError(t, sess.CommitTransaction(ctx, tx))
+	return nil
+}
+
+func TestDatabaseIsIgnored(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"insert into dolt_ignore values ('generated_*', true), ('generated_keep', false), ('conflict_*', true), ('conflict_%', false);",
+	)
+	defer engine.Close()
+
+	ignored, err := db.IsIgnored(ctx, "generated_foo")
+	require.NoError(t, err)
+	assert.True(t, ignored)
+
+	ignored, err = db.IsIgnored(ctx, "generated_keep")
+	require.NoError(t, err)
+	assert.False(t, ignored)
+
+	ignored, err = db.IsIgnored(ctx, "t")
+	require.NoError(t, err)
+	assert.False(t, ignored)
+
+	_, err = db.IsIgnored(ctx, "conflict_t")
+	require.Error(t, err)
+	assert.NotNil(t, doltdb.AsDoltIgnoreInConflict(err))
+}
+
+func TestDatabaseGetAutoIncrementValue(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key auto_increment, c int);",
+		"create table noinc (pk int primary key);",
+		"insert into t (c) values (1), (2), (3);",
+	)
+	defer engine.Close()
+
+	next, err := db.GetAutoIncrementValue(ctx, "t")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), next)
+
+	next, err = db.GetAutoIncrementValue(ctx, "T")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), next)
+
+	_, err = db.GetAutoIncrementValue(ctx, "noinc")
+	require.Error(t, err)
+	assert.True(t, sqle.ErrNoAutoIncrementColumn.Is(err))
+
+	_, err = db.GetAutoIncrementValue(ctx, "missing")
+	require.Error(t, err)
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+}
+
+func TestDatabaseTableRowCountApprox(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key, c int);",
+		"create table keyless (c int);",
+		"insert into t values (1, 1), (2, 2), (3, 3);",
+		"insert into keyless values (1), (2), (2);",
+	)
+	defer engine.Close()
+
+	count, exact, err := db.TableRowCountApprox(ctx, "T")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), count)
+	assert.True(t, exact)
+
+	count, exact, err = db.TableRowCountApprox(ctx, "keyless")
+	require.NoError(t, err)
+	assert.LessOrEqual(t, count, uint64(3))
+	assert.False(t, exact)
+
+	_, _, err = db.TableRowCountApprox(ctx, "missing")
+	require.Error(t, err)
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+}
+
+func TestDatabaseSetAutoIncrementValue(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key auto_increment, c int);",
+		"create table noinc (pk int primary key);",
+		"insert into t (c) values (1), (2), (3);",
+	)
+	defer engine.Close()
+
+	require.NoError(t, inTransaction(t, ctx, func() error { return db.SetAutoIncrementValue(ctx, "t", 10) }))
+	next, err := db.GetAutoIncrementValue(ctx, "t")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), next)
+
+	require.NoError(t, inTransaction(t, ctx, func() error { return db.SetAutoIncrementValue(ctx, "t", 5) }))
+	next, err = db.GetAutoIncrementValue(ctx, "t")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), next)
+	assert.Equal(t, uint16(0), ctx.Session.WarningCount())
+
+	// values already in the table can't be reused
+	require.NoError(t, inTransaction(t, ctx, func() error { return db.SetAutoIncrementValue(ctx, "t", 2) }))
+	next, err = db.GetAutoIncrementValue(ctx, "t")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), next)
+	require.Equal(t, uint16(1), ctx.Session.WarningCount())
+	assert.Equal(t, sqle.AutoIncrementClampedWarningCode, ctx.Session.Warnings()[0].Code)
+
+	err = inTransaction(t, ctx, func() error { return db.SetAutoIncrementValue(ctx, "noinc", 10) })
+	require.Error(t, err)
+	assert.True(t, sqle.ErrNoAutoIncrementColumn.Is(err))
+}
+
+func TestDatabaseSetAutoIncrementValueAcrossBranches(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key auto_increment, c int);",
+		"insert into t (c) values (1);",
+		"call dolt_commit('-Am', 'create table');",
+		"call dolt_checkout('-b', 'other');",
+		"insert into t (c) values (2), (3), (4), (5);",
+		"call dolt_commit('-am', 'insert on other');",
+		"call dolt_checkout('main');",
+	)
+	defer engine.Close()
+
+	// main only has pk 1, but the values used on other can't be reused either
+	ctx.ClearWarnings()
+	require.NoError(t, inTransaction(t, ctx, func() error { return db.SetAutoIncrementValue(ctx, "t", 3) }))
+	next, err := db.GetAutoIncrementValue(ctx, "t")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6), next)
+	require.Equal(t, uint16(1), ctx.Session.WarningCount())
+	assert.Equal(t, sqle.AutoIncrementClampedWarningCode, ctx.Session.Warnings()[0].Code)
+	assert.Equal(t, "auto_increment value for table t was raised from 3 to 6, the next value it can take without reusing a value on any branch", ctx.Session.Warnings()[0].Message)
+
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into t (c) values (6);")
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select pk from t order by pk;", []sql.Row{{1}, {6}}, nil, nil)
+}
+
+func TestDatabaseCheckoutTables(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table a (pk int primary key auto_increment, c1 int);",
+		"create table b (pk int primary key);",
+		"create table c (pk int primary key);",
+		"insert into a (c1) values (1);",
+		"call dolt_commit('-Am', 'create tables');",
+		"insert into a (c1) values (2), (3);",
+		"insert into b values (1);",
+		"insert into c values (1);",
+	)
+	defer engine.Close()
+
+	require.NoError(t, inTransaction(t, ctx, func() error { return db.CheckoutTables(ctx, []string{"a", "B"}) }))
+
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select pk, c1 from a", []sql.Row{{1, 1}}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from b", []sql.Row{}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from c", []sql.Row{{1}}, nil, nil)
+
+	// auto increment values handed out before the checkout aren't reused
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into a (c1) values (4);")
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select pk, c1 from a", []sql.Row{{1, 1}, {4, 4}}, nil, nil)
+
+	err := inTransaction(t, ctx, func() error { return db.CheckoutTables(ctx, []string{"c", "missing"}) })
+	require.Error(t, err)
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from c", []sql.Row{{1}}, nil, nil)
+}
+
+func TestDatabaseTruncateAllTables(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table parent (id int primary key auto_increment, c varchar(10), index idx_c (c));",
+		"create table child (id int primary key, pid int, foreign key (pid) references parent (id));",
+		"insert into parent (c) values ('a'), ('b'), ('c');",
+		"insert into child values (1, 1), (2, 3);",
+		"create table dolt_docs (doc_name varchar(16383) not null, doc_text longtext, primary key (doc_name));",
+		"insert into dolt_docs values ('README.md', 'readme');",
+		"create view v as select c from parent;",
+	)
+	defer engine.Close()
+
+	require.NoError(t, inTransaction(t, ctx, func() error {
+		return db.TruncateAllTables(ctx, sqle.TruncateAllOpts{PreserveDocs: true})
+	}))
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select count(*) from parent", []sql.Row{{0}}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select count(*) from child", []sql.Row{{0}}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select count(*) from parent where c = 'a'", []sql.Row{{0}}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select doc_name from dolt_docs", []sql.Row{{"README.md"}}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select count(*) from v", []sql.Row{{0}}, nil, nil)
+
+	// the auto-increment value is kept by default
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into parent (c) values ('d')")
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from parent", []sql.Row{{4, "d"}}, nil, nil)
+
+	require.NoError(t, inTransaction(t, ctx, func() error {
+		return db.TruncateAllTables(ctx, sqle.TruncateAllOpts{ResetAutoIncrement: true})
+	}))
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select count(*) from dolt_docs", []sql.Row{{0}}, nil, nil)
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into parent (c) values ('e')")
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from parent", []sql.Row{{1, "e"}}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select name from dolt_schemas", []sql.Row{{"v"}}, nil, nil)
+}
+
+func TestDatabaseCommitAll(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+		"call dolt_commit('-Am', 'creating table t');",
+		"insert into t values (1);",
+		"create table u (pk int primary key);",
+	)
+	defer engine.Close()
+
+	date := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	var h hash.Hash
+	err := inTransaction(t, ctx, func() (err error) {
+		h, err = db.CommitAll(ctx, "committing everything", sqle.CommitOpts{Name: "Jane Doe", Email: "jane@example.com", Date: date})
+		return err
+	})
+	require.NoError(t, err)
+
+	head, _, err := db.ResolveRef(ctx, "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, h.String(), commitHash(t, head))
+	meta, err := head.GetCommitMeta(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "committing everything", meta.Description)
+	assert.Equal(t, "Jane Doe", meta.Name)
+	assert.Equal(t, "jane@example.com", meta.Email)
+	assert.True(t, date.Equal(meta.Time()))
+
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from dolt_status", []sql.Row{}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from t as of 'HEAD'", []sql.Row{{1}}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from u as of 'HEAD'", []sql.Row{}, nil, nil)
+
+	err = inTransaction(t, ctx, func() error {
+		_, err := db.CommitAll(ctx, "nothing changed", sqle.CommitOpts{})
+		return err
+	})
+	require.Error(t, err)
+	assert.True(t, sqle.ErrNothingToCommit.Is(err))
+
+	err = inTransaction(t, ctx, func() (err error) {
+		h, err = db.CommitAll(ctx, "nothing changed", sqle.CommitOpts{AllowEmpty: true})
+		return err
+	})
+	require.NoError(t, err)
+	head, _, err = db.ResolveRef(ctx, "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, h.String(), commitHash(t, head))
+	meta, err = head.GetCommitMeta(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "root", meta.Name)
+}
+
+func TestDatabaseCommitTables(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table parent (id int primary key);",
+		"create table child (id int primary key, parent_id int, foreign key (parent_id) references parent (id));",
+		"create table t (pk int primary key);",
+		"call dolt_commit('-Am', 'creating tables');",
+		"insert into t values (1);",
+		"create table u (pk int primary key);",
+		"insert into parent values (1);",
+		"insert into child values (1, 1);",
+		"create table s (pk int primary key);",
+		"call dolt_add('s');",
+	)
+	defer engine.Close()
+
+	commitTables := func(tables []string, message string) (h hash.Hash, err error) {
+		err = inTransaction(t, ctx, func() error {
+			h, err = db.CommitTables(ctx, tables, message)
+			return err
+		})
+		return h, err
+	}
+
+	_, err := commitTables([]string{"child"}, "child only")
+	require.Error(t, err)
+	assert.True(t, sqle.ErrPartialCommitForeignKey.Is(err))
+	// the tables referencing a committed table must be committed too
+	_, err = commitTables([]string{"parent"}, "parent only")
+	require.Error(t, err)
+	assert.True(t, sqle.ErrPartialCommitForeignKey.Is(err))
+	_, err = commitTables([]string{"nope"}, "no such table")
+	require.Error(t, err)
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+	// committing would unstage s
+	_, err = commitTables([]string{"T", "u"}, "t and u")
+	require.Error(t, err)
+	assert.True(t, sqle.ErrPartialCommitStaged.Is(err))
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_reset('s');")
+
+	h, err := commitTables([]string{"T", "u"}, "t and u")
+	require.NoError(t, err)
+	head, _, err := db.ResolveRef(ctx, "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, h.String(), commitHash(t, head))
+
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from t as of 'HEAD'", []sql.Row{{1}}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from u as of 'HEAD'", []sql.Row{}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select * from parent as of 'HEAD'", []sql.Row{}, nil, nil)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select table_name, staged from dolt_status order by table_name",
+		[]sql.Row{{"child", false}, {"parent", false}, {"s", false}}, nil, nil)
+
+	_, err = commitTables([]string{"t"}, "nothing changed")
+	require.Error(t, err)
+	assert.True(t, sqle.ErrNothingToCommit.Is(err))
+
+	_, err = commitTables([]string{"child", "parent"}, "child and parent")
+	require.NoError(t, err)
+	enginetest.TestQueryWithContext(t, ctx, engine, harness, "select table_name, staged from dolt_status", []sql.Row{{"s", false}}, nil, nil)
+
+	// only whole merges can be committed
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_commit('-Am', 'creating s');")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_checkout('-b', 'other');")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into s values (1);")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_commit('-am', 'changing s');")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_checkout('main');")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "insert into t values (2);")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_merge('other', '--no-ff', '--no-commit');")
+	_, err = commitTables([]string{"t"}, "during a merge")
+	require.Error(t, err)
+	assert.True(t, sqle.ErrPartialCommitMerge.Is(err))
+}
+
+func TestDatabaseForEachTable(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table a (pk int primary key);",
+		"create table b (pk int primary key);",
+		"create table c (pk int primary key);",
+		"create view v as select * from a;",
+	)
+	defer engine.Close()
+
+	allNames, err := db.GetAllTableNames(ctx)
+	require.NoError(t, err)
+
+	var names []string
+	err = db.ForEachTable(ctx, func(name string, tbl sql.Table) error {
+		assert.Equal(t, name, tbl.Name())
+		names = append(names, name)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, allNames, names)
+	assert.Contains(t, names, doltdb.SchemasTableName)
+
+	errStop := errors.New("stop")
+	names = nil
+	err = db.ForEachTable(ctx, func(name string, tbl sql.Table) error {
+		names = append(names, name)
+		if len(names) == 2 {
+			return errStop
+		}
+		return nil
+	})
+	assert.Equal(t, errStop, err)
+	assert.Len(t, names, 2)
+}
+
+func TestDatabaseTableCreatedAt(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, ctx, db := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key);",
+		"call dolt_commit('-Am', 'creating t');",
+		"create table u (pk int primary key);",
+		"call dolt_commit('-Am', 'creating u');",
+		"insert into t values (1);",
+		"call dolt_commit('-am', 'changing t');",
+		"create table w (pk int primary key);",
+	)
+	defer engine.Close()
+
+	// TableCreatedAt walks back from the head commit seen by the current transaction, so each call runs in a new one,
+	// the way the engine runs each query
+	createdAtMessage := func(tableName string) string {
+		var cm *doltdb.Commit
+		require.NoError(t, inTransaction(t, ctx, func() (err error) {
+			cm, err = db.TableCreatedAt(ctx, tableName)
+			return err
+		}))
+		meta, err := cm.GetCommitMeta(ctx)
+		require.NoError(t, err)
+		return meta.Description
+	}
+
+	assert.Equal(t, "creating t", createdAtMessage("t"))
+	assert.Equal(t, "creating u", createdAtMessage("U"))
+	assert.Equal(t, "creating t", createdAtMessage("t"))
+
+	_, err := db.TableCreatedAt(ctx, "w")
+	require.Error(t, err)
+	assert.True(t, sqle.ErrTableNotCommitted.Is(err))
+
+	_, err = db.TableCreatedAt(ctx, "missing")
+	require.Error(t, err)
+	assert.True(t, sql.ErrTableNotFound.Is(err))
+
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "drop table t;")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_commit('-am', 'dropping t');")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "create table t (pk int primary key);")
+	enginetest.RunQueryWithContext(t, engine, harness, ctx, "call dolt_commit('-Am', 'recreating t');")
+	assert.Equal(t, "recreating t", createdAtMessage("t"))
+}
+
+func commitHash(t *testing.T, cm *doltdb.Commit) string {
+	h, err := cm.HashOf()
+	require.NoError(t, err)
+	return h.String()
+}
+
+func rootHash(t *testing.T, root *doltdb.RootValue) string {
+	h, err := root.HashOf()
+	require.NoError(t, err)
+	return h.String()
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dprocedures"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/utils/config"
 	"github.com/dolthub/dolt/go/store/datas"
@@ -1217,6 +1218,16 @@ func TestCallAsOf(t *testing.T) {
 	}
 }
 
+func TestSystemTablesAsOf(t *testing.T) {
+	for _, script := range SystemTableAsOfScriptTests {
+		func() {
+			h := newDoltHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
 func TestLargeJsonObjects(t *testing.T) {
 	SkipByDefaultInCI(t)
 	harness := newDoltHarness(t)
@@ -1529,6 +1540,56 @@ func TestDoltMerge(t *testing.T) {
 	}
 }
 
+type testMergeMetrics struct {
+	merges, fastForwards, conflicts, violations map[string]int
+}
+
+func (m *testMergeMetrics) MergeCompleted(dbName string, fastForward bool, conflicts, constraintViolations int) {
+	m.merges[dbName]++
+	if fastForward {
+		m.fastForwards[dbName]++
+	}
+	m.conflicts[dbName] += conflicts
+	m.violations[dbName] += constraintViolations
+}
+
+func TestDoltMergeMetrics(t *testing.T) {
+	metrics := &testMergeMetrics{
+		merges:       make(map[string]int),
+		fastForwards: make(map[string]int),
+		conflicts:    make(map[string]int),
+		violations:   make(map[string]int),
+	}
+	dprocedures.SetMergeMetrics(metrics)
+	defer dprocedures.SetMergeMetrics(nil)
+
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	engine, _, _ := newDatabaseTestEngine(t, harness,
+		"create table t (pk int primary key, c int);",
+		"insert into t values (1, 1), (2, 2);",
+		"call dolt_commit('-Am', 'create table');",
+		"call dolt_checkout('-b', 'ff');",
+		"insert into t values (3, 3);",
+		"call dolt_commit('-am', 'insert on ff');",
+		"call dolt_checkout('-b', 'other', 'main');",
+		"update t set c = 10 where pk in (1, 2);",
+		"call dolt_commit('-am', 'update on other');",
+		"call dolt_checkout('main');",
+		"call dolt_merge('ff');",
+		"update t set c = 100 where pk in (1, 2);",
+		"call dolt_commit('-am', 'update on main');",
+		"set dolt_allow_commit_conflicts = on;",
+		"call dolt_merge('other');",
+	)
+	defer engine.Close()
+
+	assert.Equal(t, 2, metrics.merges["mydb"])
+	assert.Equal(t, 1, metrics.fastForwards["mydb"])
+	assert.Equal(t, 2, metrics.conflicts["mydb"])
+	assert.Equal(t, 0, metrics.violations["mydb"])
+}
+
 func TestDoltMergePrepared(t *testing.T) {
 	for _, script := range MergeScripts {
 		// harness can't reset effectively when there are new commits / branches created, so use a new harness for
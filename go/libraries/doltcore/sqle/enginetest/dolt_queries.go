@@ -700,6 +700,138 @@ var DoltRevisionDbScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "database revision specs: another branch's working set",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'creating table t');",
+			"set @Commit1 = hashof('main');",
+			"call dolt_branch('feature');",
+			"insert into `mydb/feature`.t values (1);",
+			"call dolt_branch('other');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select * from t as of 'feature/working';",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select * from t as of 'FEATURE/WORKING';",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select * from t as of 'feature';",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select to_pk, from_pk, diff_type from dolt_commit_diff_t where to_commit = 'feature/working' and from_commit = @Commit1;",
+				Expected: []sql.Row{{1, nil, "added"}},
+			},
+			{
+				Query:    "select * from t as of 'other/working';",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:          "select * from t as of 'missing/working';",
+				ExpectedErrStr: "branch not found: missing/working",
+			},
+		},
+	},
+	{
+		Name: "as of a tag with an ancestor spec",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'creating table t');",
+			"insert into t values (1);",
+			"call dolt_commit('-am', 'adding 1');",
+			"insert into t values (2);",
+			"call dolt_commit('-am', 'adding 2');",
+			"call dolt_tag('v1.2.0');",
+			"insert into t values (3);",
+			"call dolt_commit('-am', 'adding 3');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select * from t as of 'v1.2.0' order by pk;",
+				Expected: []sql.Row{{1}, {2}},
+			},
+			{
+				Query:    "select * from t as of 'v1.2.0~1';",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select * from t as of 'v1.2.0^';",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select * from t as of 'refs/tags/v1.2.0~1';",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select * from t as of 'v1.2.0~2';",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select hashof('v1.2.0~1') = hashof('HEAD~2');",
+				Expected: []sql.Row{{true}},
+			},
+			{
+				Query:    "select to_pk, diff_type from dolt_diff('v1.2.0~1', 'v1.2.0', 't');",
+				Expected: []sql.Row{{2, "added"}},
+			},
+			{
+				Query:          "select * from t as of 'v1.2.0~10';",
+				ExpectedErrStr: "invalid ancestor spec",
+			},
+			{
+				Query:          "select * from t as of 'v9.9.9~1';",
+				ExpectedErrStr: "branch not found: v9.9.9",
+			},
+		},
+	},
+	{
+		Name: "database revision specs: dolt_active_revisions",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'creating table t');",
+			"call dolt_branch('b1');",
+			"call dolt_tag('v1');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select * from dolt_active_revisions;",
+				Expected: []sql.Row{{"mydb", "main", "branch"}},
+			},
+			{
+				Query:    "start transaction;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select * from `mydb/b1`.t;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select * from `mydb/v1`.t;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select * from `mydb/b1`.dolt_active_revisions;",
+				Expected: []sql.Row{{"mydb", "b1", "branch"}, {"mydb", "main", "branch"}, {"mydb", "v1", "tag"}},
+			},
+			{
+				Query:    "commit;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select * from dolt_active_revisions;",
+				Expected: []sql.Row{{"mydb", "main", "branch"}},
+			},
+		},
+	},
 }
 
 // DoltScripts are script tests specific to Dolt (not the engine in general), e.g. by involving Dolt functions. Break
@@ -1081,6 +1213,129 @@ var DoltScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "AS OF with ancestors of HEAD",
+		SetUpScript: []string{
+			"create table ancestors (pk int primary key);",
+			"call dolt_add('-A');",
+			"call dolt_commit('-m', 'creating table ancestors');",
+			"insert into ancestors values (1);",
+			"call dolt_commit('-am', 'one row');",
+			"insert into ancestors values (2);",
+			"call dolt_commit('-am', 'two rows');",
+			"insert into ancestors values (3);",
+			"call dolt_commit('-am', 'three rows');",
+			"insert into ancestors values (4);",
+			"call dolt_commit('-am', 'four rows');",
+			"insert into ancestors values (5);",
+			"call dolt_commit('-am', 'five rows');",
+			"insert into ancestors values (6);",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select count(*) from ancestors as of 'HEAD';",
+				Expected: []sql.Row{{5}},
+			},
+			{
+				Query:    "select count(*) from ancestors as of 'HEAD~0';",
+				Expected: []sql.Row{{5}},
+			},
+			{
+				Query:    "select count(*) from ancestors as of 'HEAD^0';",
+				Expected: []sql.Row{{5}},
+			},
+			{
+				Query:    "select count(*) from ancestors as of 'HEAD~';",
+				Expected: []sql.Row{{4}},
+			},
+			{
+				Query:    "select count(*) from ancestors as of 'HEAD^';",
+				Expected: []sql.Row{{4}},
+			},
+			{
+				Query:    "select count(*) from ancestors as of 'HEAD^^';",
+				Expected: []sql.Row{{3}},
+			},
+			{
+				Query:    "select count(*) from ancestors as of 'head~2^';",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "select count(*) from ancestors as of 'HEAD~5';",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:          "select count(*) from ancestors as of 'HEAD~100';",
+				ExpectedErrStr: "invalid ancestor spec",
+			},
+			{
+				Query:    "call dolt_branch('ancestors_branch', 'HEAD~2');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select count(*) from `mydb/ancestors_branch`.ancestors as of 'HEAD~1';",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:            "call dolt_checkout('ancestors_branch');",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "select count(*) from ancestors as of 'HEAD';",
+				Expected: []sql.Row{{3}},
+			},
+			{
+				Query:    "select count(*) from ancestors as of 'HEAD~2';",
+				Expected: []sql.Row{{1}},
+			},
+		},
+	},
+	{
+		Name: "dolt_table_cache_size",
+		SetUpScript: []string{
+			"create table cache_t (pk int primary key, c int);",
+			"create table cache_u (pk int primary key);",
+			"insert into cache_t values (1, 1);",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select @@dolt_table_cache_size, @@dolt_disable_table_cache;",
+				Expected: []sql.Row{{0, 0}},
+			},
+			{
+				Query:    "set dolt_disable_table_cache = 1;",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "insert into cache_t values (2, 2);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "select * from cache_t join cache_u on cache_t.pk = cache_u.pk;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "set dolt_disable_table_cache = 0;",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "set dolt_table_cache_size = 1;",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "insert into cache_u select pk from cache_t;",
+				Expected: []sql.Row{{types.NewOkResult(2)}},
+			},
+			{
+				Query:    "select cache_t.pk, cache_t.c from cache_t join cache_u on cache_t.pk = cache_u.pk order by 1;",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+			{
+				Query:       "set dolt_table_cache_size = -1;",
+				ExpectedErr: sql.ErrInvalidSystemVariableValue,
+			},
+		},
+	},
 }
 
 func makeLargeInsert(sz int) string {
@@ -6029,3 +6284,105 @@ END`,
 		},
 	},
 }
+
+// SystemTableAsOfScriptTests are tests of resolving system tables AS OF a revision
+var SystemTableAsOfScriptTests = []queries.ScriptTest{
+	{
+		Name: "history system tables are scoped to the AS OF commit",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 int);",
+			"call dolt_add('-A');",
+			"call dolt_commit('-m', 'creating table t');",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-am', 'added a row');",
+			"insert into t values (2, 2);",
+			"call dolt_commit('-am', 'added another row');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select message from dolt_log AS OF 'HEAD~1';",
+				Expected: []sql.Row{
+					{"added a row"},
+					{"creating table t"},
+					{"checkpoint enginetest database mydb"},
+					{"Initialize data repository"},
+				},
+			},
+			{
+				Query:    "select count(*) from dolt_diff AS OF 'HEAD~1' where table_name = 't';",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "select to_pk, diff_type from dolt_diff_t AS OF 'HEAD~1';",
+				Expected: []sql.Row{{1, "added"}},
+			},
+			{
+				Query:    "select pk from dolt_history_t AS OF 'HEAD~1';",
+				Expected: []sql.Row{{1}},
+			},
+		},
+	},
+	{
+		Name: "root system tables read from the AS OF root",
+		SetUpScript: []string{
+			"insert into dolt_ignore values ('ignored_*', true);",
+			"call dolt_add('-A');",
+			"call dolt_commit('-m', 'first ignore pattern');",
+			"insert into dolt_ignore values ('generated_*', true);",
+			"call dolt_commit('-am', 'second ignore pattern');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select pattern from dolt_ignore AS OF 'HEAD~1';",
+				Expected: []sql.Row{{"ignored_*"}},
+			},
+			{
+				Query:    "select pattern from dolt_ignore order by pattern;",
+				Expected: []sql.Row{{"generated_*"}, {"ignored_*"}},
+			},
+			{
+				Query:    "select * from dolt_constraint_violations AS OF 'HEAD~1';",
+				Expected: []sql.Row{},
+			},
+		},
+	},
+	{
+		Name: "database-wide system tables ignore AS OF",
+		SetUpScript: []string{
+			"call dolt_commit('--allow-empty', '-m', 'empty commit');",
+			"call dolt_branch('b1');",
+			"call dolt_tag('v1');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select name from dolt_branches AS OF 'HEAD~1' order by name;",
+				Expected: []sql.Row{{"b1"}, {"main"}},
+			},
+			{
+				Query:    "select tag_name from dolt_tags AS OF 'HEAD~1';",
+				Expected: []sql.Row{{"v1"}},
+			},
+		},
+	},
+	{
+		Name: "working set system tables cannot be read AS OF",
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:       "select * from dolt_status AS OF 'HEAD';",
+				ExpectedErr: sqle.ErrSystemTableAsOf,
+			},
+			{
+				Query:       "select * from dolt_merge_status AS OF 'HEAD';",
+				ExpectedErr: sqle.ErrSystemTableAsOf,
+			},
+			{
+				Query:       "select * from dolt_conflicts AS OF 'HEAD';",
+				ExpectedErr: sqle.ErrSystemTableAsOf,
+			},
+			{
+				Query:       "select * from dolt_schema_conflicts AS OF 'HEAD';",
+				ExpectedErr: sqle.ErrSystemTableAsOf,
+			},
+		},
+	},
+}
@@ -886,6 +886,76 @@ inner join t on to_pk = t.pk;`,
 			},
 		},
 	},
+	{
+		Name: "modified columns",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 varchar(20), c2 varchar(20));",
+			"call dolt_add('.')",
+			"insert into t values (1, 'one', 'two'), (2, 'three', 'four'), (3, 'five', 'six');",
+			"set @Commit1 = '';",
+			"call dolt_commit_hash_out(@Commit1, '-am', 'inserting into table t');",
+
+			"update t set c1 = 'uno' where pk = 1;",
+			"update t set c2 = 'cuatro' where pk = 2;",
+			"update t set c1 = 'cinco', c2 = 'seis' where pk = 3;",
+			"insert into t values (4, 'seven', 'eight');",
+			"delete from t where pk = 2;",
+			"insert into t values (2, 'three', 'cuatro');",
+			"set @Commit2 = '';",
+			"call dolt_commit_hash_out(@Commit2, '-am', 'updating table t');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "SELECT to_pk, to_c1, to_c2, from_pk, from_c1, from_c2, diff_type from dolt_diff(@Commit1, @Commit2, 't', '--modified-columns=c1');",
+				Expected: []sql.Row{
+					{1, "uno", "two", 1, "one", "two", "modified"},
+					{3, "cinco", "seis", 3, "five", "six", "modified"},
+					{4, "seven", "eight", nil, nil, nil, "added"},
+				},
+			},
+			{
+				Query: "SELECT to_pk, diff_type from dolt_diff('--modified-columns=C2', @Commit1, @Commit2, 't');",
+				Expected: []sql.Row{
+					{2, "modified"},
+					{3, "modified"},
+					{4, "added"},
+				},
+			},
+			{
+				Query: "SELECT to_pk, diff_type from dolt_diff(@Commit1, @Commit2, 't', '--modified-columns=c1, c2');",
+				Expected: []sql.Row{
+					{1, "modified"},
+					{2, "modified"},
+					{3, "modified"},
+					{4, "added"},
+				},
+			},
+			{
+				Query:    "SELECT to_pk, diff_type from dolt_diff(@Commit1, @Commit2, 't', '--modified-columns=pk');",
+				Expected: []sql.Row{{4, "added"}},
+			},
+			{
+				Query: "SELECT to_pk, diff_type from dolt_diff('HEAD~..HEAD', 't', '--modified-columns=c1');",
+				Expected: []sql.Row{
+					{1, "modified"},
+					{3, "modified"},
+					{4, "added"},
+				},
+			},
+			{
+				Query:       "SELECT * from dolt_diff(@Commit1, @Commit2, 't', '--modified-columns=doesnotexist');",
+				ExpectedErr: sql.ErrColumnNotFound,
+			},
+			{
+				Query:       "SELECT * from dolt_diff(@Commit1, @Commit2, 't', '--modified-columns=');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+			{
+				Query:       "SELECT * from dolt_diff(@Commit1, @Commit2, 't', '--modified-columns=c1', '--modified-columns=c2');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+		},
+	},
 	{
 		Name: "WORKING and STAGED",
 		SetUpScript: []string{
@@ -4608,6 +4678,82 @@ var ColumnDiffSystemTableScriptTests = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "summarizing text columns",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 text, c2 int);",
+			"insert into t values (1, 'hello', 1), (2, 'world', 2);",
+			"call dolt_commit('-Am', 'creating table t');",
+			"update t set c1 = 'hello there' where pk = 1;",
+			"update t set c2 = 3 where pk = 2;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select to_pk, to_c1, from_c1 from dolt_diff_t where to_commit = 'WORKING' order by to_pk;",
+				Expected: []sql.Row{
+					{1, "hello there", "hello"},
+					{2, "world", "world"},
+				},
+			},
+			{
+				Query:    "set dolt_diff_summarize_blobs = 1;",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query: "select to_pk, to_c1, from_c1, to_c2 from dolt_diff_t where to_commit = 'WORKING' order by to_pk;",
+				Expected: []sql.Row{
+					{1, "<changed: 11 B>", "<5 B>", 1},
+					{2, "<5 B>", "<5 B>", 3},
+				},
+			},
+			{
+				Query: "select to_pk, to_c1, from_c1, diff_type from dolt_diff_t where diff_type = 'added' order by to_pk;",
+				Expected: []sql.Row{
+					{1, "<5 B>", nil, "added"},
+					{2, "<5 B>", nil, "added"},
+				},
+			},
+		},
+	},
+	{
+		Name: "treating NULL as equal to an empty string or zero",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 varchar(20), c2 int);",
+			"insert into t values (1, null, 1), (2, 'a', null), (3, 'b', 3);",
+			"call dolt_commit('-Am', 'creating table t');",
+			"update t set c1 = '' where pk = 1;",
+			"update t set c2 = 0 where pk = 2;",
+			"update t set c1 = null where pk = 3;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select to_pk, diff_type from dolt_diff_t where to_commit = 'WORKING' order by to_pk;",
+				Expected: []sql.Row{
+					{1, "modified"},
+					{2, "modified"},
+					{3, "modified"},
+				},
+			},
+			{
+				Query:    "set dolt_diff_null_equivalence = 'both';",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query: "select to_pk, diff_type from dolt_diff_t where to_commit = 'WORKING' order by to_pk;",
+				Expected: []sql.Row{
+					{3, "modified"},
+				},
+			},
+			{
+				Query: "select to_pk, diff_type from dolt_diff_t where diff_type = 'added' order by to_pk;",
+				Expected: []sql.Row{
+					{1, "added"},
+					{2, "added"},
+					{3, "added"},
+				},
+			},
+		},
+	},
 }
 
 var CommitDiffSystemTableScriptTests = []queries.ScriptTest{
@@ -4969,18 +5115,19 @@ var CommitDiffSystemTableScriptTests = []queries.ScriptTest{
 		},
 		Assertions: []queries.ScriptTestAssertion{
 			{
-				Query:                           "select * from dolt_commit_diff_t where from_commit=@Commit2 and to_commit=@Commit3;",
-				ExpectedWarning:                 1105,
-				ExpectedWarningsCount:           1,
-				ExpectedWarningMessageSubstring: "cannot render full diff between commits",
-				Expected:                        []sql.Row{},
+				Query: "SELECT to_pk, to_c1, from_pk, from_c1, diff_type FROM dolt_commit_diff_t where from_commit=@Commit1 and to_commit=@Commit2 ORDER BY from_pk;",
+				Expected: []sql.Row{
+					{nil, nil, 1, 2, "removed"},
+					{nil, nil, 3, 4, "removed"},
+				},
 			},
 			{
-				Query:                           "select * from dolt_commit_diff_t where from_commit=@Commit3 and to_commit=@Commit3;",
-				ExpectedWarning:                 1105,
-				ExpectedWarningsCount:           1,
-				ExpectedWarningMessageSubstring: "cannot render full diff between commits",
-				Expected:                        []sql.Row{},
+				Query:    "select * from dolt_commit_diff_t where from_commit=@Commit2 and to_commit=@Commit3;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select * from dolt_commit_diff_t where from_commit=@Commit3 and to_commit=@Commit3;",
+				Expected: []sql.Row{},
 			},
 			{
 				Query:    "SELECT to_pk, to_c1, from_pk, from_c1, diff_type FROM DOLT_commit_DIFF_t where from_commit=@Commit3 and to_commit=@Commit4;",
@@ -4992,6 +5139,83 @@ var CommitDiffSystemTableScriptTests = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "summarizing text columns",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 text);",
+			"insert into t values (1, 'hello'), (2, 'world');",
+			"call dolt_commit('-Am', 'creating table t');",
+			"update t set c1 = 'hello there' where pk = 1;",
+			"delete from t where pk = 2;",
+			"set dolt_diff_summarize_blobs = 1;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select to_pk, to_c1, from_pk, from_c1, diff_type from dolt_commit_diff_t where from_commit = hashof('HEAD') and to_commit = 'WORKING' order by coalesce(to_pk, from_pk);",
+				Expected: []sql.Row{
+					{1, "<changed: 11 B>", 1, "<5 B>", "modified"},
+					{nil, nil, 2, "<5 B>", "removed"},
+				},
+			},
+		},
+	},
+	{
+		Name: "treating NULL as equal to an empty string or zero",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 varchar(20), c2 int);",
+			"insert into t values (1, null, 1), (2, 'a', null), (3, null, null);",
+			"call dolt_commit('-Am', 'creating table t');",
+			"update t set c1 = '' where pk = 1;",
+			"update t set c2 = 0 where pk = 2;",
+			"update t set c1 = '', c2 = 0 where pk = 3;",
+			"insert into t values (4, '', 0);",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select to_pk, diff_type from dolt_commit_diff_t where from_commit = hashof('HEAD') and to_commit = 'WORKING' order by to_pk;",
+				Expected: []sql.Row{
+					{1, "modified"},
+					{2, "modified"},
+					{3, "modified"},
+					{4, "added"},
+				},
+			},
+			{
+				Query:    "set dolt_diff_null_equivalence = 'empty_string';",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query: "select to_pk, diff_type from dolt_commit_diff_t where from_commit = hashof('HEAD') and to_commit = 'WORKING' order by to_pk;",
+				Expected: []sql.Row{
+					{2, "modified"},
+					{3, "modified"},
+					{4, "added"},
+				},
+			},
+			{
+				Query:    "set dolt_diff_null_equivalence = 'zero';",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query: "select to_pk, diff_type from dolt_commit_diff_t where from_commit = hashof('HEAD') and to_commit = 'WORKING' order by to_pk;",
+				Expected: []sql.Row{
+					{1, "modified"},
+					{3, "modified"},
+					{4, "added"},
+				},
+			},
+			{
+				Query:    "set dolt_diff_null_equivalence = 'both';",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query: "select to_pk, diff_type from dolt_commit_diff_t where from_commit = hashof('HEAD') and to_commit = 'WORKING' order by to_pk;",
+				Expected: []sql.Row{
+					{4, "added"},
+				},
+			},
+		},
+	},
 }
 
 var SchemaDiffSystemTableScriptTests = []queries.ScriptTest{
@@ -5569,6 +5793,45 @@ var SystemTableIndexTests = []systabScript{
 			},
 		},
 	},
+	{
+		name: "commit ancestors lookups for merge commits",
+		setup: []string{
+			"create table xy (x int primary key, y int)",
+			"call dolt_commit('-Am', 'main 1');",
+			"call dolt_checkout('-b', 'feat');",
+			"insert into xy values (1, 1);",
+			"call dolt_commit('-am', 'feat 1');",
+			"set @feat = hashof('feat');",
+			"call dolt_checkout('main');",
+			"insert into xy values (2, 2);",
+			"call dolt_commit('-am', 'main 2');",
+			"set @main = hashof('main');",
+			"call dolt_merge('feat');",
+			"set @merge = hashof('main');",
+		},
+		queries: []systabQuery{
+			{
+				query: "select parent_hash = @main, parent_hash = @feat, parent_index from dolt_commit_ancestors where commit_hash = @merge order by parent_index;",
+				exp:   []sql.Row{{true, false, 0}, {false, true, 1}},
+			},
+			{
+				query: "select parent_index from dolt_commit_ancestors where commit_hash = @merge and parent_hash = @feat;",
+				exp:   []sql.Row{{1}},
+			},
+			{
+				query: "select count(*) from dolt_commit_ancestors where commit_hash in (@merge, @feat);",
+				exp:   []sql.Row{{3}},
+			},
+			{
+				query: "select cm.message from dolt_commit_ancestors as an join dolt_commits as cm on cm.commit_hash = an.parent_hash where an.commit_hash = @merge order by an.parent_index;",
+				exp:   []sql.Row{{"main 2"}, {"feat 1"}},
+			},
+			{
+				query: "select count(*) from dolt_commits as cm join dolt_commit_ancestors as an on cm.commit_hash = an.commit_hash where cm.commit_hash = @merge;",
+				exp:   []sql.Row{{2}},
+			},
+		},
+	},
 	{
 		name: "empty log table",
 		setup: []string{
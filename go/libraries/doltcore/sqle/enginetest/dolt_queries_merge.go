@@ -2099,6 +2099,542 @@ var MergeScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "dolt_merge with --merge-base set to the common ancestor",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int);",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-Am', 'create table');",
+			"call dolt_branch('other');",
+			"insert into t values (2, 2);",
+			"call dolt_commit('-am', 'add row 2 on main');",
+			"call dolt_checkout('other');",
+			"insert into t values (3, 3);",
+			"call dolt_commit('-am', 'add row 3 on other');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_merge('--merge-base', 'HEAD~1', 'other');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{1, 1}, {2, 2}, {3, 3}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge with --merge-base that is not an ancestor of both commits",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int);",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-Am', 'create table');",
+			"call dolt_branch('other');",
+			"insert into t values (2, 2);",
+			"call dolt_commit('-am', 'add row 2 on main');",
+			"call dolt_checkout('other');",
+			"insert into t values (3, 3);",
+			"call dolt_commit('-am', 'add row 3 on other');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "call dolt_merge('--merge-base', 'main', 'other');",
+				ExpectedErrStr: "merge base is not an ancestor of both commits being merged",
+			},
+			{
+				Query:          "call dolt_merge('--force-merge-base', 'other');",
+				ExpectedErrStr: "error: Flag '--force-merge-base' requires '--merge-base'",
+			},
+			{
+				// Using main as the base makes row 2 look like it was deleted on other
+				Query:           "call dolt_merge('--merge-base', 'main', '--force-merge-base', 'other');",
+				Expected:        []sql.Row{{doltCommit, 0, 0}},
+				ExpectedWarning: 1105,
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{1, 1}, {3, 3}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge notes rows merged automatically from changes to different columns",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 int, c2 int);",
+			"insert into t values (1, 1, 1), (2, 2, 2), (3, 3, 3);",
+			"call dolt_commit('-Am', 'create table');",
+			"call dolt_branch('other');",
+			"update t set c1 = 10 where pk in (1, 2);",
+			"update t set c2 = 30 where pk = 3;",
+			"call dolt_commit('-am', 'change c1 on main');",
+			"call dolt_checkout('other');",
+			"update t set c2 = 20 where pk in (1, 2);",
+			"update t set c2 = 30 where pk = 3;",
+			"call dolt_commit('-am', 'change c2 on other');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				// row 3 was changed the same way on both sides, so it didn't need merging
+				Query:                           "call dolt_merge('other');",
+				Expected:                        []sql.Row{{doltCommit, 0, 0}},
+				ExpectedWarning:                 1105,
+				ExpectedWarningsCount:           1,
+				ExpectedWarningMessageSubstring: "2 rows were merged automatically from changes to different columns in table t",
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{1, 10, 20}, {2, 10, 20}, {3, 3, 30}},
+			},
+			{
+				Query:    "select * from dolt_merge_stats;",
+				Expected: []sql.Row{{"t", 2, 0}},
+			},
+			{
+				// already up to date
+				Query:    "call dolt_merge('other');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query:    "select * from dolt_merge_stats;",
+				Expected: []sql.Row{},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge with --merge-base does not fast-forward",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int);",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-Am', 'create table');",
+			"call dolt_checkout('-b', 'other');",
+			"insert into t values (2, 2);",
+			"call dolt_commit('-am', 'add row 2 on other');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_merge('--merge-base', 'HEAD', 'other');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query:    "select message from dolt_log limit 1;",
+				Expected: []sql.Row{{"Merge branch 'other' into main"}},
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge with --date",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int);",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-Am', 'create table');",
+			"call dolt_branch('b3');",
+			"call dolt_checkout('-b', 'b1');",
+			"insert into t values (2, 2);",
+			"call dolt_commit('-am', 'add row 2 on b1');",
+			"call dolt_checkout('-b', 'b2');",
+			"insert into t values (3, 3);",
+			"call dolt_commit('-am', 'add row 3 on b2');",
+			"call dolt_checkout('b3');",
+			"insert into t values (4, 4);",
+			"call dolt_commit('-am', 'add row 4 on b3');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:           "call dolt_merge('b1', '--date', '2022-06-15T12:00:00Z');",
+				Expected:        []sql.Row{{doltCommit, 1, 0}},
+				ExpectedWarning: 1105,
+			},
+			{
+				Query:    "select message, date < '2022-06-16' from dolt_log limit 1;",
+				Expected: []sql.Row{{"add row 2 on b1", false}},
+			},
+			{
+				Query:    "call dolt_merge('--no-ff', '-m', 'no-ff merge of b2', 'b2', '--date', '2022-06-15T12:00:00Z');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query:    "select message, date >= '2022-06-15' and date < '2022-06-16' from dolt_log limit 1;",
+				Expected: []sql.Row{{"no-ff merge of b2", true}},
+			},
+			{
+				Query:    "call dolt_merge('b3', '--date', '2022-07-15T12:00:00Z');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query:    "select message, date >= '2022-07-15' and date < '2022-07-16' from dolt_log limit 1;",
+				Expected: []sql.Row{{"Merge branch 'b3' into main", true}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge with --only merges the named tables",
+		SetUpScript: []string{
+			"create table t1 (pk int primary key, c int);",
+			"create table t2 (pk int primary key, c int);",
+			"insert into t1 values (1, 1);",
+			"insert into t2 values (1, 1);",
+			"call dolt_commit('-Am', 'create tables');",
+			"call dolt_checkout('-b', 'other');",
+			"insert into t1 values (2, 2);",
+			"insert into t2 values (2, 2);",
+			"create table t3 (pk int primary key);",
+			"call dolt_commit('-Am', 'change tables on other');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "call dolt_merge('--only', 't1,missing', 'other');",
+				ExpectedErrStr: "table missing does not exist on either side of the merge",
+			},
+			{
+				Query:    "call dolt_merge('--only', 'T1', 'other');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query:    "select * from t1;",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+			{
+				Query:    "select * from t2;",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "select table_name from information_schema.tables where table_schema = 'mydb' and table_type = 'BASE TABLE' order by 1;",
+				Expected: []sql.Row{{"t1"}, {"t2"}},
+			},
+			{
+				Query:    "select count(*) from dolt_status;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select count(*) from dolt_commit_ancestors where commit_hash = hashof('HEAD');",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "call dolt_merge('other');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query:    "select * from t2;",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+			{
+				Query:    "select table_name from information_schema.tables where table_schema = 'mydb' and table_type = 'BASE TABLE' order by 1;",
+				Expected: []sql.Row{{"t1"}, {"t2"}, {"t3"}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge with --only adds a table that only exists on the other branch",
+		SetUpScript: []string{
+			"create table t1 (pk int primary key);",
+			"call dolt_commit('-Am', 'create table');",
+			"call dolt_checkout('-b', 'other');",
+			"insert into t1 values (1);",
+			"create table t2 (pk int primary key);",
+			"insert into t2 values (1);",
+			"call dolt_commit('-Am', 'change tables on other');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_merge('--only', 't2', 'other');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query:    "select * from t1;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select * from t2;",
+				Expected: []sql.Row{{1}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge with --resolve resolves data conflicts",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int);",
+			"insert into t values (1, 1), (2, 2);",
+			"call dolt_commit('-Am', 'create table');",
+			"call dolt_checkout('-b', 'other');",
+			"update t set c = 10 where pk = 1;",
+			"insert into t values (3, 30);",
+			"call dolt_commit('-am', 'change t on other');",
+			"call dolt_checkout('main');",
+			"update t set c = 100 where pk = 1;",
+			"insert into t values (4, 400);",
+			"call dolt_commit('-am', 'change t on main');",
+			"call dolt_branch('main2');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "call dolt_merge('--resolve', 'mine', 'other');",
+				ExpectedErrStr: "error: invalid value 'mine' for '--resolve', expected 'ours' or 'theirs'",
+			},
+			{
+				Query:    "call dolt_merge('--resolve', 'theirs', 'other');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{1, 10}, {2, 2}, {3, 30}, {4, 400}},
+			},
+			{
+				Query:    "select count(*) from dolt_conflicts;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select count(*) from dolt_status;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select count(*) from dolt_log where message = 'Merge branch ''other'' into main';",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:            "call dolt_checkout('main2');",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "call dolt_merge('--resolve', 'ours', '--no-commit', 'other');",
+				Expected: []sql.Row{{"", 0, 0}},
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{1, 100}, {2, 2}, {3, 30}, {4, 400}},
+			},
+			{
+				Query:    "select table_name, staged from dolt_status;",
+				Expected: []sql.Row{{"t", true}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge with --resolve fails on constraint violations",
+		SetUpScript: []string{
+			"create table parent (pk int primary key);",
+			"create table child (pk int primary key, parent_fk int, foreign key (parent_fk) references parent(pk));",
+			"insert into parent values (1);",
+			"call dolt_commit('-Am', 'setup');",
+			"call dolt_branch('right');",
+			"delete from parent where pk = 1;",
+			"call dolt_commit('-am', 'delete parent 1');",
+			"call dolt_checkout('right');",
+			"insert into child values (1, 1);",
+			"call dolt_commit('-am', 'insert child');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "call dolt_merge('--resolve', 'theirs', 'right');",
+				ExpectedErrStr: "error: '--resolve' only resolves data conflicts, but the merge has constraint violations in tables: child",
+			},
+			{
+				Query:    "select count(*) from dolt_constraint_violations;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:          "call dolt_merge('--abort', '--resolve', 'ours');",
+				ExpectedErrStr: "error: Flags '--abort' and '--resolve' cannot be used together.\n",
+			},
+		},
+	},
+	{
+		Name: "dolt_merge with --conflict-branch",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int);",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-Am', 'create table');",
+			"call dolt_checkout('-b', 'other');",
+			"update t set c = 10 where pk = 1;",
+			"call dolt_commit('-am', 'change t on other');",
+			"call dolt_checkout('main');",
+			"update t set c = 100 where pk = 1;",
+			"call dolt_commit('-am', 'change t on main');",
+			"call dolt_branch('taken');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "call dolt_merge('--conflict-branch', 'taken', 'other');",
+				ExpectedErrStr: "fatal: A branch named 'taken' already exists.",
+			},
+			{
+				Query:          "call dolt_merge('--conflict-branch', 'resolve-me', '--resolve', 'ours', 'other');",
+				ExpectedErrStr: "error: Flags '--conflict-branch' and '--resolve' cannot be used together.\n",
+			},
+			{
+				Query:    "call dolt_merge('--conflict-branch', 'resolve-me', 'other');",
+				Expected: []sql.Row{{"", 0, 1}},
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{1, 100}},
+			},
+			{
+				Query:    "select count(*) from dolt_conflicts;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select is_merging from dolt_merge_status;",
+				Expected: []sql.Row{{false}},
+			},
+			{
+				Query:    "select count(*) from dolt_status;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select (select hash from dolt_branches where name = 'resolve-me') = hashof('main');",
+				Expected: []sql.Row{{true}},
+			},
+			{
+				Query:            "call dolt_checkout('resolve-me');",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "select is_merging, source from dolt_merge_status;",
+				Expected: []sql.Row{{true, "other"}},
+			},
+			{
+				Query:    "select our_c, their_c from dolt_conflicts_t;",
+				Expected: []sql.Row{{100, 10}},
+			},
+			{
+				Query:            "call dolt_conflicts_resolve('--theirs', 't');",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "call dolt_commit('-am', 'resolve merge');",
+				Expected: []sql.Row{{doltCommit}},
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{1, 10}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge with --text-merge",
+		SetUpScript: []string{
+			"set @@dolt_allow_commit_conflicts = 1;",
+			"create table t (pk int primary key, doc text);",
+			"insert into t values (1, 'one\\ntwo\\nthree\\n'), (2, 'a\\nb\\n');",
+			"call dolt_commit('-Am', 'create table');",
+			"call dolt_checkout('-b', 'other');",
+			"update t set doc = 'ONE\\ntwo\\nthree\\n' where pk = 1;",
+			"update t set doc = 'a\\nB\\n' where pk = 2;",
+			"call dolt_commit('-am', 'change t on other');",
+			"call dolt_checkout('main');",
+			"update t set doc = 'one\\ntwo\\nTHREE\\n' where pk = 1;",
+			"update t set doc = 'a\\nBB\\n' where pk = 2;",
+			"call dolt_commit('-am', 'change t on main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "call dolt_merge('--text-merge', 'doc', 'other');",
+				ExpectedErrStr: "error: invalid column 'doc' for '--text-merge', expected 'table.column'",
+			},
+			{
+				Query:    "call dolt_merge('other');",
+				Expected: []sql.Row{{"", 0, 1}},
+			},
+			{
+				Query:    "select count(*) from dolt_conflicts_t;",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "call dolt_merge('--abort');",
+				Expected: []sql.Row{{"", 0, 0}},
+			},
+			{
+				Query:    "call dolt_merge('--text-merge', 'T.Doc', 'other');",
+				Expected: []sql.Row{{"", 0, 1}},
+			},
+			{
+				Query:    "select pk, doc from t order by pk;",
+				Expected: []sql.Row{{1, "ONE\ntwo\nTHREE\n"}, {2, "a\nBB\n"}},
+			},
+			{
+				Query:    "select our_pk, our_doc, their_doc from dolt_conflicts_t;",
+				Expected: []sql.Row{{2, "a\nBB\n", "a\nB\n"}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge with --allow-empty",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'create table');",
+			"call dolt_checkout('-b', 'other');",
+			"call dolt_commit('--allow-empty', '-m', 'empty commit');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "call dolt_merge('--allow-empty', 'other');",
+				ExpectedErrStr: "error: Flag '--allow-empty' requires '--no-ff'",
+			},
+			{
+				Query:    "call dolt_merge('--no-ff', '--allow-empty', '-m', 'merge other', 'other');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query:    "select message from dolt_log limit 1;",
+				Expected: []sql.Row{{"merge other"}},
+			},
+			{
+				Query:    "select count(*) from dolt_commit_ancestors where commit_hash = hashof('HEAD');",
+				Expected: []sql.Row{{2}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge with --author uses the author for every kind of merge commit",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'create table');",
+			"call dolt_branch('ff');",
+			"call dolt_branch('threeway');",
+			"call dolt_checkout('-b', 'other');",
+			"insert into t values (1);",
+			"call dolt_commit('-am', 'insert on other');",
+			"call dolt_checkout('threeway');",
+			"insert into t values (2);",
+			"call dolt_commit('-am', 'insert on threeway');",
+			"call dolt_checkout('ff');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_merge('--no-ff', '--author', 'Jane Doe <jane@example.com>', 'other');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query:    "select committer, email from dolt_log limit 1;",
+				Expected: []sql.Row{{"Jane Doe", "jane@example.com"}},
+			},
+			{
+				Query:            "call dolt_checkout('threeway');",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "call dolt_merge('--author', 'John Doe <john@example.com>', 'other');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query:    "select committer, email from dolt_log limit 1;",
+				Expected: []sql.Row{{"John Doe", "john@example.com"}},
+			},
+		},
+	},
 }
 
 var KeylessMergeCVsAndConflictsScripts = []queries.ScriptTest{
@@ -2697,6 +3233,70 @@ var Dolt1ConflictTableNameTableTests = []queries.ScriptTest{
 // MergeArtifactsScripts tests new format merge behavior where
 // existing violations and conflicts are merged together.
 var MergeArtifactsScripts = []queries.ScriptTest{
+	{
+		Name: "completing a merge without a message uses the merge's message",
+		SetUpScript: []string{
+			"CREATE table t (pk int PRIMARY KEY, col1 int);",
+			"INSERT INTO t VALUES (1, 1), (2, 2);",
+			"CALL DOLT_COMMIT('-Am', 'create table');",
+			"CALL DOLT_BRANCH('right');",
+			"CALL DOLT_BRANCH('right2');",
+			"UPDATE t set col1 = 100 where pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'left edit');",
+			"CALL DOLT_BRANCH('left2');",
+			"CALL DOLT_CHECKOUT('right');",
+			"UPDATE t set col1 = -100 where pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'right edit');",
+			"CALL DOLT_CHECKOUT('right2');",
+			"UPDATE t set col1 = -100 where pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'right edit');",
+			"CALL DOLT_CHECKOUT('main');",
+			"SET autocommit = 0;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_MERGE('right');",
+				Expected: []sql.Row{{"", 0, 1}},
+			},
+			{
+				Query:    "CALL DOLT_CONFLICTS_RESOLVE('--ours', 't');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:            "CALL DOLT_COMMIT();",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "SELECT message FROM dolt_log LIMIT 1;",
+				Expected: []sql.Row{{"Merge branch 'right' into main"}},
+			},
+			{
+				Query:    "CALL DOLT_CHECKOUT('left2');",
+				Expected: []sql.Row{{0, "Switched to branch 'left2'"}},
+			},
+			{
+				Query:    "CALL DOLT_MERGE('right2', '-m', 'a custom message');",
+				Expected: []sql.Row{{"", 0, 1}},
+			},
+			{
+				Query:    "CALL DOLT_CONFLICTS_RESOLVE('--theirs', 't');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:            "CALL DOLT_COMMIT();",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "SELECT message FROM dolt_log LIMIT 1;",
+				Expected: []sql.Row{{"a custom message"}},
+			},
+			{
+				// with no merge in progress, a message is still required
+				Query:          "CALL DOLT_COMMIT('--allow-empty');",
+				ExpectedErrStr: "Must provide commit message.",
+			},
+		},
+	},
 	{
 		Name: "conflicts on different branches can be merged",
 		SetUpScript: []string{
@@ -3372,6 +3972,111 @@ var MergeArtifactsScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "dolt_merge with --prune-violations deletes rows violating foreign keys",
+		SetUpScript: []string{
+			"CREATE table parent (pk int PRIMARY KEY, col1 int);",
+			"CREATE table child (pk int PRIMARY KEY, parent_fk int, FOREIGN KEY (parent_fk) REFERENCES parent(pk));",
+			"INSERT INTO parent VALUES (1, 1), (2, 2);",
+			"CALL DOLT_COMMIT('-Am', 'setup');",
+			"CALL DOLT_BRANCH('right');",
+			"DELETE FROM parent where pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'delete parent 1');",
+			"CALL DOLT_CHECKOUT('right');",
+			"INSERT INTO child VALUES (1, 1), (2, 2);",
+			"CALL DOLT_COMMIT('-am', 'insert children');",
+			"CALL DOLT_CHECKOUT('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "CALL DOLT_MERGE('--prune-violations', 'primary key', 'right');",
+				ExpectedErrStr: "unknown constraint violation type 'primary key', expected one of 'foreign key', 'unique index', 'check constraint' or 'not null'",
+			},
+			{
+				Query:    "CALL DOLT_MERGE('--prune-violations', 'foreign key', 'right');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query:    "select * from dolt_constraint_violations;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select * from child;",
+				Expected: []sql.Row{{2, 2}},
+			},
+			{
+				Query:    "select * from child where parent_fk = 1;",
+				Expected: []sql.Row{},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge with --prune-violations keeps violations of other types",
+		SetUpScript: []string{
+			"CREATE table parent (pk int PRIMARY KEY, col1 int);",
+			"CREATE table child (pk int PRIMARY KEY, parent_fk int, FOREIGN KEY (parent_fk) REFERENCES parent(pk));",
+			"INSERT INTO parent VALUES (1, 1), (2, 2);",
+			"CALL DOLT_COMMIT('-Am', 'setup');",
+			"CALL DOLT_BRANCH('right');",
+			"DELETE FROM parent where pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'delete parent 1');",
+			"CALL DOLT_CHECKOUT('right');",
+			"INSERT INTO child VALUES (1, 1), (2, 2);",
+			"CALL DOLT_COMMIT('-am', 'insert children');",
+			"CALL DOLT_CHECKOUT('main');",
+			"set dolt_force_transaction_commit = on;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_MERGE('--prune-violations', 'unique index,not null', 'right');",
+				Expected: []sql.Row{{"", 0, 1}},
+			},
+			{
+				Query:    "select violation_type, pk, parent_fk from dolt_constraint_violations_child;",
+				Expected: []sql.Row{{uint64(merge.CvType_ForeignKey), 1, 1}},
+			},
+			{
+				Query:    "select * from child;",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge with --prune-violations deletes only the incoming rows violating unique indexes",
+		SetUpScript: []string{
+			"CREATE table t (pk int PRIMARY KEY, col1 int, UNIQUE KEY (col1));",
+			"INSERT INTO t VALUES (1, 1);",
+			"CALL DOLT_COMMIT('-Am', 'setup');",
+			"CALL DOLT_BRANCH('right');",
+			"INSERT INTO t VALUES (2, 2), (6, 6);",
+			"CALL DOLT_COMMIT('-am', 'insert on main');",
+			"CALL DOLT_CHECKOUT('right');",
+			"INSERT INTO t VALUES (3, 2), (4, 6), (5, 5);",
+			"CALL DOLT_COMMIT('-am', 'insert on right');",
+			"CALL DOLT_CHECKOUT('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:                           "CALL DOLT_MERGE('--prune-violations', 'unique index', 'right');",
+				Expected:                        []sql.Row{{doltCommit, 0, 0}},
+				ExpectedWarning:                 1105,
+				ExpectedWarningsCount:           1,
+				ExpectedWarningMessageSubstring: "2 rows were deleted for constraint violations from table t",
+			},
+			{
+				Query:    "select * from dolt_merge_stats;",
+				Expected: []sql.Row{{"t", 0, 2}},
+			},
+			{
+				Query:    "select * from dolt_constraint_violations;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, 1}, {2, 2}, {5, 5}, {6, 6}},
+			},
+		},
+	},
 }
 
 var SchemaConflictScripts = []queries.ScriptTest{
@@ -3439,6 +4144,54 @@ var SchemaConflictScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "dolt_conflicts_summary counts every kind of merge artifact",
+		SetUpScript: []string{
+			"set @@autocommit=0;",
+			"create table t (pk int primary key, c0 varchar(20))",
+			"create table summary (pk int primary key, c0 int)",
+			"create table parent (pk int primary key)",
+			"create table child (pk int primary key, parent_fk int, foreign key (parent_fk) references parent (pk))",
+			"insert into parent values (1)",
+			"call dolt_commit('-Am', 'added tables')",
+			"call dolt_checkout('-b', 'other')",
+			"alter table t modify column c0 int",
+			"insert into summary values (1, 1), (2, 2)",
+			"insert into child values (1, 1)",
+			"call dolt_commit('-am', 'changes on branch other')",
+			"call dolt_checkout('main')",
+			"alter table t modify column c0 datetime(6)",
+			"insert into summary values (1, 10), (2, 20)",
+			"delete from parent where pk = 1",
+			"call dolt_commit('-am', 'changes on branch main')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select * from dolt_conflicts_summary",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "call dolt_merge('other')",
+				Expected: []sql.Row{{"", 0, 1}},
+			},
+			{
+				Query: "select * from dolt_conflicts_summary",
+				Expected: []sql.Row{
+					{"child", uint64(0), uint64(0), uint64(1)},
+					{"summary", uint64(2), uint64(0), uint64(0)},
+					{"t", uint64(0), uint64(1), uint64(0)},
+				},
+			},
+			{
+				Query:    "call dolt_merge('--abort')",
+				Expected: []sql.Row{{"", 0, 0}},
+			},
+			{
+				Query:    "select * from dolt_conflicts_summary",
+				Expected: []sql.Row{},
+			},
+		},
+	},
 }
 
 // OldFormatMergeConflictsAndCVsScripts tests old format merge behavior
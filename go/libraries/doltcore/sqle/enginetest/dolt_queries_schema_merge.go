@@ -1526,6 +1526,37 @@ var SchemaChangeTestsTypeChanges = []MergeScriptTest{
 			},
 		},
 	},
+	{
+		Name: "VARCHAR widened differently on both sides",
+		AncSetUpScript: []string{
+			"set autocommit = 0;",
+			"CREATE table t (pk int primary key, col1 VARCHAR(10));",
+			"INSERT into t values (1, '123');",
+			"alter table t add index idx1 (col1);",
+		},
+		RightSetUpScript: []string{
+			"alter table t modify column col1 VARCHAR(30);",
+			"INSERT into t values (2, '12345678901234567890');",
+		},
+		LeftSetUpScript: []string{
+			"alter table t modify column col1 VARCHAR(20);",
+			"INSERT into t values (3, '123456789012345');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_merge('right');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query:    "select column_type from information_schema.columns where table_name = 't' and column_name = 'col1';",
+				Expected: []sql.Row{{"varchar(30)"}},
+			},
+			{
+				Query:    "select pk, col1 from t order by pk;",
+				Expected: []sql.Row{{1, "123"}, {2, "12345678901234567890"}, {3, "123456789012345"}},
+			},
+		},
+	},
 }
 
 var SchemaChangeTestsSchemaConflicts = []MergeScriptTest{
@@ -3307,4 +3307,71 @@ var MultiDbSavepointTests = []queries.TransactionTest{
 			},
 		},
 	},
+	{
+		Name: "rollback to savepoint restores other branches and temporary tables",
+		SetUpScript: []string{
+			"create table t (x int primary key)",
+			"insert into t values (1)",
+			"call dolt_commit('-Am', 'created table t')",
+			"call dolt_branch('b1')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ set autocommit = off",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "/* client a */ create temporary table tmp (x int primary key)",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "/* client a */ insert into tmp values (1)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ select * from `mydb/b1`.t",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "/* client a */ savepoint sp1",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ insert into t values (2)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ insert into `mydb/b1`.t values (3)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ insert into tmp values (2)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ rollback to sp1",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select * from t",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "/* client a */ select * from `mydb/b1`.t",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "/* client a */ select * from tmp",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "/* client a */ insert into tmp values (3)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ select * from tmp order by x",
+				Expected: []sql.Row{{1}, {3}},
+			},
+		},
+	},
 }
@@ -0,0 +1,138 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/analyzer"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/planbuilder"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// EventWithStatus is an event of a database along with the details parsed from its CREATE EVENT statement, as
+// returned by Database.GetEventsWithStatus.
+type EventWithStatus struct {
+	sql.EventDefinition
+	// Details are the details of the event, including its status, schedule and body.
+	Details sql.EventDetails
+	// Enabled is whether the event is enabled. Events that are disabled, or disabled on replicas, are never executed.
+	Enabled bool
+	// NextExecution is the next time the event is scheduled to be executed at or after the time of the current query,
+	// or the zero time if it's disabled or isn't scheduled to be executed again.
+	NextExecution time.Time
+}
+
+// GetEventsWithStatus returns the events of this database, each with the details parsed from its CREATE EVENT
+// statement, so that callers don't need to parse the statements themselves. Each statement is parsed in this
+// database, under the SQL mode it was created with, and only once. The statement's time expressions, such as
+// CURRENT_TIMESTAMP, are evaluated as of the current query.
+func (db Database) GetEventsWithStatus(ctx *sql.Context) ([]EventWithStatus, error) {
+	events, err := db.GetEvents(ctx)
+	if err != nil || len(events) == 0 {
+		return nil, err
+	}
+
+	// the body of an event refers to the tables of its database, so it has to be parsed with that as the current
+	// database
+	prevDb := ctx.GetCurrentDatabase()
+	ctx.SetCurrentDatabase(db.RevisionQualifiedName())
+	defer ctx.SetCurrentDatabase(prevDb)
+	catalog := analyzer.NewCatalog(dsess.DSessFromSess(ctx.Session).Provider())
+
+	res := make([]EventWithStatus, len(events))
+	for i, event := range events {
+		node, err := planbuilder.ParseWithOptions(ctx, catalog, event.CreateStatement, sql.NewSqlModeFromString(event.SqlMode).ParserOptions())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse event %s: %w", event.Name, err)
+		}
+		createEvent, ok := node.(*plan.CreateEvent)
+		if !ok {
+			return nil, sql.ErrEventCreateStatementInvalid.New(event.CreateStatement)
+		}
+
+		details, err := createEvent.GetEventDetails(ctx, event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = EventWithStatus{
+			EventDefinition: event,
+			Details:         details,
+			Enabled:         createEvent.Status == plan.EventStatus_Enable,
+		}
+		if res[i].Enabled {
+			res[i].NextExecution, err = nextEventExecution(ctx, createEvent, details)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return res, nil
+}
+
+// nextEventExecution returns the first time the event given is scheduled to be executed at or after the time of the
+// current query, or the zero time if it isn't scheduled to be executed again.
+func nextEventExecution(ctx *sql.Context, event *plan.CreateEvent, details sql.EventDetails) (time.Time, error) {
+	now := ctx.QueryTime()
+	if details.HasExecuteAt {
+		if details.ExecuteAt.Before(now) {
+			return time.Time{}, nil
+		}
+		return details.ExecuteAt, nil
+	}
+
+	delta, err := event.Every.EvalDelta(ctx, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	next := details.Starts
+	if next.Before(now) {
+		if delta.Years == 0 && delta.Months == 0 {
+			// the interval is a fixed duration, so skip straight to the last execution before now
+			d := delta.Add(next).Sub(next)
+			if d <= 0 {
+				return time.Time{}, fmt.Errorf("invalid interval for event %s: %s", details.Name, details.ExecuteEvery)
+			}
+			next = next.Add(now.Sub(next) / d * d)
+		}
+		next, err = nextAfter(next, now, delta)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	if details.HasEnds && next.After(details.Ends) {
+		return time.Time{}, nil
+	}
+	return next, nil
+}
+
+// nextAfter returns the first time at or after |now| that's reached by adding |delta| to |t| any number of times.
+func nextAfter(t, now time.Time, delta *expression.TimeDelta) (time.Time, error) {
+	for t.Before(now) {
+		next := delta.Add(t)
+		if !next.After(t) {
+			return time.Time{}, fmt.Errorf("invalid event interval")
+		}
+		t = next
+	}
+	return t, nil
+}
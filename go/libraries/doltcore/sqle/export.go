@@ -0,0 +1,140 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/json"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/parquet"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/csv"
+	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
+)
+
+// ExportFormat is a file format that Database.StreamExport can write.
+type ExportFormat int
+
+const (
+	ExportFormatCSV ExportFormat = iota
+	ExportFormatJSON
+	ExportFormatParquet
+)
+
+// String returns the name of the format, as used by dolt table export.
+func (f ExportFormat) String() string {
+	switch f {
+	case ExportFormatCSV:
+		return "csv"
+	case ExportFormatJSON:
+		return "json"
+	case ExportFormatParquet:
+		return "parquet"
+	default:
+		return fmt.Sprintf("unknown export format %d", int(f))
+	}
+}
+
+// StreamExportOpts are the options for Database.StreamExport.
+type StreamExportOpts struct {
+	// Filter, if not nil, limits the export to the rows it evaluates to true for, like a WHERE clause. It's evaluated
+	// against rows in the table's schema, so columns are referenced by their position in the table.
+	Filter sql.Expression
+}
+
+// StreamExport writes the rows of the table named in the working set to |w| in the format given, the same way dolt
+// table export writes them to a file. Rows are read from the table and written one at a time, so the table is never
+// held in memory, and the export stops with the context's error if the context is canceled. |w| isn't closed. The
+// rows exported can be limited with |opts|.Filter, which is applied to each row as it's read rather than used to look
+// up rows in an index.
+func (db Database) StreamExport(ctx *sql.Context, tableName string, w io.Writer, format ExportFormat, opts StreamExportOpts) (err error) {
+	stbl, ok, err := db.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return err
+	} else if !ok {
+		return sql.ErrTableNotFound.New(tableName)
+	}
+
+	var tbl *DoltTable
+	switch t := stbl.(type) {
+	case *AlterableDoltTable:
+		tbl = t.DoltTable
+	case *WritableDoltTable:
+		tbl = t.DoltTable
+	case *DoltTable:
+		tbl = t
+	default:
+		return fmt.Errorf("cannot export table %s", tableName)
+	}
+
+	var wr table.SqlRowWriter
+	switch format {
+	case ExportFormatCSV:
+		wr, err = csv.NewCSVWriter(iohelp.NopWrCloser(w), tbl.sch, csv.NewCSVInfo())
+	case ExportFormatJSON:
+		wr, err = json.NewJSONWriter(iohelp.NopWrCloser(w), tbl.sch)
+	case ExportFormatParquet:
+		wr, err = parquet.NewParquetRowWriter(tbl.sqlSch.Schema, iohelp.NopWrCloser(w))
+	default:
+		return fmt.Errorf("cannot export table %s: %s", tableName, format.String())
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// the writer has to be closed to finish the output, such as the closing bracket of JSON
+		cerr := wr.Close(ctx)
+		if err == nil {
+			err = cerr
+		}
+	}()
+
+	partitions, err := tbl.Partitions(ctx)
+	if err != nil {
+		return err
+	}
+	iter := sql.NewTableRowIter(ctx, tbl, partitions)
+	defer iter.Close(ctx)
+
+	for {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		row, err := iter.Next(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if opts.Filter != nil {
+			res, err := sql.EvaluateCondition(ctx, opts.Filter, row)
+			if err != nil {
+				return err
+			}
+			if !sql.IsTrue(res) {
+				continue
+			}
+		}
+
+		if err = wr.WriteSqlRow(ctx, row); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,302 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlfmt"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+)
+
+// ExportDDL writes the statements that create the schema of this database to |w|, with tables in the order returned by
+// TableDependencyOrder.
+func (db Database) ExportDDL(ctx *sql.Context, w io.Writer) error {
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return err
+	}
+	deps, err := db.TableDependencyOrder(ctx)
+	if err != nil {
+		return err
+	}
+	fkc, err := root.GetForeignKeyCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	schemas := make(map[string]schema.Schema, len(deps.Order))
+	for _, name := range deps.Order {
+		tbl, ok, err := root.GetTable(ctx, name)
+		if err != nil {
+			return err
+		} else if !ok {
+			return sql.ErrTableNotFound.New(name)
+		}
+		if schemas[name], err = tbl.GetSchema(ctx); err != nil {
+			return err
+		}
+	}
+
+	sessionMode, err := ctx.Session.GetSessionVariable(ctx, "sql_mode")
+	if err != nil {
+		return err
+	}
+	ew := &ddlWriter{w: w, sessionMode: fmt.Sprint(sessionMode)}
+
+	isDeferred := make(map[string]bool, len(deps.Deferred))
+	for _, fk := range deps.Deferred {
+		isDeferred[fk.Name] = true
+	}
+	for _, name := range deps.Order {
+		sch := schemas[name]
+		declared, _ := fkc.KeysForTable(name)
+		var fks []doltdb.ForeignKey
+		for _, fk := range declared {
+			if !isDeferred[fk.Name] {
+				fks = append(fks, fk)
+			}
+		}
+		pkSch, err := sqlutil.FromDoltSchema(name, sch)
+		if err != nil {
+			return err
+		}
+		stmt, err := diff.GenerateCreateTableStatement(name, sch, pkSch, fks, schemas)
+		if err != nil {
+			return err
+		}
+		ew.statement(strings.TrimSuffix(stmt, ";"), "", false)
+	}
+	for _, fk := range deps.Deferred {
+		stmt := sqlfmt.AlterTableAddForeignKeyStmt(fk, schemas[fk.TableName], schemas[fk.ReferencedTableName])
+		ew.statement(strings.TrimSuffix(stmt, ";"), "", false)
+	}
+
+	views, err := db.AllViews(ctx)
+	if err != nil {
+		return err
+	}
+	for _, view := range views {
+		ew.statement(view.CreateViewStatement, view.SqlMode, false)
+	}
+
+	triggers, err := db.GetTriggers(ctx)
+	if err != nil {
+		return err
+	}
+	// triggers can name other triggers to come before or after, so they're created in the order they were before
+	sort.SliceStable(triggers, func(i, j int) bool {
+		return triggers[i].CreatedAt.Before(triggers[j].CreatedAt)
+	})
+	for _, trigger := range triggers {
+		ew.statement(trigger.CreateStatement, trigger.SqlMode, true)
+	}
+
+	events, err := db.GetEvents(ctx)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		ew.statement(event.CreateStatement, event.SqlMode, true)
+	}
+
+	procedures, err := db.GetStoredProcedures(ctx)
+	if err != nil {
+		return err
+	}
+	sort.Slice(procedures, func(i, j int) bool {
+		return procedures[i].Name < procedures[j].Name
+	})
+	for _, procedure := range procedures {
+		ew.statement(procedure.CreateStatement, procedure.SqlMode, true)
+	}
+
+	return ew.err
+}
+
+// ddlWriter writes the statements of Database.ExportDDL, keeping the first error it gets so that the caller only
+// needs to check it once.
+type ddlWriter struct {
+	w           io.Writer
+	sessionMode string
+	err         error
+}
+
+// statement writes |stmt|, wrapped in DELIMITER statements if |compound| is true, under |sqlMode| if it's given.
+func (ew *ddlWriter) statement(stmt, sqlMode string, compound bool) {
+	changeMode := sqlMode != "" && !strings.EqualFold(sqlMode, ew.sessionMode)
+	if changeMode {
+		ew.printf("SET @previousSqlMode=@@SQL_MODE;\nSET @@SQL_MODE='%s';\n", sqlMode)
+	}
+	if compound {
+		ew.printf("DELIMITER ;;\n%s;;\nDELIMITER ;\n", stmt)
+	} else {
+		ew.printf("%s;\n", stmt)
+	}
+	if changeMode {
+		ew.printf("SET @@SQL_MODE=@previousSqlMode;\n")
+	}
+}
+
+func (ew *ddlWriter) printf(format string, args ...interface{}) {
+	if ew.err == nil {
+		_, ew.err = fmt.Fprintf(ew.w, format, args...)
+	}
+}
+
+// foreignKeyTableOrder orders |tableNames| so that each table comes after the tables it references, and returns the
+// foreign keys deferred to break cycles.
+func foreignKeyTableOrder(tableNames []string, fks []doltdb.ForeignKey) (order []string, deferred []doltdb.ForeignKey) {
+	inSet := make(map[string]bool, len(tableNames))
+	for _, name := range tableNames {
+		inSet[name] = true
+	}
+	parents := make(map[string][]doltdb.ForeignKey)
+	for _, fk := range fks {
+		if fk.TableName != fk.ReferencedTableName && inSet[fk.TableName] && inSet[fk.ReferencedTableName] {
+			parents[fk.TableName] = append(parents[fk.TableName], fk)
+		}
+	}
+
+	placed := make(map[string]bool, len(tableNames))
+	ready := func(name string) bool {
+		for _, fk := range parents[name] {
+			if !placed[fk.ReferencedTableName] {
+				return false
+			}
+		}
+		return true
+	}
+	for len(order) < len(tableNames) {
+		next := ""
+		for _, name := range tableNames {
+			if !placed[name] && ready(name) {
+				next = name
+				break
+			}
+		}
+		if next == "" {
+			for _, name := range tableNames {
+				if !placed[name] {
+					next = name
+					break
+				}
+			}
+			for _, fk := range parents[next] {
+				if !placed[fk.ReferencedTableName] {
+					deferred = append(deferred, fk)
+				}
+			}
+		}
+		placed[next] = true
+		order = append(order, next)
+	}
+	return order, deferred
+}
+
+// foreignKeyCycles returns the tables of |tableNames| whose foreign keys reference each other in a cycle.
+func foreignKeyCycles(tableNames []string, fks []doltdb.ForeignKey) [][]string {
+	inSet := make(map[string]bool, len(tableNames))
+	for _, name := range tableNames {
+		inSet[name] = true
+	}
+	refs := make(map[string][]string)
+	for _, fk := range fks {
+		if fk.TableName != fk.ReferencedTableName && inSet[fk.TableName] && inSet[fk.ReferencedTableName] {
+			refs[fk.TableName] = append(refs[fk.TableName], fk.ReferencedTableName)
+		}
+	}
+
+	reachable := make(map[string]map[string]bool, len(tableNames))
+	for _, name := range tableNames {
+		seen := make(map[string]bool)
+		stack := []string{name}
+		for len(stack) > 0 {
+			curr := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, ref := range refs[curr] {
+				if !seen[ref] {
+					seen[ref] = true
+					stack = append(stack, ref)
+				}
+			}
+		}
+		reachable[name] = seen
+	}
+
+	var cycles [][]string
+	inCycle := make(map[string]bool)
+	for _, name := range tableNames {
+		if inCycle[name] || !reachable[name][name] {
+			continue
+		}
+		var cycle []string
+		for _, other := range tableNames {
+			if reachable[name][other] && reachable[other][name] {
+				cycle = append(cycle, other)
+				inCycle[other] = true
+			}
+		}
+		cycles = append(cycles, cycle)
+	}
+	return cycles
+}
+
+// TableDependencies is an order to create or load the user tables of a database in, as returned by
+// Database.TableDependencyOrder.
+type TableDependencies struct {
+	// Order lists the user tables so that every table comes after the tables referenced by its foreign keys, other
+	// than the foreign keys in Deferred.
+	Order []string
+	// Cycles lists the tables of each cycle of foreign key references, in the order they appear in Order.
+	Cycles [][]string
+	// Deferred are the foreign keys of tables in Cycles that reference tables later in Order, which were left out to
+	// break the cycles. They must be added once all the tables exist.
+	Deferred []doltdb.ForeignKey
+}
+
+// TableDependencyOrder returns the user tables ordered so that referenced tables come before the tables that
+// reference them.
+func (db Database) TableDependencyOrder(ctx *sql.Context) (TableDependencies, error) {
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return TableDependencies{}, err
+	}
+	tableNames, err := db.GetTableNames(ctx)
+	if err != nil {
+		return TableDependencies{}, err
+	}
+	sort.Strings(tableNames)
+	fkc, err := root.GetForeignKeyCollection(ctx)
+	if err != nil {
+		return TableDependencies{}, err
+	}
+
+	fks := fkc.AllKeys()
+	order, deferred := foreignKeyTableOrder(tableNames, fks)
+	return TableDependencies{
+		Order:    order,
+		Cycles:   foreignKeyCycles(order, fks),
+		Deferred: deferred,
+	}, nil
+}
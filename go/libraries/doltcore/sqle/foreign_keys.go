@@ -0,0 +1,221 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+var ErrForeignKeyMissingIndex = errors.NewKind("missing index for foreign key `%s` on table `%s`")
+
+// addForeignKeysToNewTable returns |root| with the foreign keys |fks| declared by the new table named, which has the
+// schema |sch|, added to it.
+func (db Database) addForeignKeysToNewTable(ctx *sql.Context, root *doltdb.RootValue, tableName string, sch schema.Schema, fks []sql.ForeignKeyConstraint) (*doltdb.RootValue, error) {
+	tbl, ok, err := root.GetTable(ctx, tableName)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, sql.ErrTableNotFound.New(tableName)
+	}
+
+	fkc, err := root.GetForeignKeyCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sqlFk := range fks {
+		// empty string foreign key names are given a generated name by the foreign key collection
+		if sqlFk.Name != "" && !doltdb.IsValidIdentifier(sqlFk.Name) {
+			return nil, fmt.Errorf("invalid foreign key name `%s`", sqlFk.Name)
+		}
+		if !strings.EqualFold(sqlFk.Table, tableName) {
+			return nil, fmt.Errorf("foreign key `%s` is declared by table `%s`, not by the new table `%s`", sqlFk.Name, sqlFk.Table, tableName)
+		}
+		if (sqlFk.Database != "" && !strings.EqualFold(sqlFk.Database, db.Name())) || (sqlFk.ParentDatabase != "" && !strings.EqualFold(sqlFk.ParentDatabase, db.Name())) {
+			return nil, fmt.Errorf("only foreign keys on the same database are currently supported")
+		}
+
+		onUpdateRefAction, err := parseFkReferentialAction(sqlFk.OnUpdate)
+		if err != nil {
+			return nil, err
+		}
+		onDeleteRefAction, err := parseFkReferentialAction(sqlFk.OnDelete)
+		if err != nil {
+			return nil, err
+		}
+
+		// The referenced table must exist now, so the foreign key is always resolved
+		sqlFk.IsResolved = true
+		doltFk, err := newForeignKey(ctx, root, tbl, sch, sqlFk, onUpdateRefAction, onDeleteRefAction)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok, err := hasIndexOnColumns(sch, sqlFk.Columns); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, ErrForeignKeyMissingIndex.New(sqlFk.Name, tableName)
+		}
+		refSch := sch
+		if !sqlFk.IsSelfReferential() {
+			refTbl, _, _, err := root.GetTableInsensitive(ctx, sqlFk.ParentTable)
+			if err != nil {
+				return nil, err
+			}
+			refSch, err = refTbl.GetSchema(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if ok, err := hasIndexOnColumns(refSch, sqlFk.ParentColumns); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, sql.ErrForeignKeyMissingReferenceIndex.New(sqlFk.Name, sqlFk.ParentTable)
+		}
+
+		if err = fkc.AddKeys(doltFk); err != nil {
+			return nil, err
+		}
+	}
+
+	return root.PutForeignKeyCollection(ctx, fkc)
+}
+
+// hasIndexOnColumns returns whether the columns named are a prefix of one of the indexes of |sch| or of its primary
+// key, in any order, so that a foreign key on them can use it.
+func hasIndexOnColumns(sch schema.Schema, cols []string) (bool, error) {
+	if _, ok, err := findIndexWithPrefix(sch, cols); err != nil || ok {
+		return ok, err
+	}
+	ok, prefixCount := colsAreIndexSubset(lowercaseSlice(cols), lowercaseSlice(sch.GetPKCols().GetColumnNames()))
+	return ok && prefixCount == len(cols), nil
+}
+
+// ForeignKeysReferencing returns the foreign keys of other tables that reference the table named, sorted by name.
+func (db Database) ForeignKeysReferencing(ctx *sql.Context, tableName string) ([]sql.ForeignKeyConstraint, error) {
+	root, tableName, fkc, err := db.foreignKeysForTable(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	_, referencedBy := fkc.KeysForTable(tableName)
+	fks := make([]doltdb.ForeignKey, 0, len(referencedBy))
+	for _, fk := range referencedBy {
+		if !strings.EqualFold(fk.TableName, tableName) {
+			fks = append(fks, fk)
+		}
+	}
+
+	return db.toForeignKeyConstraints(ctx, root, fks)
+}
+
+// ForeignKeysDeclaredBy returns the foreign keys declared by the table named, sorted by name.
+func (db Database) ForeignKeysDeclaredBy(ctx *sql.Context, tableName string) ([]sql.ForeignKeyConstraint, error) {
+	root, tableName, fkc, err := db.foreignKeysForTable(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	declared, _ := fkc.KeysForTable(tableName)
+	return db.toForeignKeyConstraints(ctx, root, declared)
+}
+
+// foreignKeysForTable returns the working root, the name of the table |tableName| as it's stored in that root and the
+// root's foreign key collection.
+func (db Database) foreignKeysForTable(ctx *sql.Context, tableName string) (*doltdb.RootValue, string, *doltdb.ForeignKeyCollection, error) {
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	resolved, ok, err := root.ResolveTableName(ctx, tableName)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if !ok {
+		return nil, "", nil, sql.ErrTableNotFound.New(tableName)
+	}
+
+	fkc, err := root.GetForeignKeyCollection(ctx)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return root, resolved, fkc, nil
+}
+
+// toForeignKeyConstraints converts the foreign keys |fks| in |root| to their SQL representation.
+func (db Database) toForeignKeyConstraints(ctx *sql.Context, root *doltdb.RootValue, fks []doltdb.ForeignKey) ([]sql.ForeignKeyConstraint, error) {
+	schemas := make(map[string]schema.Schema)
+	getSchema := func(tableName string) (schema.Schema, error) {
+		if sch, ok := schemas[tableName]; ok {
+			return sch, nil
+		}
+		tbl, ok, err := root.GetTable(ctx, tableName)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, sql.ErrTableNotFound.New(tableName)
+		}
+		sch, err := tbl.GetSchema(ctx)
+		if err != nil {
+			return nil, err
+		}
+		schemas[tableName] = sch
+		return sch, nil
+	}
+
+	constraints := make([]sql.ForeignKeyConstraint, len(fks))
+	for i, fk := range fks {
+		if len(fk.UnresolvedFKDetails.TableColumns) > 0 && len(fk.UnresolvedFKDetails.ReferencedTableColumns) > 0 {
+			constraints[i] = sql.ForeignKeyConstraint{
+				Name:           fk.Name,
+				Database:       db.Name(),
+				Table:          fk.TableName,
+				Columns:        fk.UnresolvedFKDetails.TableColumns,
+				ParentDatabase: db.Name(),
+				ParentTable:    fk.ReferencedTableName,
+				ParentColumns:  fk.UnresolvedFKDetails.ReferencedTableColumns,
+				OnUpdate:       toReferentialAction(fk.OnUpdate),
+				OnDelete:       toReferentialAction(fk.OnDelete),
+				IsResolved:     fk.IsResolved(),
+			}
+			continue
+		}
+
+		childSch, err := getSchema(fk.TableName)
+		if err != nil {
+			return nil, err
+		}
+		parentSch, err := getSchema(fk.ReferencedTableName)
+		if err != nil {
+			return nil, err
+		}
+		constraints[i], err = toForeignKeyConstraint(fk, db.Name(), childSch, parentSch)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return constraints, nil
+}
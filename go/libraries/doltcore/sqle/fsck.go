@@ -0,0 +1,192 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// FsckOpts limits the checks made by Database.Fsck, to bound its running time on large databases.
+type FsckOpts struct {
+	// Tables limits the check to the tables named. Schema fragments are only checked when it's empty, in which case
+	// every table is checked.
+	Tables []string
+	// SampleRows, if positive, limits the consistency check of each secondary index to about that many rows of its
+	// table, spread evenly across the table. Every row is checked when it's zero.
+	SampleRows int
+}
+
+// FsckProblem is a single integrity problem found by Database.Fsck.
+type FsckProblem struct {
+	// Table is the table with the problem. Problems with schema fragments are reported for the dolt_schemas table.
+	Table string
+	// Index is the secondary index with the problem, or empty if the problem isn't with a secondary index.
+	Index string
+	// Err describes the problem.
+	Err error
+}
+
+func (p FsckProblem) Error() string {
+	if p.Index != "" {
+		return fmt.Sprintf("table %s, index %s: %s", p.Table, p.Index, p.Err.Error())
+	}
+	return fmt.Sprintf("table %s: %s", p.Table, p.Err.Error())
+}
+
+// FsckReport is the result of Database.Fsck.
+type FsckReport struct {
+	// TablesChecked is the number of tables checked.
+	TablesChecked int
+	// Problems lists the problems found, which is empty if the database passed the check.
+	Problems []FsckProblem
+}
+
+var errFsckMissingChunk = errors.New("missing chunk")
+
+// Fsck checks the structural integrity of the tables in the working set: that every chunk referenced by a table's
+// rows and indexes exists, that every secondary index has an entry for each of its table's rows, and that every
+// schema fragment in the dolt_schemas table can be used (see ValidateSchemaFragments). Problems found are returned in
+// the report rather than as an error, and nothing is repaired. The checks made can be limited with |opts|. Only the
+// DOLT storage format is supported. The dolt_fsck table function runs it from SQL.
+func (db Database) Fsck(ctx *sql.Context, opts FsckOpts) (FsckReport, error) {
+	if !types.IsFormat_DOLT(db.ddb.Format()) {
+		return FsckReport{}, fmt.Errorf("fsck is only supported for the %s storage format", types.Format_DOLT.VersionString())
+	}
+
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return FsckReport{}, err
+	}
+
+	tableNames := opts.Tables
+	if len(tableNames) == 0 {
+		tableNames, err = root.GetTableNames(ctx)
+		if err != nil {
+			return FsckReport{}, err
+		}
+	}
+
+	var report FsckReport
+	for _, name := range tableNames {
+		tbl, tableName, ok, err := root.GetTableInsensitive(ctx, name)
+		if err != nil {
+			return FsckReport{}, err
+		} else if !ok {
+			return FsckReport{}, sql.ErrTableNotFound.New(name)
+		}
+
+		problems, err := db.fsckTable(ctx, tableName, tbl, opts.SampleRows)
+		if err != nil {
+			return FsckReport{}, err
+		}
+		report.Problems = append(report.Problems, problems...)
+		report.TablesChecked++
+	}
+
+	if len(opts.Tables) == 0 {
+		fragErrs, err := db.ValidateSchemaFragments(ctx)
+		if err != nil {
+			return FsckReport{}, err
+		}
+		for _, fragErr := range fragErrs {
+			report.Problems = append(report.Problems, FsckProblem{Table: doltdb.SchemasTableName, Err: fragErr})
+		}
+	}
+
+	return report, nil
+}
+
+// fsckTable checks the chunks and secondary indexes of a single table for Database.Fsck.
+func (db Database) fsckTable(ctx *sql.Context, tableName string, tbl *doltdb.Table, sampleRows int) ([]FsckProblem, error) {
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	primary := durable.ProllyMapFromIndex(rows)
+
+	var problems []FsckProblem
+	missing, err := db.missingChunk(ctx, primary)
+	if err != nil {
+		return nil, err
+	}
+	if !missing.IsEmpty() {
+		// the indexes can't be checked against the table's rows
+		return []FsckProblem{{Table: tableName, Err: fmt.Errorf("missing chunk %s in row data", missing.String())}}, nil
+	}
+
+	indexes, err := tbl.GetIndexSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, def := range sch.Indexes().AllIndexes() {
+		idx, err := indexes.GetIndex(ctx, sch, def.Name())
+		if err != nil {
+			return nil, err
+		}
+		secondary := durable.ProllyMapFromIndex(idx)
+
+		missing, err = db.missingChunk(ctx, secondary)
+		if err != nil {
+			return nil, err
+		}
+		if !missing.IsEmpty() {
+			problems = append(problems, FsckProblem{Table: tableName, Index: def.Name(), Err: fmt.Errorf("missing chunk %s", missing.String())})
+			continue
+		}
+
+		if err = index.ValidateIndexConsistency(ctx, sch, def, primary, secondary, sampleRows); err != nil {
+			problems = append(problems, FsckProblem{Table: tableName, Index: def.Name(), Err: err})
+		}
+	}
+
+	return problems, nil
+}
+
+// missingChunk returns the address of a chunk referenced by |m| that doesn't exist in the database, or an empty hash if
+// they all exist. Chunks below a missing chunk can't be checked.
+func (db Database) missingChunk(ctx context.Context, m prolly.Map) (hash.Hash, error) {
+	var missing hash.Hash
+	err := m.WalkAddresses(ctx, func(ctx context.Context, addr hash.Hash) error {
+		ok, err := db.ddb.Has(ctx, addr)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			missing = addr
+			return errFsckMissingChunk
+		}
+		return nil
+	})
+	if err != nil && err != errFsckMissingChunk {
+		return hash.Hash{}, err
+	}
+	return missing, nil
+}
@@ -0,0 +1,315 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// ValidateIndexConsistency checks that the secondary index |secondary|, defined by |def|, has an entry for the rows of
+// the table with schema |sch| and row data |primary|. If |sampleRows| is positive, only about that many rows, spread
+// evenly across the table, are checked, which bounds the time taken for large tables. For tables with a primary key,
+// the number of entries in the index must also match the number of rows. Returns an error describing the first
+// inconsistency found.
+func ValidateIndexConsistency(ctx context.Context, sch schema.Schema, def schema.Index, primary, secondary prolly.Map, sampleRows int) error {
+	// Full-Text indexes do not make use of their internal map, so we may safely skip this check
+	if def.IsFullText() {
+		return nil
+	}
+
+	if schema.IsKeyless(sch) {
+		return validateKeylessIndex(ctx, sch, def, primary, secondary, sampleRows)
+	} else {
+		return validatePkIndex(ctx, sch, def, primary, secondary, sampleRows)
+	}
+}
+
+func validateKeylessIndex(ctx context.Context, sch schema.Schema, def schema.Index, primary, secondary prolly.Map, sampleRows int) error {
+	secondary = prolly.ConvertToSecondaryKeylessIndex(secondary)
+	idxDesc, _ := secondary.Descriptors()
+	builder := val.NewTupleBuilder(idxDesc)
+	mapping, err := ordinalMappingsForSecondaryIndex(sch, def)
+	if err != nil {
+		return err
+	}
+	_, vd := primary.Descriptors()
+
+	return iterSampledRows(ctx, primary, sampleRows, func(hashId, value val.Tuple) error {
+		// make secondary index key
+		for i := range mapping {
+			j := mapping.MapOrdinal(i)
+			// first field in |value| is cardinality
+			field := value.GetField(j + 1)
+
+			if shouldDereferenceContent(j+1, vd, i, idxDesc) {
+				field, err = dereferenceContent(ctx, vd, j+1, value, secondary.NodeStore())
+				if err != nil {
+					return err
+				}
+			} else if def.IsSpatial() {
+				geom, _, err := types.GeometryType{}.Convert(field[:len(field)-1])
+				if err != nil {
+					return err
+				}
+				cell := ZCell(geom.(types.GeometryValue))
+				field = cell[:]
+			}
+
+			// Apply prefix lengths if they are configured
+			if len(def.PrefixLengths()) > i {
+				field = trimValueToPrefixLength(field, def.PrefixLengths()[i], vd.Types[j+1].Enc)
+			}
+
+			builder.PutRaw(i, field)
+		}
+		builder.PutRaw(idxDesc.Count()-1, hashId.GetField(0))
+		k := builder.Build(primary.Pool())
+
+		ok, err := secondary.Has(ctx, k)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("index key %s not found in index %s", builder.Desc.Format(k), def.Name())
+		}
+		return nil
+	})
+}
+
+func validatePkIndex(ctx context.Context, sch schema.Schema, def schema.Index, primary, secondary prolly.Map, sampleRows int) error {
+	// secondary indexes have empty values
+	idxDesc, _ := secondary.Descriptors()
+	builder := val.NewTupleBuilder(idxDesc)
+	mapping, err := ordinalMappingsForSecondaryIndex(sch, def)
+	if err != nil {
+		return err
+	}
+	kd, vd := primary.Descriptors()
+
+	// Before we walk through the primary index data and validate that every row in the primary index exists in the
+	// secondary index, we also check that the primary index and secondary index have the same number of rows.
+	// Otherwise, we won't catch if the secondary index has extra, bogus data in it.
+	totalSecondaryCount, err := secondary.Count()
+	if err != nil {
+		return err
+	}
+	totalPrimaryCount, err := primary.Count()
+	if err != nil {
+		return err
+	}
+	if totalSecondaryCount != totalPrimaryCount {
+		return fmt.Errorf("primary index row count (%d) does not match secondary index row count (%d)",
+			totalPrimaryCount, totalSecondaryCount)
+	}
+
+	pkSize := kd.Count()
+	return iterSampledRows(ctx, primary, sampleRows, func(key, value val.Tuple) error {
+		// make secondary index key
+		for i := range mapping {
+			j := mapping.MapOrdinal(i)
+			if j < pkSize {
+				builder.PutRaw(i, key.GetField(j))
+			} else {
+				field := value.GetField(j - pkSize)
+
+				if shouldDereferenceContent(j-pkSize, vd, i, idxDesc) {
+					field, err = dereferenceContent(ctx, vd, j-pkSize, value, secondary.NodeStore())
+					if err != nil {
+						return err
+					}
+				} else if def.IsSpatial() {
+					geom, _, err := types.GeometryType{}.Convert(field[:len(field)-1])
+					if err != nil {
+						return err
+					}
+					cell := ZCell(geom.(types.GeometryValue))
+					field = cell[:]
+				}
+
+				// Apply prefix lengths if they are configured
+				if len(def.PrefixLengths()) > i {
+					field = trimValueToPrefixLength(field, def.PrefixLengths()[i], vd.Types[j-pkSize].Enc)
+				}
+
+				builder.PutRaw(i, field)
+			}
+		}
+		k := builder.Build(primary.Pool())
+
+		ok, err := secondary.Has(ctx, k)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("index key %v not found in index %s", builder.Desc.Format(k), def.Name())
+		}
+		return nil
+	})
+}
+
+// iterSampledRows calls |cb| for each row of |m|. If |sampleRows| is positive and |m| has more rows than that, it's
+// only called for |sampleRows| rows spread evenly across the map.
+func iterSampledRows(ctx context.Context, m prolly.Map, sampleRows int, cb func(key, value val.Tuple) error) error {
+	count, err := m.Count()
+	if err != nil {
+		return err
+	}
+
+	if sampleRows <= 0 || count <= sampleRows {
+		iter, err := m.IterAll(ctx)
+		if err != nil {
+			return err
+		}
+		for {
+			key, value, err := iter.Next(ctx)
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if err = cb(key, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	stride := uint64(count / sampleRows)
+	for i := uint64(0); i < uint64(sampleRows); i++ {
+		iter, err := m.IterOrdinalRange(ctx, i*stride, i*stride+1)
+		if err != nil {
+			return err
+		}
+		key, value, err := iter.Next(ctx)
+		if err != nil {
+			return err
+		}
+		if err = cb(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shouldDereferenceContent returns true if address encoded content should be dereferenced when
+// building a key for a secondary index. This is determined by looking at the encoding of the field
+// in the main table (|tablePos| and |tableValueDescriptor|) and the encoding of the field in the index
+// (|indexPos| and |indexKeyDescriptor|) and seeing if one is an address encoding and the other is not.
+func shouldDereferenceContent(tablePos int, tableValueDescriptor val.TupleDesc, indexPos int, indexKeyDescriptor val.TupleDesc) bool {
+	if tableValueDescriptor.Types[tablePos].Enc == val.StringAddrEnc && indexKeyDescriptor.Types[indexPos].Enc != val.StringAddrEnc {
+		return true
+	}
+
+	if tableValueDescriptor.Types[tablePos].Enc == val.BytesAddrEnc && indexKeyDescriptor.Types[indexPos].Enc != val.BytesAddrEnc {
+		return true
+	}
+
+	return false
+}
+
+// dereferenceContent dereferences an address encoded field (e.g. TEXT, BLOB) to load the content
+// and return a []byte. |tableValueDescriptor| is the tuple descriptor for the value tuple of the main
+// table, |tablePos| is the field index into the value tuple, and |tuple| is the value tuple from the
+// main table.
+func dereferenceContent(ctx context.Context, tableValueDescriptor val.TupleDesc, tablePos int, tuple val.Tuple, ns tree.NodeStore) ([]byte, error) {
+	v, err := GetField(ctx, tableValueDescriptor, tablePos, tuple, ns)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+
+	switch x := v.(type) {
+	case string:
+		return []byte(x), nil
+	case []byte:
+		return x, nil
+	default:
+		return nil, fmt.Errorf("unexpected type for address encoded content: %T", v)
+	}
+}
+
+// trimValueToPrefixLength trims |value| by truncating the bytes after |prefixLength|. If |prefixLength|
+// is zero or if |value| is nil, then no trimming is done and |value| is directly returned. The
+// |encoding| param indicates the original encoding of |value| in the source table.
+func trimValueToPrefixLength(value []byte, prefixLength uint16, encoding val.Encoding) []byte {
+	if value == nil || prefixLength == 0 {
+		return value
+	}
+
+	if uint16(len(value)) < prefixLength {
+		prefixLength = uint16(len(value))
+	}
+
+	addTerminatingNullByte := false
+	if encoding == val.BytesAddrEnc || encoding == val.StringAddrEnc {
+		// If the original encoding was for a BLOB or TEXT field, then we need to add
+		// a null byte at the end of the prefix to get it into StringEnc format.
+		addTerminatingNullByte = true
+	} else if prefixLength < uint16(len(value)) {
+		// Otherwise, if we're trimming a StringEnc value, we also need to re-add the
+		// null terminating byte.
+		addTerminatingNullByte = true
+	}
+
+	newValue := make([]byte, prefixLength)
+	copy(newValue, value[:prefixLength])
+	if addTerminatingNullByte {
+		newValue = append(newValue, byte(0))
+	}
+
+	return newValue
+}
+
+func ordinalMappingsForSecondaryIndex(sch schema.Schema, def schema.Index) (val.OrdinalMapping, error) {
+	// assert empty values for secondary indexes
+	if def.Schema().GetNonPKCols().Size() > 0 {
+		return nil, fmt.Errorf("expected empty secondary index values for index %s", def.Name())
+	}
+
+	secondary := def.Schema().GetPKCols()
+	ord := make(val.OrdinalMapping, secondary.Size())
+
+	for i := range ord {
+		name := secondary.GetByIndex(i).Name
+		ord[i] = -1
+
+		pks := sch.GetPKCols().GetColumns()
+		for j, col := range pks {
+			if col.Name == name {
+				ord[i] = j
+			}
+		}
+		vals := sch.GetNonPKCols().GetColumns()
+		for j, col := range vals {
+			if col.Name == name {
+				ord[i] = j + len(pks)
+			}
+		}
+		if ord[i] < 0 {
+			return nil, fmt.Errorf("column %s of index %s not found", name, def.Name())
+		}
+	}
+	return ord, nil
+}
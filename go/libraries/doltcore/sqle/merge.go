@@ -0,0 +1,363 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dprocedures"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+	"github.com/dolthub/dolt/go/store/hash"
+	storetypes "github.com/dolthub/dolt/go/store/types"
+)
+
+var ErrConflictKeysUnsupportedFormat = errors.NewKind("cannot list the conflicting keys of a merge in database %s: its storage format doesn't support it")
+var ErrSchemaConflictsNeedManualResolution = errors.NewKind("table %s has schema conflicts, which can't be resolved automatically: abort the merge and reconcile the two schemas by hand")
+
+// MergeBaseTable returns the table named as it exists at the merge base of |ref1| and |ref2|.
+func (db Database) MergeBaseTable(ctx *sql.Context, tableName, ref1, ref2 string) (sql.Table, bool, error) {
+	cm1, _, err := db.ResolveRef(ctx, ref1)
+	if err != nil {
+		return nil, false, err
+	}
+	cm2, _, err := db.ResolveRef(ctx, ref2)
+	if err != nil {
+		return nil, false, err
+	}
+
+	base, err := doltdb.GetCommitAncestor(ctx, cm1, cm2)
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot find merge base of %s and %s: %w", ref1, ref2, err)
+	}
+	h, err := base.HashOf()
+	if err != nil {
+		return nil, false, err
+	}
+
+	return db.GetTableInsensitiveAsOf(ctx, tableName, h.String())
+}
+
+// ConflictStrategy is the side of a merge whose rows are kept when resolving conflicts with Database.ResolveConflicts.
+type ConflictStrategy int
+
+const (
+	// ConflictStrategyOurs keeps the rows of the working set, discarding the conflicting changes being merged in.
+	ConflictStrategyOurs ConflictStrategy = iota
+	// ConflictStrategyTheirs replaces the conflicting rows of the working set with the rows being merged in.
+	ConflictStrategyTheirs
+)
+
+// ResolveConflicts resolves the data conflicts of the table named by taking the rows from the side given by
+// |strategy|, and returns the number of rows that were in conflict.
+func (db Database) ResolveConflicts(ctx *sql.Context, tableName string, strategy ConflictStrategy) (uint64, error) {
+	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
+		return 0, err
+	}
+
+	ws, err := db.GetWorkingSet(ctx)
+	if err != nil {
+		return 0, err
+	}
+	root := ws.WorkingRoot()
+
+	tbl, resolvedName, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, sql.ErrTableNotFound.New(tableName)
+	}
+
+	if ws.MergeActive() {
+		for _, name := range ws.MergeState().TablesWithSchemaConflicts() {
+			if strings.EqualFold(name, resolvedName) {
+				return 0, ErrSchemaConflictsNeedManualResolution.New(resolvedName)
+			}
+		}
+	}
+
+	numConflicts, err := tbl.NumRowsInConflict(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if numConflicts == 0 {
+		return 0, nil
+	}
+
+	sess := dsess.DSessFromSess(ctx.Session)
+	ours := strategy == ConflictStrategyOurs
+	err = dprocedures.ResolveDataConflicts(ctx, sess, root, db.RevisionQualifiedName(), ours, []string{resolvedName})
+	if err != nil {
+		return 0, err
+	}
+
+	return numConflicts, nil
+}
+
+// ConstraintViolations returns the constraint violations in the working set for the table named, the same
+// information as the dolt_constraint_violations_$table system table. Only the new storage format is supported.
+func (db Database) ConstraintViolations(ctx *sql.Context, tableName string) ([]merge.ConstraintViolation, error) {
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, tableName, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, sql.ErrTableNotFound.New(tableName)
+	}
+
+	if tbl.Format() != storetypes.Format_DOLT {
+		return nil, fmt.Errorf("typed constraint violations are not supported for the %s storage format", tbl.Format().VersionString())
+	}
+
+	return merge.GetConstraintViolations(ctx, tbl)
+}
+
+// MergeStatus describes the merge in progress in a working set, as reported by the dolt_merge_status system table.
+type MergeStatus struct {
+	// Active is whether a merge is in progress. If it's false, the other fields are all empty.
+	Active bool
+	// Source is the commit spec that was merged, e.g. a branch name, and SourceCommit is the commit it resolved to.
+	Source       string
+	SourceCommit hash.Hash
+	// Target is the branch being merged into.
+	Target string
+	// UnmergedTables are the names of the tables with conflicts or constraint violations that must be resolved
+	// before the merge can be committed, in sorted order.
+	UnmergedTables []string
+}
+
+// MergeStatus returns the status of the merge in progress in this database's working set, or the zero MergeStatus
+// if there isn't one, including when the database isn't on a branch.
+func (db Database) MergeStatus(ctx *sql.Context) (MergeStatus, error) {
+	ws, err := db.GetWorkingSet(ctx)
+	if err == doltdb.ErrOperationNotSupportedInDetachedHead {
+		return MergeStatus{}, nil
+	} else if err != nil {
+		return MergeStatus{}, err
+	}
+	if !ws.MergeActive() {
+		return MergeStatus{}, nil
+	}
+
+	state := ws.MergeState()
+	sourceCommit, err := state.Commit().HashOf()
+	if err != nil {
+		return MergeStatus{}, err
+	}
+	target, err := ws.Ref().ToHeadRef()
+	if err != nil {
+		return MergeStatus{}, err
+	}
+
+	wr := ws.WorkingRoot()
+	inConflict, err := wr.TablesWithDataConflicts(ctx)
+	if err != nil {
+		return MergeStatus{}, err
+	}
+	withViolations, err := wr.TablesWithConstraintViolations(ctx)
+	if err != nil {
+		return MergeStatus{}, err
+	}
+	unmerged := set.NewStrSet(inConflict)
+	unmerged.Add(withViolations...)
+	unmerged.Add(state.TablesWithSchemaConflicts()...)
+	unmergedTables := unmerged.AsSlice()
+	sort.Strings(unmergedTables)
+
+	return MergeStatus{
+		Active:         true,
+		Source:         state.CommitSpecStr(),
+		SourceCommit:   sourceCommit,
+		Target:         target.String(),
+		UnmergedTables: unmergedTables,
+	}, nil
+}
+
+// TablesInConflict lists the tables in a working set that must be resolved before it can be committed, as returned by
+// Database.ConflictedTables. A table may be in more than one list. Each list is sorted.
+type TablesInConflict struct {
+	// DataConflicts are the tables with conflicting rows, as shown in dolt_conflicts.
+	DataConflicts []string
+	// SchemaConflicts are the tables whose schemas conflicted in the merge in progress, as shown in
+	// dolt_schema_conflicts.
+	SchemaConflicts []string
+	// ConstraintViolations are the tables with constraint violations, as shown in dolt_constraint_violations.
+	ConstraintViolations []string
+}
+
+// IsEmpty returns whether there are no tables to resolve.
+func (t TablesInConflict) IsEmpty() bool {
+	return len(t.DataConflicts) == 0 && len(t.SchemaConflicts) == 0 && len(t.ConstraintViolations) == 0
+}
+
+// ConflictedTables returns the tables in the working set with conflicts or constraint violations.
+func (db Database) ConflictedTables(ctx *sql.Context) (TablesInConflict, error) {
+	ws, err := db.GetWorkingSet(ctx)
+	if err == doltdb.ErrOperationNotSupportedInDetachedHead {
+		return TablesInConflict{}, nil
+	} else if err != nil {
+		return TablesInConflict{}, err
+	}
+
+	var tables TablesInConflict
+	wr := ws.WorkingRoot()
+	tables.DataConflicts, err = wr.TablesWithDataConflicts(ctx)
+	if err != nil {
+		return TablesInConflict{}, err
+	}
+	tables.ConstraintViolations, err = wr.TablesWithConstraintViolations(ctx)
+	if err != nil {
+		return TablesInConflict{}, err
+	}
+	if ws.MergeActive() {
+		tables.SchemaConflicts = append(tables.SchemaConflicts, ws.MergeState().TablesWithSchemaConflicts()...)
+	}
+
+	sort.Strings(tables.DataConflicts)
+	sort.Strings(tables.SchemaConflicts)
+	sort.Strings(tables.ConstraintViolations)
+	return tables, nil
+}
+
+// PreviewMerge merges the commit that |sourceRef| resolves to into HEAD, as DOLT_MERGE() would, and returns the merged
+// root and merge stats without changing the working set.
+func (db Database) PreviewMerge(ctx *sql.Context, sourceRef string) (*doltdb.RootValue, map[string]*merge.MergeStats, error) {
+	sess := dsess.DSessFromSess(ctx.Session)
+	head, err := sess.GetHeadCommit(ctx, db.RevisionQualifiedName())
+	if err != nil {
+		return nil, nil, err
+	}
+	source, _, err := db.ResolveRef(ctx, sourceRef)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := merge.MergeCommits(ctx, head, source, db.editOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result.Root, result.Stats, nil
+}
+
+// DefaultConflictKeysLimit is the number of keys Database.MergeConflictKeys returns for each table when no limit is
+// given.
+const DefaultConflictKeysLimit = 1000
+
+// RowKey is the primary key of a row, with a value for each of the table's primary key columns in schema order. The
+// key of a row in a keyless table is the hash that identifies the row.
+type RowKey sql.Row
+
+// ConflictKeys are the keys of the rows of a table that conflict in a merge, as returned by
+// Database.MergeConflictKeys.
+type ConflictKeys struct {
+	// Keys are the keys of the conflicting rows, in key order.
+	Keys []RowKey
+	// Truncated is true if more rows conflict than are listed in Keys.
+	Truncated bool
+}
+
+// MergeConflictKeys returns the keys of the rows that would conflict in a merge of |sourceRef| into HEAD, keyed by
+// table name, with at most |limit| keys per table.
+func (db Database) MergeConflictKeys(ctx *sql.Context, sourceRef string, limit int) (map[string]ConflictKeys, error) {
+	if !storetypes.IsFormat_DOLT(db.ddb.Format()) {
+		return nil, ErrConflictKeysUnsupportedFormat.New(db.baseName)
+	}
+	if limit <= 0 {
+		limit = DefaultConflictKeysLimit
+	}
+
+	root, stats, err := db.PreviewMerge(ctx, sourceRef)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := make(map[string]ConflictKeys)
+	for tableName, tableStats := range stats {
+		if tableStats.DataConflicts == 0 {
+			continue
+		}
+		keys, err := conflictKeys(ctx, root, tableName, limit)
+		if err != nil {
+			return nil, err
+		}
+		conflicts[tableName] = keys
+	}
+	return conflicts, nil
+}
+
+// conflictKeys returns up to |limit| keys of the rows of the table named in |root| that have data conflicts.
+func conflictKeys(ctx *sql.Context, root *doltdb.RootValue, tableName string, limit int) (ConflictKeys, error) {
+	tbl, ok, err := root.GetTable(ctx, tableName)
+	if err != nil {
+		return ConflictKeys{}, err
+	} else if !ok {
+		return ConflictKeys{}, doltdb.ErrTableNotFound
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return ConflictKeys{}, err
+	}
+	artifacts, err := tbl.GetArtifacts(ctx)
+	if err != nil {
+		return ConflictKeys{}, err
+	}
+
+	iter, err := durable.ProllyMapFromArtifactIndex(artifacts).IterAllConflicts(ctx)
+	if err != nil {
+		return ConflictKeys{}, err
+	}
+
+	kd := sch.GetKeyDescriptor()
+	var keys ConflictKeys
+	for {
+		art, err := iter.Next(ctx)
+		if err == io.EOF {
+			return keys, nil
+		} else if err != nil {
+			return ConflictKeys{}, err
+		}
+
+		if len(keys.Keys) == limit {
+			keys.Truncated = true
+			return keys, nil
+		}
+
+		key := make(RowKey, kd.Count())
+		for i := range key {
+			key[i], err = index.GetField(ctx, kd, i, art.Key, tbl.NodeStore())
+			if err != nil {
+				return ConflictKeys{}, err
+			}
+		}
+		keys.Keys = append(keys.Keys, key)
+	}
+}
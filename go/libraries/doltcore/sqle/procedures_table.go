@@ -19,6 +19,7 @@ import (
 	"io"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"gopkg.in/src-d/go-errors.v1"
@@ -203,6 +204,45 @@ func DoltProceduresGetTable(ctx *sql.Context, db Database) (*WritableDoltTable,
 // DoltProceduresGetAll returns all stored procedures for the database if the procedureName is blank (and empty string),
 // or it returns only the procedure with the matching name if one is given. The name is not case-sensitive.
 func DoltProceduresGetAll(ctx *sql.Context, db Database, procedureName string) ([]sql.StoredProcedureDetails, error) {
+	return doltProceduresLookup(ctx, db, func(b *sql.IndexBuilder, nameExpr string) *sql.IndexBuilder {
+		if procedureName == "" {
+			return b.IsNotNull(ctx, nameExpr)
+		}
+		return b.Equals(ctx, nameExpr, procedureName)
+	})
+}
+
+// doltProceduresGetAllFold returns the stored procedures whose names are the same as |procedureName| regardless of
+// case. The names in the table's index are compared byte by byte, so for ASCII names only the range of the index from
+// the name in upper case to the name in lower case is read, since it holds every way of casing the name. Other names
+// are looked for in the whole table.
+func doltProceduresGetAllFold(ctx *sql.Context, db Database, procedureName string) ([]sql.StoredProcedureDetails, error) {
+	ascii := true
+	for i := 0; i < len(procedureName) && ascii; i++ {
+		ascii = procedureName[i] < utf8.RuneSelf
+	}
+	procedures, err := doltProceduresLookup(ctx, db, func(b *sql.IndexBuilder, nameExpr string) *sql.IndexBuilder {
+		if !ascii {
+			return b.IsNotNull(ctx, nameExpr)
+		}
+		return b.GreaterOrEqual(ctx, nameExpr, strings.ToUpper(procedureName)).LessOrEqual(ctx, nameExpr, strings.ToLower(procedureName))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []sql.StoredProcedureDetails
+	for _, procedure := range procedures {
+		if strings.EqualFold(procedure.Name, procedureName) {
+			matches = append(matches, procedure)
+		}
+	}
+	return matches, nil
+}
+
+// doltProceduresLookup returns the stored procedures in the index lookup on the `dolt_procedures` table's name built
+// by |build|.
+func doltProceduresLookup(ctx *sql.Context, db Database, build func(b *sql.IndexBuilder, nameExpr string) *sql.IndexBuilder) ([]sql.StoredProcedureDetails, error) {
 	tbl, err := DoltProceduresGetTable(ctx, db)
 	if err != nil {
 		return nil, err
@@ -224,12 +264,7 @@ func DoltProceduresGetAll(ctx *sql.Context, db Database, procedureName string) (
 	}
 	nameExpr := idx.Expressions()[0]
 
-	var lookup sql.IndexLookup
-	if procedureName == "" {
-		lookup, err = sql.NewIndexBuilder(idx).IsNotNull(ctx, nameExpr).Build(ctx)
-	} else {
-		lookup, err = sql.NewIndexBuilder(idx).Equals(ctx, nameExpr, procedureName).Build(ctx)
-	}
+	lookup, err := build(sql.NewIndexBuilder(idx), nameExpr).Build(ctx)
 	if err != nil {
 		return nil, err
 	}
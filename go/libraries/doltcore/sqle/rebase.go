@@ -0,0 +1,380 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var ErrRebaseUncommittedChanges = errors.NewKind("cannot rebase: the working set has uncommitted changes or a merge in progress")
+var ErrRebaseMergeCommit = errors.NewKind("cannot rebase: commit %s is a merge commit, and rebasing merge commits is not supported")
+var ErrRebaseBranchMoved = errors.NewKind("cannot rebase: branch %s was updated by another transaction")
+var ErrCherryPickMergeCommit = errors.NewKind("cannot cherry-pick commit %s: cherry-picking a merge commit is not supported")
+var ErrCherryPickRootCommit = errors.NewKind("cannot cherry-pick commit %s: cherry-picking a commit without parents is not supported")
+
+// RebaseOpts are the options for Database.Rebase.
+type RebaseOpts struct {
+	// Squash replays the branch's commits as a single commit, rather than one commit for each of them.
+	Squash bool
+	// Message is the message of the squashed commit. If it's empty, the messages of the replayed commits are used.
+	Message string
+}
+
+// RebaseResult describes the outcome of Database.Rebase.
+type RebaseResult struct {
+	// Head is the hash of the branch's head commit after the rebase, which is unchanged if a commit conflicted.
+	Head hash.Hash
+	// Replayed is the number of commits replayed onto the upstream commit, or replayed before the conflicting commit.
+	Replayed int
+	// Skipped is the number of commits whose changes were already in the upstream commit, which were dropped.
+	Skipped int
+	// ConflictCommit is the hash of the commit whose changes conflicted, or the empty hash if none did.
+	ConflictCommit hash.Hash
+	// ConflictedTables are the tables that had conflicts or constraint violations when ConflictCommit was replayed.
+	ConflictedTables []string
+}
+
+// Rebase replays the commits of this database's branch that aren't in |upstream| onto it, and moves the branch to the
+// last replayed commit. If a commit conflicts, the branch is left as it was.
+func (db Database) Rebase(ctx *sql.Context, upstream string, opts RebaseOpts) (RebaseResult, error) {
+	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
+		return RebaseResult{}, err
+	}
+
+	ws, err := db.GetWorkingSet(ctx)
+	if err != nil {
+		return RebaseResult{}, err
+	}
+	sess := dsess.DSessFromSess(ctx.Session)
+	dbName := db.RevisionQualifiedName()
+	head, err := sess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return RebaseResult{}, err
+	}
+	headRoot, err := head.GetRootValue(ctx)
+	if err != nil {
+		return RebaseResult{}, err
+	}
+	if clean, err := workingSetIsClean(ws, headRoot); err != nil {
+		return RebaseResult{}, err
+	} else if !clean {
+		return RebaseResult{}, ErrRebaseUncommittedChanges.New()
+	}
+
+	onto, _, err := db.ResolveRef(ctx, upstream)
+	if err != nil {
+		return RebaseResult{}, err
+	}
+	ontoHash, err := onto.HashOf()
+	if err != nil {
+		return RebaseResult{}, err
+	}
+	base, err := doltdb.GetCommitAncestor(ctx, head, onto)
+	if err != nil {
+		return RebaseResult{}, err
+	}
+	baseHash, err := base.HashOf()
+	if err != nil {
+		return RebaseResult{}, err
+	}
+
+	headHash, err := head.HashOf()
+	if err != nil {
+		return RebaseResult{}, err
+	}
+	result := RebaseResult{Head: headHash}
+	if baseHash == ontoHash {
+		// the branch already contains the upstream commit
+		return result, nil
+	}
+
+	commits, err := firstParentCommitsSince(ctx, db.ddb, head, baseHash)
+	if err != nil {
+		return RebaseResult{}, err
+	}
+
+	newHead := onto
+	newRoot, err := onto.GetRootValue(ctx)
+	if err != nil {
+		return RebaseResult{}, err
+	}
+	var messages []string
+	for _, cm := range commits {
+		mergeResult, err := db.replayCommit(ctx, newRoot, cm)
+		if err != nil {
+			return RebaseResult{}, err
+		}
+		if mergeResult.HasMergeArtifacts() {
+			result.ConflictCommit, err = cm.HashOf()
+			if err != nil {
+				return RebaseResult{}, err
+			}
+			result.ConflictedTables = tablesWithMergeArtifacts(mergeResult)
+			return result, nil
+		}
+
+		if changed, err := rootsDiffer(newRoot, mergeResult.Root); err != nil {
+			return RebaseResult{}, err
+		} else if !changed {
+			result.Skipped++
+			continue
+		}
+		newRoot = mergeResult.Root
+		result.Replayed++
+
+		meta, err := cm.GetCommitMeta(ctx)
+		if err != nil {
+			return RebaseResult{}, err
+		}
+		if opts.Squash {
+			messages = append(messages, meta.Description)
+			continue
+		}
+		newHead, err = db.commitDangling(ctx, newRoot, newHead, meta)
+		if err != nil {
+			return RebaseResult{}, err
+		}
+	}
+
+	if opts.Squash && result.Replayed > 0 {
+		msg := opts.Message
+		if msg == "" {
+			msg = strings.Join(messages, "\n\n")
+		}
+		meta, err := datas.NewCommitMetaWithUserTS(ctx.Client().User, fmt.Sprintf("%s@%s", ctx.Client().User, ctx.Client().Address), msg, ctx.QueryTime())
+		if err != nil {
+			return RebaseResult{}, err
+		}
+		newHead, err = db.commitDangling(ctx, newRoot, newHead, meta)
+		if err != nil {
+			return RebaseResult{}, err
+		}
+	}
+
+	headRef, err := ws.Ref().ToHeadRef()
+	if err != nil {
+		return RebaseResult{}, err
+	}
+	if err = db.ddb.SetHeadToCommitIfAt(ctx, headRef, headHash, newHead); err == doltdb.ErrHeadMoved {
+		return RebaseResult{}, ErrRebaseBranchMoved.New(headRef.GetPath())
+	} else if err != nil {
+		return RebaseResult{}, err
+	}
+	newRoot, err = newHead.GetRootValue(ctx)
+	if err != nil {
+		return RebaseResult{}, err
+	}
+	if err = sess.SetWorkingSet(ctx, dbName, ws.WithWorkingRoot(newRoot).WithStagedRoot(newRoot)); err != nil {
+		return RebaseResult{}, err
+	}
+	if err = sess.CommitWorkingSet(ctx, dbName, sess.GetTransaction()); err != nil {
+		return RebaseResult{}, err
+	}
+
+	result.Head, err = newHead.HashOf()
+	if err != nil {
+		return RebaseResult{}, err
+	}
+	return result, nil
+}
+
+// workingSetIsClean returns whether |ws| has no merge in progress and its working and staged roots are both
+// |headRoot|.
+func workingSetIsClean(ws *doltdb.WorkingSet, headRoot *doltdb.RootValue) (bool, error) {
+	if ws.MergeActive() {
+		return false, nil
+	}
+	if changed, err := rootsDiffer(headRoot, ws.WorkingRoot()); err != nil || changed {
+		return false, err
+	}
+	changed, err := rootsDiffer(headRoot, ws.StagedRoot())
+	return !changed, err
+}
+
+// rootsDiffer returns whether the two roots given have different hashes.
+func rootsDiffer(a, b *doltdb.RootValue) (bool, error) {
+	aHash, err := a.HashOf()
+	if err != nil {
+		return false, err
+	}
+	bHash, err := b.HashOf()
+	if err != nil {
+		return false, err
+	}
+	return aHash != bHash, nil
+}
+
+// firstParentCommitsSince returns the commits on the first-parent path from |head| back to the commit with hash
+// |base|, not including it, oldest first. Returns ErrRebaseMergeCommit if one of them is a merge commit.
+func firstParentCommitsSince(ctx *sql.Context, ddb *doltdb.DoltDB, head *doltdb.Commit, base hash.Hash) ([]*doltdb.Commit, error) {
+	var commits []*doltdb.Commit
+	for cm := head; ; {
+		h, err := cm.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		if h == base {
+			break
+		}
+		if cm.NumParents() > 1 {
+			return nil, ErrRebaseMergeCommit.New(h.String())
+		} else if cm.NumParents() == 0 {
+			return nil, fmt.Errorf("commit %s is not descended from %s", h.String(), base.String())
+		}
+
+		commits = append(commits, cm)
+		cm, err = ddb.ResolveParent(ctx, cm, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+	return commits, nil
+}
+
+// replayCommit applies the changes |cm| made to its parent to |root|, as a cherry-pick does.
+func (db Database) replayCommit(ctx *sql.Context, root *doltdb.RootValue, cm *doltdb.Commit) (*merge.Result, error) {
+	parent, err := db.ddb.ResolveParent(ctx, cm, 0)
+	if err != nil {
+		return nil, err
+	}
+	parentRoot, err := parent.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cmRoot, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mo := merge.MergeOpts{
+		IsCherryPick:        true,
+		KeepSchemaConflicts: true,
+	}
+	return merge.MergeRoots(ctx, root, cmRoot, parentRoot, cm, parent, db.editOpts, mo)
+}
+
+// tablesWithMergeArtifacts returns the sorted names of the tables with conflicts or constraint violations in
+// |result|.
+func tablesWithMergeArtifacts(result *merge.Result) []string {
+	tables := set.NewStrSet(nil)
+	for name, stats := range result.Stats {
+		if stats.HasArtifacts() {
+			tables.Add(name)
+		}
+	}
+	for _, conflict := range result.SchemaConflicts {
+		tables.Add(conflict.TableName)
+	}
+	return tables.AsSortedSlice()
+}
+
+// commitDangling writes |root| and commits it with the parent and metadata given, without updating any ref.
+func (db Database) commitDangling(ctx *sql.Context, root *doltdb.RootValue, parent *doltdb.Commit, meta *datas.CommitMeta) (*doltdb.Commit, error) {
+	_, valueHash, err := db.ddb.WriteRootValue(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	return db.ddb.CommitDanglingWithParentCommits(ctx, valueHash, []*doltdb.Commit{parent}, meta)
+}
+
+// CherryPickResult describes the changes Database.CherryPick applied to the working set.
+type CherryPickResult struct {
+	// Added, Modified and Deleted are the number of rows the cherry-picked commit's changes added, modified and deleted
+	// in the working set.
+	Added, Modified, Deleted int
+	// DataConflicts is the number of rows with conflicts, and ConstraintViolations the number of rows that violate
+	// constraints.
+	DataConflicts, ConstraintViolations int
+	// ConflictedTables are the sorted names of the tables with data conflicts, schema conflicts or constraint
+	// violations. If there are any, a cherry-pick is left in progress in the working set.
+	ConflictedTables []string
+}
+
+// CherryPick applies the changes made by the commit that |commitRef| resolves to onto the working set, without
+// committing them.
+func (db Database) CherryPick(ctx *sql.Context, commitRef string) (CherryPickResult, error) {
+	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
+		return CherryPickResult{}, err
+	}
+
+	ws, err := db.GetWorkingSet(ctx)
+	if err != nil {
+		return CherryPickResult{}, err
+	}
+	if ws.MergeActive() {
+		return CherryPickResult{}, doltdb.ErrMergeActive
+	}
+
+	cm, _, err := db.ResolveRef(ctx, commitRef)
+	if err != nil {
+		return CherryPickResult{}, err
+	}
+	switch len(cm.DatasParents()) {
+	case 0:
+		h, err := cm.HashOf()
+		if err != nil {
+			return CherryPickResult{}, err
+		}
+		return CherryPickResult{}, ErrCherryPickRootCommit.New(h.String())
+	case 1:
+	default:
+		h, err := cm.HashOf()
+		if err != nil {
+			return CherryPickResult{}, err
+		}
+		return CherryPickResult{}, ErrCherryPickMergeCommit.New(h.String())
+	}
+
+	mergeResult, err := db.replayCommit(ctx, ws.WorkingRoot(), cm)
+	if err != nil {
+		return CherryPickResult{}, err
+	}
+
+	var result CherryPickResult
+	for _, stats := range mergeResult.Stats {
+		result.Added += stats.Adds
+		result.Modified += stats.Modifications
+		result.Deleted += stats.Deletes
+		result.DataConflicts += stats.DataConflicts
+		result.ConstraintViolations += stats.ConstraintViolations
+	}
+	if mergeResult.HasMergeArtifacts() {
+		result.ConflictedTables = tablesWithMergeArtifacts(mergeResult)
+		ws = ws.StartCherryPick(cm, commitRef).WithUnmergableTables(merge.SchemaConflictTableNames(mergeResult.SchemaConflicts))
+	}
+
+	sess := dsess.DSessFromSess(ctx.Session)
+	err = sess.SetWorkingSet(ctx, db.RevisionQualifiedName(), ws.WithWorkingRoot(mergeResult.Root))
+	if err != nil {
+		return CherryPickResult{}, err
+	}
+	return result, nil
+}
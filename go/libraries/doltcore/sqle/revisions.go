@@ -0,0 +1,245 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var ErrBranchAlreadyExists = errors.NewKind("a branch named '%s' already exists")
+
+// IsDetachedHead returns whether this database is pinned to a commit or tag rather than a branch. A detached database
+// is read-only and has no working set.
+func (db Database) IsDetachedHead(ctx *sql.Context) (bool, error) {
+	sess := dsess.DSessFromSess(ctx.Session)
+	dbState, ok, err := sess.LookupDbState(ctx, db.RevisionQualifiedName())
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, fmt.Errorf("no root value found in session")
+	}
+	return dbState.WorkingSet() == nil, nil
+}
+
+// DetachHead returns a read-only database pinned to the commit that |ref| resolves to.
+func (db Database) DetachHead(ctx *sql.Context, ref string) (dsess.SqlDatabase, error) {
+	cm, _, err := db.ResolveRef(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	h, err := cm.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	return db.sessionDatabase(ctx, h.String())
+}
+
+// ReattachHead returns the database for |branch| in place of this one.
+func (db Database) ReattachHead(ctx *sql.Context, branch string) (dsess.SqlDatabase, error) {
+	_, ok, err := db.ddb.HasBranch(ctx, branch)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, doltdb.ErrBranchNotFound
+	}
+
+	return db.sessionDatabase(ctx, branch)
+}
+
+// sessionDatabase returns the session's database for |revision| of this database.
+func (db Database) sessionDatabase(ctx *sql.Context, revision string) (dsess.SqlDatabase, error) {
+	name := db.baseName + dsess.DbRevisionDelimiter + revision
+	revDb, ok, err := dsess.DSessFromSess(ctx.Session).Provider().SessionDatabase(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, sql.ErrDatabaseNotFound.New(name)
+	}
+	return revDb, nil
+}
+
+// BranchHead returns the hash of the commit at the head of the branch named, without checking it out. The branch name
+// is not case-sensitive. Returns an error wrapping doltdb.ErrBranchNotFound if there's no such branch.
+func (db Database) BranchHead(ctx *sql.Context, branch string) (hash.Hash, error) {
+	branchName, ok, err := db.ddb.HasBranch(ctx, branch)
+	if err != nil {
+		return hash.Hash{}, err
+	}
+	if !ok {
+		return hash.Hash{}, fmt.Errorf("%w: %s", doltdb.ErrBranchNotFound, branch)
+	}
+
+	cm, err := db.ddb.ResolveCommitRef(ctx, ref.NewBranchRef(branchName))
+	if err != nil {
+		return hash.Hash{}, err
+	}
+
+	return cm.HashOf()
+}
+
+// BranchExists returns whether there is a branch with the name given, which is not case-sensitive.
+func (db Database) BranchExists(ctx *sql.Context, branch string) (bool, error) {
+	_, ok, err := db.ddb.HasBranch(ctx, branch)
+	return ok, err
+}
+
+// CreateBranch creates a branch named |branch| at the commit that |startPoint| resolves to, or HEAD if it's empty.
+// Returns ErrBranchAlreadyExists if there's already a branch with that name.
+func (db Database) CreateBranch(ctx *sql.Context, branch, startPoint string) error {
+	if !doltdb.IsValidUserBranchName(branch) {
+		return doltdb.ErrInvBranchName
+	}
+	if err := branch_control.CanCreateBranch(ctx, branch); err != nil {
+		return err
+	}
+
+	if exists, err := db.BranchExists(ctx, branch); err != nil {
+		return err
+	} else if exists {
+		return ErrBranchAlreadyExists.New(branch)
+	}
+
+	if startPoint == "" {
+		startPoint = "HEAD"
+	}
+	cm, _, err := db.ResolveRef(ctx, startPoint)
+	if err != nil {
+		return err
+	}
+
+	if err = db.ddb.NewBranchAtCommit(ctx, ref.NewBranchRef(branch), cm, nil); err != nil {
+		return err
+	}
+	return branch_control.AddAdminForContext(ctx, branch)
+}
+
+// TagInfo describes a tag in a database.
+type TagInfo struct {
+	// Name is the name of the tag.
+	Name string
+	// Hash is the hash of the commit the tag refers to.
+	Hash hash.Hash
+	// Tagger is the name of the user who created the tag.
+	Tagger string
+	// Email is the email address of the user who created the tag.
+	Email string
+	// Date is the time the tag was created.
+	Date time.Time
+	// Message is the tag's message.
+	Message string
+}
+
+// Tags returns the tags in this database whose names match |pattern|, as for path.Match, sorted by creation time.
+func (db Database) Tags(ctx *sql.Context, pattern string) ([]TagInfo, error) {
+	if pattern != "" {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	tags, err := db.ddb.GetTagsWithHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]TagInfo, 0, len(tags))
+	for _, t := range tags {
+		if pattern != "" {
+			if ok, _ := path.Match(pattern, t.Tag.Name); !ok {
+				continue
+			}
+		}
+		infos = append(infos, TagInfo{
+			Name:    t.Tag.Name,
+			Hash:    t.Hash,
+			Tagger:  t.Tag.Meta.Name,
+			Email:   t.Tag.Meta.Email,
+			Date:    t.Tag.Meta.Time(),
+			Message: t.Tag.Meta.Description,
+		})
+	}
+
+	sort.SliceStable(infos, func(i, j int) bool {
+		if infos[i].Date.Equal(infos[j].Date) {
+			return infos[i].Name < infos[j].Name
+		}
+		return infos[i].Date.Before(infos[j].Date)
+	})
+
+	return infos, nil
+}
+
+// RevisionInfo describes a revision that can be used to form a revision database, as returned by
+// Database.AvailableRevisions.
+type RevisionInfo struct {
+	// Name is the name of the branch or tag, or the commit hash. The revision database for it is named
+	// <database>/<Name>.
+	Name string
+	// Type is the kind of revision: RevisionTypeBranch, RevisionTypeTag or RevisionTypeCommit.
+	Type dsess.RevisionType
+	// Hash is the hash of the commit the revision refers to.
+	Hash hash.Hash
+}
+
+// AvailableRevisions returns the branches and tags of this database along with the commits they refer to.
+func (db Database) AvailableRevisions(ctx *sql.Context) ([]RevisionInfo, error) {
+	branches, err := db.ddb.GetBranchesWithHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := db.ddb.GetTagsWithHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]RevisionInfo, 0, 2*(len(branches)+len(tags)))
+	commits := make(map[hash.Hash]struct{})
+	for _, b := range branches {
+		revisions = append(revisions, RevisionInfo{Name: b.Ref.GetPath(), Type: dsess.RevisionTypeBranch, Hash: b.Hash})
+		commits[b.Hash] = struct{}{}
+	}
+	for _, t := range tags {
+		revisions = append(revisions, RevisionInfo{Name: t.Tag.Name, Type: dsess.RevisionTypeTag, Hash: t.Hash})
+		commits[t.Hash] = struct{}{}
+	}
+	for h := range commits {
+		revisions = append(revisions, RevisionInfo{Name: h.String(), Type: dsess.RevisionTypeCommit, Hash: h})
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		if revisions[i].Type != revisions[j].Type {
+			return revisions[i].Type < revisions[j].Type
+		}
+		return revisions[i].Name < revisions[j].Name
+	})
+
+	return revisions, nil
+}
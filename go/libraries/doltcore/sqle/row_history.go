@@ -0,0 +1,304 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var ErrRowHistoryKeyless = errors.NewKind("cannot get the history of a row of table %s: only tables with a primary key are supported")
+var ErrRowHistoryKeyLength = errors.NewKind("cannot get the history of a row of table %s: expected %d primary key values, got %d")
+
+// RowVersion is a version of a single row, as returned by Database.RowHistory.
+type RowVersion struct {
+	// CommitHash is the hash of the commit that made this change to the row.
+	CommitHash hash.Hash
+	// Committer is the name of the committer of the commit.
+	Committer string
+	// CommitDate is the time of the commit.
+	CommitDate time.Time
+	// DiffType is one of "added", "modified" or "removed".
+	DiffType string
+	// Row is the row as of the commit, in the table's current schema. Columns that don't exist at the commit, or that
+	// had a different type, are nil. Row is nil for removed rows.
+	Row sql.Row
+}
+
+// RowHistory returns every version of the row of the table named with the primary key |pk|, given in primary key
+// order, across the history of the session's head commit. Each version is the change made to the row by a single
+// commit, and versions are returned in commit order, oldest first. Like the dolt_history_$table system table, rows
+// are found with a primary key lookup in each commit rather than by scanning the table, and commits that didn't
+// change the table at all are skipped without a lookup. A merge commit only produces a version if the row differs
+// from the row in every one of its parents. At commits where the table's primary key columns differ from its current
+// ones, the row is treated as absent. Only tables with a primary key are supported.
+func (db Database) RowHistory(ctx *sql.Context, tableName string, pk []interface{}) ([]RowVersion, error) {
+	stbl, ok, err := db.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, sql.ErrTableNotFound.New(tableName)
+	}
+
+	var tbl *DoltTable
+	switch t := stbl.(type) {
+	case *AlterableDoltTable:
+		tbl = t.DoltTable
+	case *WritableDoltTable:
+		tbl = t.DoltTable
+	case *DoltTable:
+		tbl = t
+	default:
+		return nil, fmt.Errorf("cannot get the history of a row of table %s", tableName)
+	}
+	if schema.IsKeyless(tbl.sch) {
+		return nil, ErrRowHistoryKeyless.New(tbl.tableName)
+	}
+	if len(pk) != len(tbl.sqlSch.PkOrdinals) {
+		return nil, ErrRowHistoryKeyLength.New(tbl.tableName, len(tbl.sqlSch.PkOrdinals), len(pk))
+	}
+
+	head, err := dsess.DSessFromSess(ctx.Session).GetHeadCommit(ctx, db.RevisionQualifiedName())
+	if err != nil {
+		return nil, err
+	}
+
+	rh := &rowHistory{
+		table:    tbl,
+		pk:       pk,
+		byCommit: make(map[hash.Hash]sql.Row),
+		byTable:  make(map[hash.Hash]sql.Row),
+	}
+
+	type commitVersion struct {
+		RowVersion
+		height uint64
+	}
+	var versions []commitVersion
+
+	itr := doltdb.CommitItrForRoots(db.ddb, head)
+	for {
+		h, cm, err := itr.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		row, err := rh.rowAt(ctx, h, cm)
+		if err != nil {
+			return nil, err
+		}
+
+		diffType, err := rh.diffTypeAt(ctx, cm, row)
+		if err != nil {
+			return nil, err
+		}
+		if diffType == "" {
+			continue
+		}
+
+		meta, err := cm.GetCommitMeta(ctx)
+		if err != nil {
+			return nil, err
+		}
+		height, err := cm.Height()
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, commitVersion{
+			RowVersion: RowVersion{
+				CommitHash: h,
+				Committer:  meta.Name,
+				CommitDate: meta.Time(),
+				DiffType:   diffType,
+				Row:        row,
+			},
+			height: height,
+		})
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		if versions[i].height != versions[j].height {
+			return versions[i].height < versions[j].height
+		}
+		return versions[i].CommitDate.Before(versions[j].CommitDate)
+	})
+
+	res := make([]RowVersion, len(versions))
+	for i := range versions {
+		res[i] = versions[i].RowVersion
+	}
+	return res, nil
+}
+
+// rowHistory looks up a single row at different commits for Database.RowHistory, remembering the row found at each
+// commit and for each version of the table's data.
+type rowHistory struct {
+	table    *DoltTable
+	pk       []interface{}
+	byCommit map[hash.Hash]sql.Row
+	byTable  map[hash.Hash]sql.Row
+}
+
+// diffTypeAt returns how the commit |cm| changed the row, given the |row| at that commit, or the empty string if it
+// didn't change it.
+func (rh *rowHistory) diffTypeAt(ctx *sql.Context, cm *doltdb.Commit, row sql.Row) (string, error) {
+	if cm.NumParents() == 0 {
+		if row == nil {
+			return "", nil
+		}
+		return "added", nil
+	}
+
+	var firstParentRow sql.Row
+	for i := 0; i < cm.NumParents(); i++ {
+		parent, err := cm.GetParent(ctx, i)
+		if err != nil {
+			return "", err
+		}
+		h, err := parent.HashOf()
+		if err != nil {
+			return "", err
+		}
+		parentRow, err := rh.rowAt(ctx, h, parent)
+		if err != nil {
+			return "", err
+		}
+
+		if parentRow == nil && row == nil {
+			return "", nil
+		} else if parentRow != nil && row != nil {
+			eq, err := parentRow.Equals(row, rh.table.sqlSch.Schema)
+			if err != nil {
+				return "", err
+			}
+			if eq {
+				return "", nil
+			}
+		}
+
+		if i == 0 {
+			firstParentRow = parentRow
+		}
+	}
+
+	switch {
+	case row == nil:
+		return "removed", nil
+	case firstParentRow == nil:
+		return "added", nil
+	default:
+		return "modified", nil
+	}
+}
+
+// rowAt returns the row at the commit |cm|, with hash |h|, in the table's current schema, or nil if the row doesn't
+// exist at that commit.
+func (rh *rowHistory) rowAt(ctx *sql.Context, h hash.Hash, cm *doltdb.Commit) (sql.Row, error) {
+	if row, ok := rh.byCommit[h]; ok {
+		return row, nil
+	}
+
+	row, err := rh.lookupRow(ctx, cm)
+	if err != nil {
+		return nil, err
+	}
+	rh.byCommit[h] = row
+	return row, nil
+}
+
+func (rh *rowHistory) lookupRow(ctx *sql.Context, cm *doltdb.Commit) (sql.Row, error) {
+	root, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, _, ok, err := root.GetTableInsensitive(ctx, rh.table.Name())
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, nil
+	}
+
+	tblHash, err := tbl.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	if row, ok := rh.byTable[tblHash]; ok {
+		return row, nil
+	}
+
+	histTable, err := rh.table.LockedToRoot(ctx, root)
+	if err == doltdb.ErrTableNotFound {
+		// the table's name differs in case at this commit
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var row sql.Row
+	if samePrimaryKey(histTable.sqlSch, rh.table.sqlSch) {
+		histRow, err := histTable.rowForKey(ctx, rh.pk)
+		if err != nil {
+			return nil, err
+		}
+		if histRow != nil {
+			row = convertRowToSchema(histRow, histTable.sqlSch.Schema, rh.table.sqlSch.Schema)
+		}
+	}
+
+	rh.byTable[tblHash] = row
+	return row, nil
+}
+
+// samePrimaryKey returns whether the two schemas have the same primary key columns, in the same order and with the
+// same types.
+func samePrimaryKey(a, b sql.PrimaryKeySchema) bool {
+	if len(a.PkOrdinals) != len(b.PkOrdinals) {
+		return false
+	}
+	for i := range a.PkOrdinals {
+		colA, colB := a.Schema[a.PkOrdinals[i]], b.Schema[b.PkOrdinals[i]]
+		if !strings.EqualFold(colA.Name, colB.Name) || !colA.Type.Equals(colB.Type) {
+			return false
+		}
+	}
+	return true
+}
+
+// convertRowToSchema converts |row|, in the schema |srcSch|, to the schema |targetSch| by column name. As in the
+// dolt_history_$table system table, columns that are missing from |srcSch| or have a different type are nil.
+func convertRowToSchema(row sql.Row, srcSch, targetSch sql.Schema) sql.Row {
+	res := make(sql.Row, len(targetSch))
+	for i, col := range targetSch {
+		srcIdx := srcSch.IndexOfColName(col.Name)
+		if srcIdx >= 0 && srcSch[srcIdx].Type.Equals(col.Type) {
+			res[i] = row[srcIdx]
+		}
+	}
+	return res
+}
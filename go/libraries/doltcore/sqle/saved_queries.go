@@ -0,0 +1,105 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+)
+
+var ErrSavedQueryExists = errors.NewKind("a saved query named '%s' already exists")
+var ErrInvalidSavedQuery = errors.NewKind("invalid saved query: %s")
+
+// SaveQuery saves |query| in the dolt_query_catalog table under |name|, which must be unique among the saved queries.
+// Returns ErrSavedQueryExists if there's already a query with that name.
+func (db Database) SaveQuery(ctx *sql.Context, name, query, description string) error {
+	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
+		return err
+	}
+	if strings.TrimSpace(name) == "" {
+		return ErrInvalidSavedQuery.New("name must not be empty")
+	}
+	if strings.TrimSpace(query) == "" {
+		return ErrInvalidSavedQuery.New("query must not be empty")
+	}
+
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return err
+	}
+	saved, err := dtables.RetrieveAllFromQueryCatalog(ctx, root)
+	if err != nil {
+		return err
+	}
+	for _, sq := range saved {
+		if sq.Name == name || sq.ID == name {
+			return ErrSavedQueryExists.New(name)
+		}
+	}
+
+	_, root, err = dtables.NewQueryCatalogEntryWithNameAsID(ctx, root, name, query, description)
+	if err != nil {
+		return err
+	}
+	return db.SetRoot(ctx, root)
+}
+
+// ListSavedQueries returns the queries saved in the dolt_query_catalog table, in display order.
+func (db Database) ListSavedQueries(ctx *sql.Context) ([]dtables.SavedQuery, error) {
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dtables.RetrieveAllFromQueryCatalog(ctx, root)
+}
+
+// DeleteSavedQuery deletes the query saved in the dolt_query_catalog table under |name|. Returns
+// dtables.ErrQueryNotFound if there's no such query.
+func (db Database) DeleteSavedQuery(ctx *sql.Context, name string) error {
+	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
+		return err
+	}
+
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return err
+	}
+	saved, err := dtables.RetrieveAllFromQueryCatalog(ctx, root)
+	if err != nil {
+		return err
+	}
+
+	// Queries saved with a random ID are matched by name, so every entry with the name is deleted
+	found := false
+	for _, sq := range saved {
+		if sq.Name == name {
+			root, err = dtables.RemoveFromQueryCatalog(ctx, root, sq.ID)
+			if err != nil {
+				return err
+			}
+			found = true
+		}
+	}
+	if !found {
+		return dtables.ErrQueryNotFound.New(name)
+	}
+	return db.SetRoot(ctx, root)
+}
@@ -0,0 +1,124 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+// SchemaDiff is the difference between the schemas of a table at two revisions, as returned by Database.DiffSchemas.
+// Only the parts of the schema that were added, dropped or modified are included; the DiffType of each difference is
+// never diff.SchDiffNone.
+type SchemaDiff struct {
+	// Columns are the columns that changed, matched up by tag, so a renamed column is modified rather than dropped and
+	// added. Columns of the from revision come first, in schema order, followed by the columns added.
+	Columns []diff.ColumnDifference
+	// Indexes are the secondary indexes that changed, matched up by the columns they index.
+	Indexes []diff.IndexDifference
+	// ForeignKeys are the foreign keys declared on the table that changed, matched up by the columns they reference.
+	ForeignKeys []diff.ForeignKeyDifference
+	// Checks are the check constraints that changed, matched up by name.
+	Checks []diff.CheckDifference
+}
+
+// IsEmpty returns whether the schema of the table is the same at both revisions.
+func (sd SchemaDiff) IsEmpty() bool {
+	return len(sd.Columns) == 0 && len(sd.Indexes) == 0 && len(sd.ForeignKeys) == 0 && len(sd.Checks) == 0
+}
+
+// DiffSchemas returns how the schema of the table named changed between |fromRef| and |toRef|, which may be any ref
+// accepted by ResolveRef. Unlike DiffRows, no rows are read. If the table only exists at one of the two revisions, its
+// whole schema is reported as added or dropped.
+func (db Database) DiffSchemas(ctx *sql.Context, tableName, fromRef, toRef string) (SchemaDiff, error) {
+	fromSch, fromFks, err := db.schemaAtRef(ctx, tableName, fromRef)
+	if err != nil {
+		return SchemaDiff{}, err
+	}
+	toSch, toFks, err := db.schemaAtRef(ctx, tableName, toRef)
+	if err != nil {
+		return SchemaDiff{}, err
+	}
+	if fromSch == nil && toSch == nil {
+		return SchemaDiff{}, sql.ErrTableNotFound.New(tableName)
+	}
+
+	if fromSch == nil {
+		fromSch = schema.EmptySchema
+	}
+	if toSch == nil {
+		toSch = schema.EmptySchema
+	}
+
+	var sd SchemaDiff
+	colDiffs, tags := diff.DiffSchColumns(fromSch, toSch)
+	for _, tag := range tags {
+		if d := colDiffs[tag]; d.DiffType != diff.SchDiffNone {
+			sd.Columns = append(sd.Columns, d)
+		}
+	}
+	for _, d := range diff.DiffSchIndexes(fromSch, toSch) {
+		if d.DiffType != diff.SchDiffNone {
+			sd.Indexes = append(sd.Indexes, d)
+		}
+	}
+	for _, d := range diff.DiffForeignKeys(fromFks, toFks) {
+		if d.DiffType != diff.SchDiffNone {
+			sd.ForeignKeys = append(sd.ForeignKeys, d)
+		}
+	}
+	for _, d := range diff.DiffChecks(allChecks(fromSch), allChecks(toSch)) {
+		if d.DiffType != diff.SchDiffNone {
+			sd.Checks = append(sd.Checks, d)
+		}
+	}
+	return sd, nil
+}
+
+// schemaAtRef returns the schema of the table named at the revision |refStr|, along with the foreign keys it
+// declares. The schema is nil if the table doesn't exist at that revision.
+func (db Database) schemaAtRef(ctx *sql.Context, tableName, refStr string) (schema.Schema, []doltdb.ForeignKey, error) {
+	_, root, _, err := db.rootAtRef(ctx, refStr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tbl, name, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil || !ok {
+		return nil, nil, err
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fkc, err := root.GetForeignKeyCollection(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	fks, _ := fkc.KeysForTable(name)
+	return sch, fks, nil
+}
+
+// allChecks returns the check constraints of |sch|, which has none if it has no check collection.
+func allChecks(sch schema.Schema) []schema.Check {
+	if sch.Checks() == nil {
+		return nil
+	}
+	return sch.Checks().AllChecks()
+}
@@ -0,0 +1,178 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+// FragmentError describes a schema fragment in the dolt_schemas table that can't be used.
+type FragmentError struct {
+	// Type is the type of the fragment: view, trigger or event.
+	Type string
+	// Name is the name of the fragment.
+	Name string
+	// Err is the reason the fragment can't be used, either a parse error or a reference to a table that doesn't exist.
+	Err error
+}
+
+func (e FragmentError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Type, e.Name, e.Err.Error())
+}
+
+// ValidateSchemaFragments checks every view, trigger and event stored in the dolt_schemas table of the working set,
+// and returns a FragmentError for each one that no longer parses, or that references a table or view in this
+// database that doesn't exist. Servers can use this to surface broken fragments at startup rather than on first use.
+// This method doesn't modify the database.
+func (db Database) ValidateSchemaFragments(ctx *sql.Context) ([]FragmentError, error) {
+	tbl, ok, err := db.GetTableInsensitive(ctx, doltdb.SchemasTableName)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	fragsByType := make(map[string][]schemaFragment)
+	for _, fragType := range []string{viewFragment, triggerFragment, eventFragment} {
+		frags, err := getSchemaFragmentsOfType(ctx, tbl.(*WritableDoltTable), fragType)
+		if err != nil {
+			return nil, err
+		}
+		fragsByType[fragType] = frags
+	}
+
+	// views may be defined in terms of other views
+	viewNames := make(map[string]struct{})
+	for _, frag := range fragsByType[viewFragment] {
+		viewNames[strings.ToLower(frag.name)] = struct{}{}
+	}
+
+	var fragErrs []FragmentError
+	for _, fragType := range []string{viewFragment, triggerFragment, eventFragment} {
+		for _, frag := range fragsByType[fragType] {
+			stmt, err := sqlparser.ParseWithOptions(frag.fragment, sql.NewSqlModeFromString(frag.sqlMode).ParserOptions())
+			if err != nil {
+				fragErrs = append(fragErrs, FragmentError{Type: fragType, Name: frag.name, Err: err})
+				continue
+			}
+
+			for _, tableName := range referencedTables(stmt, db.Name()) {
+				if _, ok := viewNames[strings.ToLower(tableName)]; ok {
+					continue
+				}
+				_, ok, err := db.GetTableInsensitive(ctx, tableName)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					fragErrs = append(fragErrs, FragmentError{Type: fragType, Name: frag.name, Err: sql.ErrTableNotFound.New(tableName)})
+				}
+			}
+		}
+	}
+
+	return fragErrs, nil
+}
+
+// referencedTables returns the names of the tables and views in the database named that are referenced by |stmt|.
+// References qualified with the name of a different database and common table expressions are omitted.
+func referencedTables(stmt sqlparser.Statement, dbName string) []string {
+	var nodes []sqlparser.SQLNode
+	var tableNames []sqlparser.TableName
+	switch stmt := stmt.(type) {
+	case *sqlparser.DDL:
+		if stmt.ViewSpec != nil {
+			nodes = append(nodes, stmt.ViewSpec.ViewExpr)
+		}
+		if stmt.TriggerSpec != nil {
+			tableNames = append(tableNames, stmt.Table)
+			nodes = append(nodes, stmt.TriggerSpec.Body)
+		}
+		if stmt.EventSpec != nil {
+			nodes = append(nodes, stmt.EventSpec.Body)
+		}
+	default:
+		// older views were stored as just their select statement
+		nodes = append(nodes, stmt)
+	}
+
+	ctes := make(map[string]struct{})
+	var visit sqlparser.Visit
+	visit = func(node sqlparser.SQLNode) (bool, error) {
+		// the parser doesn't walk the WITH clause of statements, so their common table expressions are walked here
+		var with *sqlparser.With
+		switch node := node.(type) {
+		case *sqlparser.Select:
+			with = node.With
+		case *sqlparser.Union:
+			with = node.With
+		case *sqlparser.Insert:
+			with = node.With
+		case *sqlparser.Update:
+			with = node.With
+		case *sqlparser.Delete:
+			with = node.With
+		}
+		if with != nil {
+			_ = sqlparser.Walk(visit, with)
+		}
+
+		switch node := node.(type) {
+		case *sqlparser.CommonTableExpr:
+			ctes[strings.ToLower(node.As.String())] = struct{}{}
+		case *sqlparser.AliasedTableExpr:
+			if tn, ok := node.Expr.(sqlparser.TableName); ok {
+				tableNames = append(tableNames, tn)
+			}
+		case *sqlparser.Insert:
+			tableNames = append(tableNames, node.Table)
+		case sqlparser.TableName:
+			// table names elsewhere, such as column qualifiers and multi-table delete targets, may be aliases
+			return false, nil
+		}
+		return true, nil
+	}
+	_ = sqlparser.Walk(visit, nodes...)
+
+	seen := make(map[string]struct{})
+	var names []string
+	for _, tn := range tableNames {
+		if !tn.Qualifier.IsEmpty() && !strings.EqualFold(tn.Qualifier.String(), dbName) {
+			continue
+		}
+		name := tn.Name.String()
+		lwr := strings.ToLower(name)
+		if lwr == "dual" || name == "" {
+			continue
+		}
+		if _, ok := ctes[lwr]; ok {
+			continue
+		}
+		if _, ok := seen[lwr]; ok {
+			continue
+		}
+		seen[lwr] = struct{}{}
+		names = append(names, name)
+	}
+
+	return names
+}
@@ -0,0 +1,171 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// FragSpec identifies a schema object stored in the dolt_schemas table.
+type FragSpec struct {
+	// Type is the type of the schema object: "view", "trigger" or "event"
+	Type string
+	// Name is the name of the schema object, which is not case-sensitive
+	Name string
+}
+
+// DropSchemaObjects drops the views, triggers and events given in a single change to the working set.
+func (db Database) DropSchemaObjects(ctx *sql.Context, specs []FragSpec) error {
+	for _, spec := range specs {
+		switch strings.ToLower(spec.Type) {
+		case viewFragment, triggerFragment, eventFragment:
+		default:
+			return fmt.Errorf("unsupported schema object type %q for %s", spec.Type, spec.Name)
+		}
+	}
+
+	return db.dropFragsFromSchemasTable(ctx, specs, func(spec FragSpec) error {
+		switch strings.ToLower(spec.Type) {
+		case viewFragment:
+			return sql.ErrViewDoesNotExist.New(db.baseName, spec.Name)
+		case triggerFragment:
+			return sql.ErrTriggerDoesNotExist.New(spec.Name)
+		default:
+			return sql.ErrEventDoesNotExist.New(spec.Name)
+		}
+	})
+}
+
+// SchemaFragmentDef is a view, trigger or event to create with Database.ImportSchemaFragments.
+type SchemaFragmentDef struct {
+	// Type is the type of the schema object: "view", "trigger" or "event"
+	Type string
+	// Name is the name of the schema object, which is not case-sensitive
+	Name string
+	// CreateStatement is the statement that creates the schema object
+	CreateStatement string
+	// CreatedAt is the time the schema object was created. It's ignored for views, which don't record it.
+	CreatedAt time.Time
+	// SqlMode is the SQL mode to parse CreateStatement with, or the session's SQL mode if empty
+	SqlMode string
+}
+
+// ImportSchemaFragments creates the views, triggers and events given in a single change to the working set.
+func (db Database) ImportSchemaFragments(ctx *sql.Context, frags []SchemaFragmentDef) error {
+	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
+		return err
+	}
+	if len(frags) == 0 {
+		return nil
+	}
+
+	existing := make(map[FragSpec]bool)
+	stbl, found, err := db.GetTableInsensitive(ctx, doltdb.SchemasTableName)
+	if err != nil {
+		return err
+	}
+	if found {
+		for _, fragType := range []string{viewFragment, triggerFragment, eventFragment} {
+			existingFrags, err := getSchemaFragmentsOfType(ctx, stbl.(*WritableDoltTable), fragType)
+			if err != nil {
+				return err
+			}
+			for _, frag := range existingFrags {
+				existing[FragSpec{Type: fragType, Name: strings.ToLower(frag.name)}] = true
+			}
+		}
+	}
+
+	rows := make([]sql.Row, len(frags))
+	for i, frag := range frags {
+		fragType := strings.ToLower(frag.Type)
+		key := FragSpec{Type: fragType, Name: strings.ToLower(frag.Name)}
+		if frag.Name == "" {
+			return fmt.Errorf("cannot import %s: it has no name", frag.Type)
+		}
+
+		var existsErr error
+		created := frag.CreatedAt
+		switch fragType {
+		case viewFragment:
+			existsErr = sql.ErrExistingView.New(db.Name(), frag.Name)
+			created = time.Unix(0, 0).UTC()
+		case triggerFragment:
+			existsErr = fmt.Errorf("triggers `%s` already exists", frag.Name)
+		case eventFragment:
+			existsErr = sql.ErrEventAlreadyExists.New(frag.Name)
+		default:
+			return fmt.Errorf("unsupported schema object type %q for %s", frag.Type, frag.Name)
+		}
+		if existing[key] {
+			return existsErr
+		}
+		existing[key] = true
+
+		sqlMode := sql.LoadSqlMode(ctx)
+		if frag.SqlMode != "" {
+			sqlMode = sql.NewSqlModeFromString(frag.SqlMode)
+		}
+		if _, err := sqlparser.ParseWithOptions(frag.CreateStatement, sqlMode.ParserOptions()); err != nil {
+			return fmt.Errorf("cannot import %s %s: %w", fragType, frag.Name, err)
+		}
+
+		extraJSON, err := json.Marshal(Extra{CreatedAt: created.Unix()})
+		if err != nil {
+			return err
+		}
+		rows[i] = sql.Row{fragType, frag.Name, frag.CreateStatement, extraJSON, sqlMode.String()}
+	}
+
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return err
+	}
+	tbl, err := getOrCreateDoltSchemasTable(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	// the rows are written as a single statement, so that if any of them can't be inserted, the rows inserted before
+	// it are discarded instead of being written to the working root when the inserter is closed
+	inserter := tbl.Inserter(ctx)
+	inserter.StatementBegin(ctx)
+	for _, row := range rows {
+		if err := inserter.Insert(ctx, row); err != nil {
+			_ = inserter.DiscardChanges(ctx, err)
+			_ = inserter.Close(ctx)
+			// the dolt_schemas table may have been created for the import
+			if rErr := db.SetRoot(ctx, root); rErr != nil {
+				return rErr
+			}
+			return err
+		}
+	}
+	if err := inserter.StatementComplete(ctx); err != nil {
+		_ = inserter.Close(ctx)
+		return err
+	}
+	return inserter.Close(ctx)
+}
@@ -0,0 +1,218 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// TableSchemaJSONVersion is the version of the documents written by TableSchemaJSON. It must be incremented whenever
+// the document changes in a way that older versions of Dolt can't read.
+const TableSchemaJSONVersion = 1
+
+var ErrUnsupportedSchemaJSONVersion = errors.NewKind("unsupported table schema document version %d, the latest supported version is %d")
+var ErrInvalidSchemaJSONForeignKey = errors.NewKind("foreign key %s in table schema document must name a referenced table and the same number of columns and referenced columns")
+
+// tableSchemaJSON is the document written by TableSchemaJSON and read by CreateTableFromSchemaJSON.
+type tableSchemaJSON struct {
+	Version     int                    `json:"version"`
+	Name        string                 `json:"name"`
+	Schema      json.RawMessage        `json:"schema"`
+	ForeignKeys []SchemaJSONForeignKey `json:"foreign_keys,omitempty"`
+}
+
+// SchemaJSONForeignKey describes a foreign key declared by the table in a document written by TableSchemaJSON. Columns
+// are named rather than identified by their tags, and the indexes backing the foreign key aren't recorded, since they
+// are chosen again when the table is created from the document.
+type SchemaJSONForeignKey struct {
+	Name              string   `json:"name"`
+	Columns           []string `json:"columns"`
+	ReferencedTable   string   `json:"referenced_table"`
+	ReferencedColumns []string `json:"referenced_columns"`
+	OnUpdate          string   `json:"on_update"`
+	OnDelete          string   `json:"on_delete"`
+}
+
+// TableSchemaJSON returns a versioned JSON document describing the schema of the table named in the working set,
+// including its columns and their tags, indexes, checks, collation, and the foreign keys declared on it. Unlike
+// SHOW CREATE TABLE, the document preserves column tags, so a table recreated from it with CreateTableFromSchemaJSON
+// can be diffed and merged with the original.
+func (db Database) TableSchemaJSON(ctx *sql.Context, tableName string) ([]byte, error) {
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, tableName, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, sql.ErrTableNotFound.New(tableName)
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schJSON, err := encoding.MarshalSchemaAsJSON(sch)
+	if err != nil {
+		return nil, err
+	}
+
+	fkc, err := root.GetForeignKeyCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	declaredFks, _ := fkc.KeysForTable(tableName)
+
+	var fks []SchemaJSONForeignKey
+	for _, fk := range declaredFks {
+		jsonFk, err := toSchemaJSONForeignKey(ctx, root, fk, sch)
+		if err != nil {
+			return nil, err
+		}
+		fks = append(fks, jsonFk)
+	}
+
+	return json.Marshal(tableSchemaJSON{
+		Version:     TableSchemaJSONVersion,
+		Name:        tableName,
+		Schema:      schJSON,
+		ForeignKeys: fks,
+	})
+}
+
+// toSchemaJSONForeignKey returns the description of |fk|, which is declared by the table with the schema |sch|.
+func toSchemaJSONForeignKey(ctx *sql.Context, root *doltdb.RootValue, fk doltdb.ForeignKey, sch schema.Schema) (SchemaJSONForeignKey, error) {
+	jsonFk := SchemaJSONForeignKey{
+		Name:              fk.Name,
+		Columns:           fk.UnresolvedFKDetails.TableColumns,
+		ReferencedTable:   fk.ReferencedTableName,
+		ReferencedColumns: fk.UnresolvedFKDetails.ReferencedTableColumns,
+		OnUpdate:          string(toReferentialAction(fk.OnUpdate)),
+		OnDelete:          string(toReferentialAction(fk.OnDelete)),
+	}
+	if !fk.IsResolved() {
+		return jsonFk, nil
+	}
+
+	parentSch := sch
+	if !fk.IsSelfReferential() {
+		parentTbl, ok, err := root.GetTable(ctx, fk.ReferencedTableName)
+		if err != nil {
+			return SchemaJSONForeignKey{}, err
+		} else if !ok {
+			return SchemaJSONForeignKey{}, sql.ErrTableNotFound.New(fk.ReferencedTableName)
+		}
+		parentSch, err = parentTbl.GetSchema(ctx)
+		if err != nil {
+			return SchemaJSONForeignKey{}, err
+		}
+	}
+
+	cst, err := toForeignKeyConstraint(fk, "", sch, parentSch)
+	if err != nil {
+		return SchemaJSONForeignKey{}, err
+	}
+	jsonFk.Columns = cst.Columns
+	jsonFk.ReferencedColumns = cst.ParentColumns
+	return jsonFk, nil
+}
+
+// CreateTableFromSchemaJSON creates a table in the working set from a document written by TableSchemaJSON, using the
+// table name, column tags, indexes and foreign keys it describes. The tables referenced by the document's foreign keys
+// must already exist, and the foreign keys are checked the same way as those given to CreateTableComplete. The working
+// root is only written once the table and all its foreign keys have been validated.
+func (db Database) CreateTableFromSchemaJSON(ctx *sql.Context, data []byte) error {
+	if err := dsess.CheckAccessForDb(ctx, db, branch_control.Permissions_Write); err != nil {
+		return err
+	}
+
+	var doc tableSchemaJSON
+	err := json.Unmarshal(data, &doc)
+	if err != nil {
+		return err
+	}
+	if doc.Version < 1 || doc.Version > TableSchemaJSONVersion {
+		return ErrUnsupportedSchemaJSONVersion.New(doc.Version, TableSchemaJSONVersion)
+	}
+
+	tableName := doc.Name
+	if doltdb.HasDoltPrefix(tableName) {
+		return ErrReservedTableName.New(tableName)
+	}
+	if !doltdb.IsValidTableName(tableName) {
+		return ErrInvalidTableName.New(tableName)
+	}
+
+	sch, err := encoding.UnmarshalSchemaFromJSON(doc.Schema)
+	if err != nil {
+		return err
+	}
+	if err = checkNewTableSchema(tableName, sch); err != nil {
+		return err
+	}
+
+	fks := make([]sql.ForeignKeyConstraint, len(doc.ForeignKeys))
+	for i, fk := range doc.ForeignKeys {
+		if fk.ReferencedTable == "" || len(fk.Columns) == 0 || len(fk.Columns) != len(fk.ReferencedColumns) {
+			return ErrInvalidSchemaJSONForeignKey.New(fk.Name)
+		}
+		fks[i] = sql.ForeignKeyConstraint{
+			Name:          fk.Name,
+			Table:         tableName,
+			Columns:       fk.Columns,
+			ParentTable:   fk.ReferencedTable,
+			ParentColumns: fk.ReferencedColumns,
+			OnUpdate:      sql.ForeignKeyReferentialAction(strings.ToUpper(fk.OnUpdate)),
+			OnDelete:      sql.ForeignKeyReferentialAction(strings.ToUpper(fk.OnDelete)),
+		}
+	}
+
+	return db.WithSchemaLock(ctx, func() error {
+		root, err := db.GetRoot(ctx)
+		if err != nil {
+			return err
+		}
+
+		newRoot, err := createEmptyDoltTable(ctx, tableName, root, sch)
+		if err != nil {
+			return err
+		}
+		if len(fks) > 0 {
+			newRoot, err = db.addForeignKeysToNewTable(ctx, newRoot, tableName, sch, fks)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err = db.trackNewTable(ctx, tableName, sch); err != nil {
+			return err
+		}
+
+		return db.SetRoot(ctx, newRoot)
+	})
+}
@@ -387,13 +387,18 @@ func SqlRowAsDeleteStmt(r sql.Row, tableName string, tableSch schema.Schema, lim
 			if seenOne {
 				b.WriteString(" AND ")
 			}
-			sqlString, err := interfaceValueAsSqlString(col.TypeInfo, r[i])
-			if err != nil {
-				return true, err
-			}
 			b.WriteString(QuoteIdentifier(col.Name))
-			b.WriteRune('=')
-			b.WriteString(sqlString)
+			// only keyless rows can have NULL values here, which never compare equal
+			if r[i] == nil {
+				b.WriteString(" IS NULL")
+			} else {
+				sqlString, err := interfaceValueAsSqlString(col.TypeInfo, r[i])
+				if err != nil {
+					return true, err
+				}
+				b.WriteRune('=')
+				b.WriteString(sqlString)
+			}
 			seenOne = true
 		}
 		i++
@@ -69,6 +69,21 @@ func ToDoltSchema(
 	sqlSchema sql.PrimaryKeySchema,
 	headRoot *doltdb.RootValue,
 	collation sql.CollationID,
+) (schema.Schema, error) {
+	return ToDoltSchemaWithTags(ctx, root, tableName, sqlSchema, headRoot, collation, nil)
+}
+
+// ToDoltSchemaWithTags is like ToDoltSchema, but uses the tags given for the columns named in |tags| instead of
+// generating new ones. Column names are matched case-insensitively, and every name in |tags| must match a column.
+// The tags given must be distinct, and columns not named in |tags| are never given one of them.
+func ToDoltSchemaWithTags(
+	ctx context.Context,
+	root *doltdb.RootValue,
+	tableName string,
+	sqlSchema sql.PrimaryKeySchema,
+	headRoot *doltdb.RootValue,
+	collation sql.CollationID,
+	tags map[string]uint64,
 ) (schema.Schema, error) {
 	var cols []schema.Column
 	var err error
@@ -85,17 +100,56 @@ func ToDoltSchema(
 		kinds = append(kinds, ti.NomsKind())
 	}
 
-	tags, err := root.GenerateTagsForNewColumns(ctx, tableName, names, kinds, headRoot)
+	colTags, err := root.GenerateTagsForNewColumns(ctx, tableName, names, kinds, headRoot)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(tags) != len(sqlSchema.Schema) {
+	if len(colTags) != len(sqlSchema.Schema) {
 		return nil, fmt.Errorf("number of tags should equal number of columns")
 	}
 
+	explicit := make(map[int]bool, len(tags))
+	explicitTags := make(map[uint64]string, len(tags))
+	for name, tag := range tags {
+		idx := sqlSchema.Schema.IndexOfColName(name)
+		if idx < 0 {
+			return nil, fmt.Errorf("cannot assign tag %d to column %s: no such column in table %s", tag, name, tableName)
+		}
+		if tag >= schema.ReservedTagMin {
+			return nil, fmt.Errorf("cannot assign tag %d to column %s: tags of %d and above are reserved", tag, name, schema.ReservedTagMin)
+		}
+		if explicit[idx] {
+			return nil, fmt.Errorf("cannot assign more than one tag to column %s", sqlSchema.Schema[idx].Name)
+		}
+		if other, ok := explicitTags[tag]; ok {
+			return nil, fmt.Errorf("cannot assign tag %d to column %s: it's also assigned to column %s", tag, name, other)
+		}
+		explicit[idx] = true
+		explicitTags[tag] = sqlSchema.Schema[idx].Name
+		colTags[idx] = tag
+	}
+
+	// a generated tag may be one that was given for another column, in which case a different one is generated
+	if len(tags) > 0 {
+		existingTags, err := doltdb.GetAllTagsForRoots(ctx, headRoot, root)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range colTags {
+			existingTags.Add(tag, tableName)
+		}
+		for i, col := range sqlSchema.Schema {
+			if _, ok := explicitTags[colTags[i]]; explicit[i] || !ok {
+				continue
+			}
+			colTags[i] = schema.AutoGenerateTag(existingTags, tableName, kinds[:i], col.Name, kinds[i])
+			existingTags.Add(colTags[i], tableName)
+		}
+	}
+
 	for i, col := range sqlSchema.Schema {
-		convertedCol, err := ToDoltCol(tags[i], col)
+		convertedCol, err := ToDoltCol(colTags[i], col)
 		if err != nil {
 			return nil, err
 		}
@@ -15,6 +15,8 @@
 package sqle
 
 import (
+	"math"
+
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
 
@@ -166,6 +168,46 @@ func AddDoltSystemVariables() {
 			Type:              types.NewSystemBoolType(dsess.ShowBranchDatabases),
 			Default:           int8(0),
 		},
+		{
+			Name:              dsess.DiffSummarizeBlobs,
+			Scope:             sql.SystemVariableScope_Both,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(dsess.DiffSummarizeBlobs),
+			Default:           int8(0),
+		},
+		{
+			Name:              dsess.TableCacheSize,
+			Scope:             sql.SystemVariableScope_Both,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemIntType(dsess.TableCacheSize, 0, math.MaxInt32, false),
+			Default:           int64(0),
+		},
+		{
+			Name:              dsess.DisableTableCache,
+			Scope:             sql.SystemVariableScope_Both,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(dsess.DisableTableCache),
+			Default:           int8(0),
+		},
+		{
+			Name:              dsess.DropTableDependents,
+			Scope:             sql.SystemVariableScope_Both,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemEnumType(dsess.DropTableDependents, dsess.DropTableDependentsIgnore, dsess.DropTableDependentsWarn, dsess.DropTableDependentsError),
+			Default:           dsess.DropTableDependentsIgnore,
+		},
+		{
+			Name:              dsess.DiffNullEquivalence,
+			Scope:             sql.SystemVariableScope_Both,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemEnumType(dsess.DiffNullEquivalence, dsess.DiffNullEquivalenceNone, dsess.DiffNullEquivalenceEmptyString, dsess.DiffNullEquivalenceZero, dsess.DiffNullEquivalenceBoth),
+			Default:           dsess.DiffNullEquivalenceNone,
+		},
 		{
 			Name:    dsess.DoltClusterAckWritesTimeoutSecs,
 			Dynamic: true,
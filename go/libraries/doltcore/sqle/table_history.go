@@ -0,0 +1,301 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	storetypes "github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+var ErrColumnHistoryUnsupportedFormat = errors.NewKind("cannot find the commits that changed a column in database %s: its storage format doesn't support it")
+
+// TableCreatedAt returns the commit that introduced the table named, walking back from the session's head commit.
+// Returns ErrTableNotCommitted if the head commit doesn't have the table.
+func (db Database) TableCreatedAt(ctx *sql.Context, tableName string) (*doltdb.Commit, error) {
+	sess := dsess.DSessFromSess(ctx.Session)
+	dbState, ok, err := sess.LookupDbState(ctx, db.RevisionQualifiedName())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("no state for database %s", db.RevisionQualifiedName())
+	}
+
+	cm, err := sess.GetHeadCommit(ctx, db.RevisionQualifiedName())
+	if err != nil {
+		return nil, err
+	}
+	headHash, err := cm.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	if cached, ok := dbState.SessionCache().GetCachedTableCreatedAt(headHash, tableName); ok {
+		return cached, nil
+	}
+
+	root, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, resolvedName, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		workingRoot, err := db.GetRoot(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if _, _, ok, err = workingRoot.GetTableInsensitive(ctx, tableName); err != nil {
+			return nil, err
+		} else if ok {
+			return nil, ErrTableNotCommitted.New(tableName)
+		}
+		return nil, sql.ErrTableNotFound.New(tableName)
+	}
+
+	for {
+		var next *doltdb.Commit
+		for i := 0; i < cm.NumParents(); i++ {
+			parent, err := cm.GetParent(ctx, i)
+			if err != nil {
+				return nil, err
+			}
+			parentRoot, err := parent.GetRootValue(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if ok, err = parentRoot.HasTable(ctx, resolvedName); err != nil {
+				return nil, err
+			} else if ok {
+				next = parent
+				break
+			}
+		}
+		if next == nil {
+			break
+		}
+		cm = next
+	}
+
+	dbState.SessionCache().CacheTableCreatedAt(headHash, tableName, cm)
+	return cm, nil
+}
+
+// DefaultColumnHistoryDepth is the number of commits Database.CommitsTouchingColumn walks when no depth is given.
+const DefaultColumnHistoryDepth = 1000
+
+// CommitsTouchingColumn returns the commits, newest first, that changed the column named in the table named, walking
+// back at most |maxDepth| commits from the session's head commit.
+func (db Database) CommitsTouchingColumn(ctx *sql.Context, tableName, columnName string, maxDepth int) ([]*doltdb.Commit, error) {
+	if !storetypes.IsFormat_DOLT(db.ddb.Format()) {
+		return nil, ErrColumnHistoryUnsupportedFormat.New(db.baseName)
+	}
+	if maxDepth <= 0 {
+		maxDepth = DefaultColumnHistoryDepth
+	}
+
+	sess := dsess.DSessFromSess(ctx.Session)
+	dbState, ok, err := sess.LookupDbState(ctx, db.RevisionQualifiedName())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("no state for database %s", db.RevisionQualifiedName())
+	}
+
+	head, err := sess.GetHeadCommit(ctx, db.RevisionQualifiedName())
+	if err != nil {
+		return nil, err
+	}
+	headHash, err := head.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	if cached, ok := dbState.SessionCache().GetCachedColumnCommits(headHash, tableName, columnName, maxDepth); ok {
+		return cached, nil
+	}
+
+	headRoot, err := head.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok, err := columnOfTable(ctx, headRoot, tableName, columnName); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, sql.ErrTableColumnNotFound.New(tableName, columnName)
+	}
+
+	itr, err := commitwalk.GetTopologicalOrderIterator(ctx, db.ddb, []hash.Hash{headHash}, nil)
+	if err != nil {
+		return nil, err
+	}
+	var commits []*doltdb.Commit
+	for depth := 0; depth < maxDepth; depth++ {
+		_, cm, err := itr.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		root, err := cm.GetRootValue(ctx)
+		if err != nil {
+			return nil, err
+		}
+		touched := false
+		if cm.NumParents() == 0 {
+			_, touched, err = columnOfTable(ctx, root, tableName, columnName)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for i := 0; i < cm.NumParents() && !touched; i++ {
+			parent, err := cm.GetParent(ctx, i)
+			if err != nil {
+				return nil, err
+			}
+			parentRoot, err := parent.GetRootValue(ctx)
+			if err != nil {
+				return nil, err
+			}
+			touched, err = columnChangedBetweenRoots(ctx, parentRoot, root, tableName, columnName)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if touched {
+			commits = append(commits, cm)
+		}
+	}
+
+	dbState.SessionCache().CacheColumnCommits(headHash, tableName, columnName, maxDepth, commits)
+	return commits, nil
+}
+
+// columnOfTable returns the column named in the table named in |root|, and whether both the table and the column
+// exist. Names aren't case-sensitive.
+func columnOfTable(ctx context.Context, root *doltdb.RootValue, tableName, columnName string) (schema.Column, bool, error) {
+	tbl, _, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil || !ok {
+		return schema.Column{}, false, err
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return schema.Column{}, false, err
+	}
+	col, ok := sch.GetAllCols().GetByNameCaseInsensitive(columnName)
+	return col, ok, nil
+}
+
+// errColumnChanged stops the diff of a table's rows once a change to the column is found.
+var errColumnChanged = fmt.Errorf("column changed")
+
+// columnChangedBetweenRoots returns whether the definition of the column named in the table named, or its value in any
+// row, is different in |from| and |to|.
+func columnChangedBetweenRoots(ctx context.Context, from, to *doltdb.RootValue, tableName, columnName string) (bool, error) {
+	fromCol, fromOk, err := columnOfTable(ctx, from, tableName, columnName)
+	if err != nil {
+		return false, err
+	}
+	toCol, toOk, err := columnOfTable(ctx, to, tableName, columnName)
+	if err != nil {
+		return false, err
+	}
+	if !fromOk || !toOk {
+		return fromOk != toOk, nil
+	}
+	if !fromCol.Equals(toCol) || fromCol.Comment != toCol.Comment {
+		return true, nil
+	}
+
+	fromTbl, _, _, err := from.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return false, err
+	}
+	toTbl, _, _, err := to.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return false, err
+	}
+	fromHash, err := fromTbl.GetRowDataHash(ctx)
+	if err != nil {
+		return false, err
+	}
+	toHash, err := toTbl.GetRowDataHash(ctx)
+	if err != nil {
+		return false, err
+	}
+	if fromHash == toHash {
+		return false, nil
+	}
+
+	fromSch, err := fromTbl.GetSchema(ctx)
+	if err != nil {
+		return false, err
+	}
+	toSch, err := toTbl.GetSchema(ctx)
+	if err != nil {
+		return false, err
+	}
+	fromRows, err := fromTbl.GetRowData(ctx)
+	if err != nil {
+		return false, err
+	}
+	toRows, err := toTbl.GetRowData(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// The column has the same type on both sides, so its values are encoded the same way, though they may be in a
+	// different position in the value tuples if other columns changed. A change to a row of a keyless table is a
+	// change to its cardinality, so it always adds or deletes a row.
+	keyless := schema.IsKeyless(toSch)
+	fromIdx := fromSch.GetNonPKCols().TagToIdx[fromCol.Tag]
+	toIdx := toSch.GetNonPKCols().TagToIdx[toCol.Tag]
+	err = prolly.DiffMaps(ctx, durable.ProllyMapFromIndex(fromRows), durable.ProllyMapFromIndex(toRows), func(ctx context.Context, d tree.Diff) error {
+		if d.Type != tree.ModifiedDiff || keyless {
+			return errColumnChanged
+		}
+		if toCol.IsPartOfPK {
+			return nil
+		}
+		if !bytes.Equal(val.Tuple(d.From).GetField(fromIdx), val.Tuple(d.To).GetField(toIdx)) {
+			return errColumnChanged
+		}
+		return nil
+	})
+	if err == errColumnChanged {
+		return true, nil
+	} else if err != nil && err != io.EOF {
+		// DiffMaps returns io.EOF once every change has been visited
+		return false, err
+	}
+	return false, nil
+}
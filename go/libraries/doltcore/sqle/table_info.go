@@ -0,0 +1,105 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+// StorageStats returns statistics about the storage used by this database, such as its size on disk and the size of
+// the chunks written since the last garbage collection. See doltdb.StorageStats.
+func (db Database) StorageStats(ctx *sql.Context) (doltdb.StorageStats, error) {
+	return db.ddb.StorageStats(ctx)
+}
+
+// IsKeylessTable returns whether the table named in the working set has no primary key.
+func (db Database) IsKeylessTable(ctx *sql.Context, tableName string) (bool, error) {
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	tbl, _, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return false, err
+	} else if !ok {
+		return false, sql.ErrTableNotFound.New(tableName)
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return schema.IsKeyless(sch), nil
+}
+
+// TableRowCountApprox returns the number of rows in the table named without scanning it, and whether that count is
+// exact. It's a lower bound for keyless tables.
+func (db Database) TableRowCountApprox(ctx *sql.Context, tableName string) (count uint64, exact bool, err error) {
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+
+	tbl, _, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return 0, false, err
+	} else if !ok {
+		return 0, false, sql.ErrTableNotFound.New(tableName)
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	count, err = rows.Count()
+	if err != nil {
+		return 0, false, err
+	}
+
+	return count, !schema.IsKeyless(sch), nil
+}
+
+// GetPrimaryKeyColumns returns the names of the primary key columns of the table named in the working root.
+func (db Database) GetPrimaryKeyColumns(ctx *sql.Context, tableName string) ([]string, error) {
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, _, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, sql.ErrTableNotFound.New(tableName)
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// keyless tables have no primary key columns, so this is an empty slice for them
+	return sch.GetPKCols().GetColumnNames(), nil
+}
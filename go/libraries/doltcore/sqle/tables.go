@@ -682,31 +682,38 @@ func (t *WritableDoltTable) truncate(
 	sch schema.Schema,
 	sess *dsess.DoltSession,
 ) (*doltdb.Table, error) {
-	empty, err := durable.NewEmptyIndex(ctx, table.ValueReadWriter(), table.NodeStore(), sch)
-	if err != nil {
-		return nil, err
-	}
-
-	idxSet, err := table.GetIndexSet(ctx)
+	ws, err := sess.WorkingSet(ctx, t.db.RevisionQualifiedName())
 	if err != nil {
 		return nil, err
 	}
 
-	for _, idx := range sch.Indexes().AllIndexes() {
-		idxSet, err = idxSet.PutIndex(ctx, idx.Name(), empty)
+	if schema.HasAutoIncrement(sch) {
+		ddb, _ := sess.GetDoltDB(ctx, t.db.RevisionQualifiedName())
+		err = t.db.removeTableFromAutoIncrementTracker(ctx, t.Name(), ddb, ws.Ref())
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	ws, err := sess.WorkingSet(ctx, t.db.RevisionQualifiedName())
+	return emptyTable(ctx, table, sch)
+}
+
+// emptyTable returns a copy of the table given with no rows, with its indexes emptied too. The schema can be updated at
+// the same time. The auto-increment value of the copy is reset, but the conflicts and constraint violations of the
+// table are kept.
+func emptyTable(ctx *sql.Context, table *doltdb.Table, sch schema.Schema) (*doltdb.Table, error) {
+	empty, err := durable.NewEmptyIndex(ctx, table.ValueReadWriter(), table.NodeStore(), sch)
 	if err != nil {
 		return nil, err
 	}
 
-	if schema.HasAutoIncrement(sch) {
-		ddb, _ := sess.GetDoltDB(ctx, t.db.RevisionQualifiedName())
-		err = t.db.removeTableFromAutoIncrementTracker(ctx, t.Name(), ddb, ws.Ref())
+	idxSet, err := table.GetIndexSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, idx := range sch.Indexes().AllIndexes() {
+		idxSet, err = idxSet.PutIndex(ctx, idx.Name(), empty)
 		if err != nil {
 			return nil, err
 		}
@@ -2005,25 +2012,7 @@ func (t *AlterableDoltTable) createIndex(ctx *sql.Context, idx sql.IndexDef, key
 		return err
 	}
 	if ret.OldIndex != nil && ret.OldIndex != ret.NewIndex { // old index was replaced, so we update foreign keys
-		fkc, err := root.GetForeignKeyCollection(ctx)
-		if err != nil {
-			return err
-		}
-		for _, fk := range fkc.AllKeys() {
-			newFk := fk
-			if t.tableName == fk.TableName && fk.TableIndex == ret.OldIndex.Name() {
-				newFk.TableIndex = ret.NewIndex.Name()
-			}
-			if t.tableName == fk.ReferencedTableName && fk.ReferencedTableIndex == ret.OldIndex.Name() {
-				newFk.ReferencedTableIndex = ret.NewIndex.Name()
-			}
-			fkc.RemoveKeys(fk)
-			err = fkc.AddKeys(newFk)
-			if err != nil {
-				return err
-			}
-		}
-		root, err = root.PutForeignKeyCollection(ctx, fkc)
+		root, err = replaceIndexInForeignKeys(ctx, root, t.tableName, ret.OldIndex, ret.NewIndex)
 		if err != nil {
 			return err
 		}
@@ -2041,6 +2030,29 @@ func (t *AlterableDoltTable) createIndex(ctx *sql.Context, idx sql.IndexDef, key
 	return t.updateFromRoot(ctx, newRoot)
 }
 
+// replaceIndexInForeignKeys updates any foreign keys on the table named that use |oldIdx| to use |newIdx| instead.
+func replaceIndexInForeignKeys(ctx *sql.Context, root *doltdb.RootValue, tableName string, oldIdx, newIdx schema.Index) (*doltdb.RootValue, error) {
+	fkc, err := root.GetForeignKeyCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, fk := range fkc.AllKeys() {
+		newFk := fk
+		if tableName == fk.TableName && fk.TableIndex == oldIdx.Name() {
+			newFk.TableIndex = newIdx.Name()
+		}
+		if tableName == fk.ReferencedTableName && fk.ReferencedTableIndex == oldIdx.Name() {
+			newFk.ReferencedTableIndex = newIdx.Name()
+		}
+		fkc.RemoveKeys(fk)
+		err = fkc.AddKeys(newFk)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return root.PutForeignKeyCollection(ctx, fkc)
+}
+
 // createForeignKey creates a doltdb.ForeignKey from a sql.ForeignKeyConstraint
 func (t *AlterableDoltTable) createForeignKey(
 	ctx *sql.Context,
@@ -2048,6 +2060,18 @@ func (t *AlterableDoltTable) createForeignKey(
 	tbl *doltdb.Table,
 	sqlFk sql.ForeignKeyConstraint,
 	onUpdateRefAction, onDeleteRefAction doltdb.ForeignKeyReferentialAction) (doltdb.ForeignKey, error) {
+	return newForeignKey(ctx, root, tbl, t.sch, sqlFk, onUpdateRefAction, onDeleteRefAction)
+}
+
+// newForeignKey creates a doltdb.ForeignKey from a sql.ForeignKeyConstraint declared by the table |tbl|, which has the
+// schema |sch|, in |root|.
+func newForeignKey(
+	ctx *sql.Context,
+	root *doltdb.RootValue,
+	tbl *doltdb.Table,
+	sch schema.Schema,
+	sqlFk sql.ForeignKeyConstraint,
+	onUpdateRefAction, onDeleteRefAction doltdb.ForeignKeyReferentialAction) (doltdb.ForeignKey, error) {
 	if !sqlFk.IsResolved {
 		return doltdb.ForeignKey{
 			Name:                   sqlFk.Name,
@@ -2067,7 +2091,7 @@ func (t *AlterableDoltTable) createForeignKey(
 	}
 	colTags := make([]uint64, len(sqlFk.Columns))
 	for i, col := range sqlFk.Columns {
-		tableCol, ok := t.sch.GetAllCols().GetByNameCaseInsensitive(col)
+		tableCol, ok := sch.GetAllCols().GetByNameCaseInsensitive(col)
 		if !ok {
 			return doltdb.ForeignKey{}, fmt.Errorf("table `%s` does not have column `%s`", sqlFk.Table, col)
 		}
@@ -2078,7 +2102,7 @@ func (t *AlterableDoltTable) createForeignKey(
 	var refSch schema.Schema
 	if sqlFk.IsSelfReferential() {
 		refTbl = tbl
-		refSch = t.sch
+		refSch = sch
 	} else {
 		var ok bool
 		var err error
@@ -2105,7 +2129,7 @@ func (t *AlterableDoltTable) createForeignKey(
 	}
 
 	var tableIndexName, refTableIndexName string
-	tableIndex, ok, err := findIndexWithPrefix(t.sch, sqlFk.Columns)
+	tableIndex, ok, err := findIndexWithPrefix(sch, sqlFk.Columns)
 	if err != nil {
 		return doltdb.ForeignKey{}, err
 	}
@@ -57,6 +57,7 @@ var _ sql.ForeignKeyTable = &TempTable{}
 var _ sql.CheckTable = &TempTable{}
 var _ sql.CheckAlterableTable = &TempTable{}
 var _ sql.StatisticsTable = &TempTable{}
+var _ dsess.SavepointTable = &TempTable{}
 
 func NewTempTable(
 	ctx *sql.Context,
@@ -246,7 +247,17 @@ func (t *TempTable) DataCacheKey(ctx *sql.Context) (doltdb.DataCacheKey, bool, e
 
 func (t *TempTable) LookupPartitions(ctx *sql.Context, lookup sql.IndexLookup) (sql.PartitionIter, error) {
 	t.lookup = lookup
-	return t.Partitions(ctx)
+	// PartitionRows reads every row of an index lookup at once, so it must be given a single partition, or else the
+	// rows are returned once per partition
+	rows, err := t.table.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	count, err := rows.Count()
+	if err != nil {
+		return nil, err
+	}
+	return newDoltTablePartitionIter(rows, doltTablePartition{start: 0, end: count, rowData: rows}), nil
 }
 
 func (t *TempTable) PartitionRows(ctx *sql.Context, partition sql.Partition) (sql.RowIter, error) {
@@ -413,6 +424,34 @@ func (t *TempTable) DropCheck(ctx *sql.Context, chName string) error {
 	return err
 }
 
+// SavepointSnapshot implements dsess.SavepointTable.
+func (t *TempTable) SavepointSnapshot(ctx *sql.Context) (*doltdb.Table, error) {
+	return t.table, nil
+}
+
+// RestoreSavepointSnapshot implements dsess.SavepointTable.
+func (t *TempTable) RestoreSavepointSnapshot(ctx *sql.Context, tbl *doltdb.Table) error {
+	sess := dsess.DSessFromSess(ctx.Session)
+	dbState, ok, err := sess.LookupDbState(ctx, t.dbName)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("database %s not found in session", t.dbName)
+	}
+
+	ws := dbState.WorkingSet()
+	if ws == nil {
+		return doltdb.ErrOperationNotSupportedInDetachedHead
+	}
+	newRoot, err := ws.WorkingRoot().PutTable(ctx, t.tableName, tbl)
+	if err != nil {
+		return err
+	}
+
+	return setTempTableRoot(t)(ctx, t.dbName, newRoot)
+}
+
 func (t *TempTable) Insert(ctx *sql.Context, sqlRow sql.Row) error {
 	return t.ed.Insert(ctx, sqlRow)
 }
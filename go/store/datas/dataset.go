@@ -166,6 +166,7 @@ type MergeState struct {
 	fromCommitSpec      string
 	unmergableTables    []string
 	isCherryPick        bool
+	mergeMessage        string
 
 	nomsMergeStateRef *types.Ref
 	nomsMergeState    *types.Struct
@@ -264,6 +265,15 @@ func (ms *MergeState) IsCherryPick(_ context.Context, vr types.ValueReader) (boo
 	return false, nil
 }
 
+// MergeMessage returns the message suggested for the commit that completes this merge, or the empty string if there
+// isn't one. Merge messages are only stored in the flatbuffers format.
+func (ms *MergeState) MergeMessage(_ context.Context, vr types.ValueReader) (string, error) {
+	if vr.Format().UsesFlatbuffers() {
+		return ms.mergeMessage, nil
+	}
+	return "", nil
+}
+
 func (ms *MergeState) UnmergableTables(ctx context.Context, vr types.ValueReader) ([]string, error) {
 	if vr.Format().UsesFlatbuffers() {
 		return ms.unmergableTables, nil
@@ -390,6 +400,7 @@ func (h serialWorkingSetHead) HeadWorkingSet() (*WorkingSetHead, error) {
 			ret.MergeState.unmergableTables[i] = string(mergeState.UnmergableTables(i))
 		}
 		ret.MergeState.isCherryPick = mergeState.IsCherryPick()
+		ret.MergeState.mergeMessage = string(mergeState.MergeMessage())
 	}
 	return &ret, nil
 }
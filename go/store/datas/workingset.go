@@ -163,12 +163,19 @@ func workingset_flatbuffer(working hash.Hash, staged *hash.Hash, mergeState *Mer
 		fromaddroff := builder.CreateByteVector((*mergeState.fromCommitAddr)[:])
 		fromspecoff := builder.CreateString(mergeState.fromCommitSpec)
 		unmergableoff := SerializeStringVector(builder, mergeState.unmergableTables)
+		var msgoff flatbuffers.UOffsetT
+		if mergeState.mergeMessage != "" {
+			msgoff = builder.CreateString(mergeState.mergeMessage)
+		}
 		serial.MergeStateStart(builder)
 		serial.MergeStateAddPreWorkingRootAddr(builder, prerootaddroff)
 		serial.MergeStateAddFromCommitAddr(builder, fromaddroff)
 		serial.MergeStateAddFromCommitSpecStr(builder, fromspecoff)
 		serial.MergeStateAddUnmergableTables(builder, unmergableoff)
 		serial.MergeStateAddIsCherryPick(builder, mergeState.isCherryPick)
+		if msgoff != 0 {
+			serial.MergeStateAddMergeMessage(builder, msgoff)
+		}
 		mergeStateOff = serial.MergeStateEnd(builder)
 	}
 
@@ -204,6 +211,7 @@ func NewMergeState(
 	commitSpecStr string,
 	unmergableTables []string,
 	isCherryPick bool,
+	mergeMessage string,
 ) (*MergeState, error) {
 	if vrw.Format().UsesFlatbuffers() {
 		ms := &MergeState{
@@ -212,6 +220,7 @@ func NewMergeState(
 			fromCommitSpec:      commitSpecStr,
 			unmergableTables:    unmergableTables,
 			isCherryPick:        isCherryPick,
+			mergeMessage:        mergeMessage,
 		}
 		*ms.preMergeWorkingAddr = preMergeWorking.TargetHash()
 		*ms.fromCommitAddr = commit.Addr()
@@ -38,7 +38,9 @@ type ThreeWayDiffer[K ~[]byte, O Ordering[K]] struct {
 
 //var _ DiffIter = (*threeWayDiffer[Item, val.TupleDesc])(nil)
 
-type resolveCb func(val.Tuple, val.Tuple, val.Tuple) (val.Tuple, bool)
+// resolveCb attempts to merge the values of a key that was changed differently on the left and right sides, given
+// the left, right and base values. It returns the merged value and true, or false if the changes conflict.
+type resolveCb func(ctx context.Context, left, right, base val.Tuple) (val.Tuple, bool, error)
 
 func NewThreeWayDiffer[K, V ~[]byte, O Ordering[K]](
 	ctx context.Context,
@@ -165,7 +167,10 @@ func (d *ThreeWayDiffer[K, O]) Next(ctx context.Context) (ThreeWayDiff, error) {
 			} else if d.lDiff.Type == d.rDiff.Type && bytes.Equal(d.lDiff.To, d.rDiff.To) {
 				res = d.newConvergentEdit(d.lDiff.Key, d.lDiff.To, d.lDiff.Type)
 			} else {
-				resolved, ok := d.resolveCb(val.Tuple(d.lDiff.To), val.Tuple(d.rDiff.To), val.Tuple(d.lDiff.From))
+				resolved, ok, err := d.resolveCb(ctx, val.Tuple(d.lDiff.To), val.Tuple(d.rDiff.To), val.Tuple(d.lDiff.From))
+				if err != nil {
+					return ThreeWayDiff{}, err
+				}
 				if !ok {
 					res = d.newDivergentClashConflict(d.lDiff.Key, d.lDiff.From, d.lDiff.To, d.rDiff.To)
 				} else {
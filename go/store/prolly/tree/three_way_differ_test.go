@@ -215,8 +215,8 @@ func TestThreeWayDiffer(t *testing.T) {
 	}
 }
 
-func testResolver(t *testing.T, ns NodeStore, valDesc val.TupleDesc, valBuilder *val.TupleBuilder) func(val.Tuple, val.Tuple, val.Tuple) (val.Tuple, bool) {
-	return func(l, r, b val.Tuple) (val.Tuple, bool) {
+func testResolver(t *testing.T, ns NodeStore, valDesc val.TupleDesc, valBuilder *val.TupleBuilder) resolveCb {
+	return func(_ context.Context, l, r, b val.Tuple) (val.Tuple, bool, error) {
 		for i := range valDesc.Types {
 			var base, left, right int64
 			var ok bool
@@ -236,7 +236,7 @@ func testResolver(t *testing.T, ns NodeStore, valDesc val.TupleDesc, valBuilder
 			}
 
 			if base != left && base != right && left != right {
-				return nil, false
+				return nil, false, nil
 			} else if base != left {
 				valBuilder.PutInt64(i, left)
 			} else if base != right {
@@ -245,7 +245,7 @@ func testResolver(t *testing.T, ns NodeStore, valDesc val.TupleDesc, valBuilder
 				valBuilder.PutInt64(i, base)
 			}
 		}
-		return valBuilder.Build(ns.Pool()), true
+		return valBuilder.Build(ns.Pool()), true, nil
 	}
 }
 
@@ -53,7 +53,7 @@ func Start() *Pager {
 	// otherwise, it must be always false.
 	if !testing {
 		if noPager || !IsStdoutTty() {
-			return &Pager{os.Stdout, nil, nil, nil, nil}
+			return StartUnpaged()
 		}
 	}
 
@@ -111,6 +111,12 @@ func Start() *Pager {
 	return p
 }
 
+// StartUnpaged returns a Pager that writes straight to stdout, for commands that let paging be turned off per
+// invocation.
+func StartUnpaged() *Pager {
+	return &Pager{os.Stdout, nil, nil, nil, nil}
+}
+
 func (p *Pager) Stop() {
 	if p.Writer != os.Stdout {
 		p.closePipe()
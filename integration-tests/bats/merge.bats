@@ -274,6 +274,30 @@ SQL
     dolt commit --force -am "force commit with conflicts"
 }
 
+@test "merge: --show-conflicts prints conflicting rows" {
+    dolt checkout -b merge_branch
+    dolt sql -q "INSERT INTO test1 values (0,1,1)"
+    dolt commit -am "add pk 0 = 1,1 to test1"
+
+    dolt checkout main
+    dolt sql -q "INSERT INTO test1 values (0,2,2)"
+    dolt commit -am "add pk 0 = 2,2 to test1"
+
+    run dolt merge merge_branch -m "merge_branch"
+    log_status_eq 0
+    [[ "$output" =~ "CONFLICT (content): Merge conflict in test1" ]] || false
+    [[ ! "$output" =~ "Conflicts in test1" ]] || false
+
+    dolt merge --abort
+
+    run dolt merge --show-conflicts merge_branch -m "merge_branch"
+    log_status_eq 0
+    [[ "$output" =~ "CONFLICT (content): Merge conflict in test1" ]] || false
+    [[ "$output" =~ "Conflicts in test1" ]] || false
+    [[ "$output" =~ "our_c1" ]] || false
+    [[ "$output" =~ "their_c1" ]] || false
+}
+
 @test "merge: dolt commit fails with unmerged tables in working set" {
     dolt checkout -b merge_branch
     dolt SQL -q "INSERT INTO test1 values (0,1,1)"
@@ -1043,6 +1067,23 @@ SQL
     [[ "$output" =~ "$regex" ]] || false
 }
 
+@test "merge: specify --author for a --no-ff merge that's used for creating commit" {
+    dolt branch other
+    dolt checkout other
+    dolt sql -q "INSERT INTO test1 VALUES (2,3,4)"
+    dolt commit -am "add (2,3,4) to test1";
+
+    dolt checkout main
+    run dolt merge other --no-ff --author "John Doe <john@doe.com>" -m "merge other"
+    log_status_eq 0
+
+    run dolt log -n 1
+    [ "$status" -eq 0 ]
+    regex='John Doe <john@doe.com>'
+    [[ "$output" =~ "$regex" ]] || false
+    [[ ! "$output" =~ "Bats Tests" ]] || false
+}
+
 @test "merge: prints merge stats" {
     dolt sql -q "CREATE table t (pk int primary key, col1 int);"
     dolt sql -q "CREATE table t2 (pk int primary key);"
@@ -1086,3 +1127,206 @@ SQL
     [ $status -eq 0 ]
     [[ "$output" =~ "2 tables changed, 3 rows added(+), 1 rows modified(*), 1 rows deleted(-)" ]] || false
 }
+
+@test "merge: --quiet and --no-pager control printing the merge commit" {
+    dolt sql -q "CREATE table t (pk int primary key, col1 int);"
+    dolt commit -Am "add table t"
+
+    dolt checkout -b right
+    dolt sql -q "insert into t values (1, 1);"
+    dolt commit -Am "right"
+
+    dolt checkout main
+    dolt sql -q "insert into t values (2, 2);"
+    dolt commit -Am "left"
+    dolt branch main2
+
+    run dolt merge right -m "merge right into main" --quiet
+    [ $status -eq 0 ]
+    [[ "$output" =~ "1 tables changed, 1 rows added(+)" ]] || false
+    [[ ! "$output" =~ "merge right into main" ]] || false
+
+    dolt checkout main2
+    run dolt merge right -m "merge right into main2" --no-pager
+    [ $status -eq 0 ]
+    [[ "$output" =~ "merge right into main2" ]] || false
+    [[ "$output" =~ "1 tables changed, 1 rows added(+)" ]] || false
+}
+
+@test "merge: --allow-empty with --no-ff creates a merge commit" {
+    dolt checkout -b other
+    dolt commit --allow-empty -m "empty commit"
+    dolt checkout main
+
+    run dolt merge other --allow-empty -m "merge other"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "requires '--no-ff'" ]] || false
+
+    run dolt merge other --no-ff --allow-empty -m "merge other"
+    [ $status -eq 0 ]
+
+    run dolt log -n 1
+    [ $status -eq 0 ]
+    [[ "$output" =~ "merge other" ]] || false
+    [[ "$output" =~ "Merge:" ]] || false
+}
+
+@test "merge: --include-system-tables includes dolt_docs in the merge summary" {
+    dolt sql -q "CREATE table t (pk int primary key, col1 int);"
+    dolt commit -Am "add table t"
+
+    dolt checkout -b right
+    dolt sql -q "insert into t values (1, 1);"
+    dolt sql -q "insert into dolt_docs values ('README.md', 'a readme');"
+    dolt commit -Am "right"
+
+    dolt checkout main
+    dolt sql -q "insert into t values (2, 2);"
+    dolt commit -Am "left"
+    dolt branch main2
+
+    run dolt merge right -m "merge right into main" --quiet
+    [ $status -eq 0 ]
+    [[ "$output" =~ "1 tables changed, 1 rows added(+)" ]] || false
+    [[ ! "$output" =~ "dolt_docs" ]] || false
+
+    dolt checkout main2
+    run dolt merge right -m "merge right into main2" --quiet --include-system-tables
+    [ $status -eq 0 ]
+    [[ "$output" =~ "dolt_docs added" ]] || false
+    [[ "$output" =~ "1 tables changed, 1 rows added(+)" ]] || false
+}
+
+@test "merge: --summary-line prints a single line summary" {
+    dolt sql -q "CREATE table t (pk int primary key, col1 int);"
+    dolt sql -q "insert into t values (1, 1), (2, 2);"
+    dolt commit -Am "add table t"
+
+    dolt checkout -b right
+    dolt sql -q "insert into t values (3, 3), (4, 4);"
+    dolt sql -q "update t set col1 = 10 where pk = 1;"
+    dolt sql -q "delete from t where pk = 2;"
+    dolt commit -Am "right"
+
+    dolt checkout main
+    dolt sql -q "CREATE table t2 (pk int primary key);"
+    dolt commit -Am "left"
+
+    run dolt merge right -m "merge right" --summary-line
+    [ $status -eq 0 ]
+    [[ "$output" =~ "merged right into main: 1 tables, +2 -1 *1, 0 conflicts" ]] || false
+    [[ ! "$output" =~ "tables changed" ]] || false
+    [[ ! "$output" =~ "Merge:" ]] || false
+
+    dolt checkout -b conflicts HEAD~1
+    dolt sql -q "update t set col1 = 20 where pk = 1;"
+    dolt commit -Am "conflicting change"
+
+    run dolt merge right --summary-line
+    [ $status -eq 0 ]
+    [[ "$output" =~ "merged right into conflicts: 1 tables, +0 -0 *0, 1 conflicts" ]] || false
+}
+
+@test "merge: --no-gc-hint keeps the merge inputs from being collected" {
+    dolt sql -q "CREATE table t (pk int primary key, col1 int);"
+    dolt sql -q "insert into t values (1, 1);"
+    dolt commit -Am "add table t"
+    base=$(dolt sql -q "select hashof('main')" -r csv | tail -n1)
+
+    dolt checkout -b right
+    dolt sql -q "insert into t values (2, 2);"
+    dolt commit -Am "right"
+    theirs=$(dolt sql -q "select hashof('right')" -r csv | tail -n1)
+
+    dolt checkout main
+    dolt sql -q "insert into t values (3, 3);"
+    dolt commit -Am "left"
+    ours=$(dolt sql -q "select hashof('main')" -r csv | tail -n1)
+
+    dolt merge right -m "merge right" --no-gc-hint
+
+    run dolt branch --datasets
+    [ $status -eq 0 ]
+    [[ "$output" =~ "refs/internal/merge/$ours/$theirs/base" ]] || false
+    [[ "$output" =~ "refs/internal/merge/$ours/$theirs/ours" ]] || false
+    [[ "$output" =~ "refs/internal/merge/$ours/$theirs/theirs" ]] || false
+
+    dolt reset --hard $ours
+    dolt branch -D right
+    dolt gc
+
+    run dolt show $theirs
+    [ $status -eq 0 ]
+    [[ "$output" =~ "right" ]] || false
+    run dolt show $base
+    [ $status -eq 0 ]
+}
+
+@test "merge: merge inputs are not kept without --no-gc-hint" {
+    dolt sql -q "CREATE table t (pk int primary key, col1 int);"
+    dolt commit -Am "add table t"
+    dolt checkout -b right
+    dolt sql -q "insert into t values (2, 2);"
+    dolt commit -Am "right"
+    dolt checkout main
+    dolt sql -q "insert into t values (3, 3);"
+    dolt commit -Am "left"
+
+    dolt merge right -m "merge right"
+
+    run dolt branch --datasets
+    [ $status -eq 0 ]
+    [[ ! "$output" =~ "refs/internal/merge" ]] || false
+}
+
+@test "merge: reports rows merged automatically from changes to different columns" {
+    dolt sql -q "CREATE table t (pk int primary key, c1 int, c2 int);"
+    dolt sql -q "insert into t values (1, 1, 1), (2, 2, 2), (3, 3, 3);"
+    dolt commit -Am "add table t"
+
+    dolt checkout -b right
+    dolt sql -q "update t set c2 = 20 where pk in (1, 2);"
+    dolt commit -Am "right"
+
+    dolt checkout main
+    dolt sql -q "update t set c1 = 10 where pk in (1, 2);"
+    dolt sql -q "insert into t values (4, 4, 4);"
+    dolt commit -Am "left"
+
+    run dolt merge right -m "merge right"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "2 of the modified rows were changed on both sides and merged automatically" ]] || false
+
+    dolt reset --hard HEAD~1
+    run dolt merge right -m "merge right" --summary-line
+    [ $status -eq 0 ]
+    [[ "$output" =~ ", 2 rows auto-merged" ]] || false
+}
+
+@test "merge: --conflict-branch saves a conflicted merge to a new branch" {
+    dolt checkout -b right
+    dolt sql -q "insert into test1 values (1, 10, 10);"
+    dolt commit -am "right"
+
+    dolt checkout main
+    dolt sql -q "insert into test1 values (1, 100, 100);"
+    dolt commit -am "left"
+
+    dolt branch taken
+    run dolt merge right --conflict-branch taken
+    [ $status -eq 1 ]
+    [[ "$output" =~ "A branch named 'taken' already exists" ]] || false
+
+    run dolt merge right --conflict-branch right-conflicts
+    [ $status -eq 0 ]
+    [[ "$output" =~ "the merge was saved to branch 'right-conflicts'" ]] || false
+
+    run dolt status
+    [ $status -eq 0 ]
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+
+    dolt checkout right-conflicts
+    run dolt sql -q "select our_c1, their_c1 from dolt_conflicts_test1" -r csv
+    [ $status -eq 0 ]
+    [[ "$output" =~ "100,10" ]] || false
+}
@@ -196,6 +196,38 @@ SQL
     [ "${#lines[@]}" -eq 1 ]
 }
 
+@test "system-tables: filter dolt_remote_branches by remote" {
+    dolt branch b1
+    dolt branch b2
+    mkdir ./remote1 ./remote2
+    dolt remote add rem1 file://./remote1
+    dolt remote add rem2 file://./remote2
+    dolt push rem1 b1
+    dolt push rem2 b2
+
+    run dolt sql -q "select name, remote from dolt_remote_branches order by name" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "remotes/rem1/b1,rem1" ]] || false
+    [[ "$output" =~ "remotes/rem2/b2,rem2" ]] || false
+
+    run dolt sql -q "select name from dolt_remote_branches where remote = 'rem1'" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "remotes/rem1/b1" ]] || false
+    [[ ! "$output" =~ "remotes/rem2/b2" ]] || false
+
+    run dolt sql -q "select name from dolt_remote_branches where remote in ('rem1', 'rem2')" -r csv
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 3 ]
+
+    run dolt sql -q "select name from dolt_remote_branches where remote = 'nosuchremote'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 1 ]
+
+    run dolt sql -q "explain select name from dolt_remote_branches where remote = 'rem1'"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "IndexedTableAccess" ]] || false
+}
+
 @test "system-tables: query dolt_remotes system table" {
     run dolt sql -q "select count(*) from dolt_remotes" -r csv
     [ $status -eq 0 ]
@@ -630,6 +662,21 @@ SQL
     [[ "$output" =~ "tag v3 from branch1" ]] || false
 }
 
+@test "system-tables: query dolt_storage_stats" {
+    dolt sql -q "CREATE TABLE test(pk int primary key, val int)"
+    dolt sql -q "INSERT INTO test VALUES (1,1), (2,2)"
+    dolt commit -Am "cm1"
+
+    run dolt sql -q "SELECT chunk_count > 0, total_bytes > 0, new_gen_bytes <= total_bytes FROM dolt_storage_stats" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "true,true,true" ]] || false
+
+    dolt gc
+    run dolt sql -q "SELECT new_gen_bytes < total_bytes FROM dolt_storage_stats" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "true" ]] || false
+}
+
 @test "system-tables: query dolt_schema_diff" {
 			dolt sql <<SQL
 call dolt_checkout('-b', 'branch1');